@@ -0,0 +1,375 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/avor0n/dependency-graph-visualizer/config"
+	"github.com/avor0n/dependency-graph-visualizer/export"
+	"github.com/avor0n/dependency-graph-visualizer/models"
+	"github.com/avor0n/dependency-graph-visualizer/sarif"
+)
+
+// runAnalyze выводит граф (или подграф файла) в формате JSON
+func runAnalyze(args []string, stdout, stderr io.Writer) int {
+	flags, projectPath := newFlagSet("analyze", stderr)
+	file := flags.String("file", "", "Вывести только зависимости указанного файла")
+	if _, code, ok := parseFlags(flags, projectPath, args); !ok {
+		return code
+	}
+
+	project, err := loadProject(*projectPath, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, "Ошибка:", err)
+		return exitError
+	}
+
+	return writeJSON(stdout, stderr, project.dependencyService.GetFileDependencies(*file))
+}
+
+// runExport выводит граф в одном из текстовых форматов
+func runExport(args []string, stdout, stderr io.Writer) int {
+	flags, projectPath := newFlagSet("export", stderr)
+	format := flags.String("format", "dot", "Формат: "+strings.Join(export.Formats(), ", "))
+	file := flags.String("file", "", "Вывести только зависимости указанного файла")
+	dir := flags.String("dir", "", "Вывести только зависимости констант из указанной директории")
+	output := flags.String("output", "", "Файл для результата (по умолчанию stdout)")
+	if _, code, ok := parseFlags(flags, projectPath, args); !ok {
+		return code
+	}
+
+	exporter, ok := export.Lookup(*format)
+	if !ok {
+		fmt.Fprintf(stderr, "Ошибка: неизвестный формат %q, поддерживаются: %s\n", *format, strings.Join(export.Formats(), ", "))
+		return exitUsage
+	}
+	if *file != "" && *dir != "" {
+		fmt.Fprintln(stderr, "Ошибка: флаги -file и -dir нельзя использовать одновременно")
+		return exitUsage
+	}
+
+	project, err := loadProject(*projectPath, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, "Ошибка:", err)
+		return exitError
+	}
+
+	graph := project.dependencyService.GetFileDependencies(*file)
+	if *dir != "" {
+		graph = export.FilterDirectory(graph, *dir)
+	}
+
+	if err := writeOutput(*output, stdout, func(w io.Writer) error { return exporter.Write(w, graph) }); err != nil {
+		fmt.Fprintln(stderr, "Ошибка экспорта:", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// runCycles выводит циклические зависимости и возвращает код 1, если они найдены
+func runCycles(args []string, stdout, stderr io.Writer) int {
+	flags, projectPath := newFlagSet("cycles", stderr)
+	asJSON := flags.Bool("json", false, "Вывести отчет в формате JSON")
+	asSARIF := flags.Bool("sarif", false, "Вывести отчет в формате SARIF 2.1.0")
+	if _, code, ok := parseFlags(flags, projectPath, args); !ok {
+		return code
+	}
+	if *asJSON && *asSARIF {
+		fmt.Fprintln(stderr, "Ошибка: флаги -json и -sarif нельзя использовать одновременно")
+		return exitUsage
+	}
+
+	project, err := loadProject(*projectPath, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, "Ошибка:", err)
+		return exitError
+	}
+
+	report := project.dependencyService.FindCycles()
+	switch {
+	case *asSARIF:
+		if code := writeJSON(stdout, stderr, sarif.FromCycles(report, project.fileService.ProjectPath)); code != exitOK {
+			return code
+		}
+	case *asJSON:
+		if code := writeJSON(stdout, stderr, report); code != exitOK {
+			return code
+		}
+	default:
+		printCycles(stdout, report)
+	}
+
+	if report.HasCycles() {
+		return exitError
+	}
+	return exitOK
+}
+
+// runStats выводит сводные показатели графа
+func runStats(args []string, stdout, stderr io.Writer) int {
+	flags, projectPath := newFlagSet("stats", stderr)
+	asJSON := flags.Bool("json", false, "Вывести показатели в формате JSON")
+	if _, code, ok := parseFlags(flags, projectPath, args); !ok {
+		return code
+	}
+
+	project, err := loadProject(*projectPath, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, "Ошибка:", err)
+		return exitError
+	}
+
+	stats := project.dependencyService.Stats()
+	if *asJSON {
+		return writeJSON(stdout, stderr, stats)
+	}
+
+	printStats(stdout, stats)
+	return exitOK
+}
+
+// runQuery выводит константы, транзитивно зависящие от константы
+// (или от которых она зависит). Константа задается идентификатором или именем.
+func runQuery(args []string, stdout, stderr io.Writer) int {
+	flags, projectPath := newFlagSet("query", stderr)
+	node := flags.String("node", "", "Идентификатор (src/config.ts#API_URL) или имя константы")
+	direction := flags.String("direction", models.ImpactUp, "Направление: up - зависящие константы, down - зависимости")
+	depth := flags.Int("depth", 0, "Ограничение глубины обхода (0 - без ограничения)")
+	asJSON := flags.Bool("json", false, "Вывести результат в формате JSON")
+	rest, code, ok := parseFlags(flags, projectPath, args)
+	if !ok {
+		return code
+	}
+	if *node == "" && len(rest) > 0 {
+		*node = rest[0]
+	}
+	if *node == "" {
+		fmt.Fprintln(stderr, "Ошибка: необходимо указать константу с помощью флага -node")
+		return exitUsage
+	}
+
+	project, err := loadProject(*projectPath, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, "Ошибка:", err)
+		return exitError
+	}
+
+	matches := project.dependencyService.FindNodes(*node)
+	switch {
+	case len(matches) == 0:
+		fmt.Fprintf(stderr, "Ошибка: константа %q не найдена\n", *node)
+		return exitError
+	case len(matches) > 1:
+		fmt.Fprintf(stderr, "Ошибка: имя %q неоднозначно, укажите идентификатор:\n", *node)
+		for _, match := range matches {
+			fmt.Fprintf(stderr, "  %s\n", match.ID)
+		}
+		return exitUsage
+	}
+
+	impact, err := project.dependencyService.GetImpact(matches[0].ID, *direction, *depth)
+	if err != nil {
+		fmt.Fprintln(stderr, "Ошибка:", err)
+		return exitUsage
+	}
+
+	if *asJSON {
+		return writeJSON(stdout, stderr, impact)
+	}
+
+	printImpact(stdout, impact)
+	return exitOK
+}
+
+// runCheck проверяет правила зависимостей из конфигурации проекта и возвращает
+// код 1, если найдены нарушения с важностью error
+func runCheck(args []string, stdout, stderr io.Writer) int {
+	flags, projectPath := newFlagSet("check", stderr)
+	asJSON := flags.Bool("json", false, "Вывести отчет в формате JSON")
+	asSARIF := flags.Bool("sarif", false, "Вывести отчет в формате SARIF 2.1.0")
+	if _, code, ok := parseFlags(flags, projectPath, args); !ok {
+		return code
+	}
+	if *asJSON && *asSARIF {
+		fmt.Fprintln(stderr, "Ошибка: флаги -json и -sarif нельзя использовать одновременно")
+		return exitUsage
+	}
+
+	project, err := loadProject(*projectPath, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, "Ошибка:", err)
+		return exitError
+	}
+
+	report, err := project.dependencyService.CheckRules(project.config.Rules)
+	if err != nil {
+		fmt.Fprintln(stderr, "Ошибка:", err)
+		return exitError
+	}
+
+	switch {
+	case *asSARIF:
+		sarifLog := sarif.FromViolations(report, project.config.Rules, project.fileService.ProjectPath)
+		if code := writeJSON(stdout, stderr, sarifLog); code != exitOK {
+			return code
+		}
+	case *asJSON:
+		if code := writeJSON(stdout, stderr, report); code != exitOK {
+			return code
+		}
+	default:
+		printViolations(stdout, report)
+	}
+
+	if report.Failed() {
+		return exitError
+	}
+	return exitOK
+}
+
+// writeJSON выводит значение в формате JSON с отступами
+func writeJSON(stdout, stderr io.Writer, value interface{}) int {
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		fmt.Fprintln(stderr, "Ошибка вывода:", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// writeOutput передает функции write файл output или stdout, если output пуст
+func writeOutput(output string, stdout io.Writer, write func(w io.Writer) error) error {
+	if output == "" {
+		return write(stdout)
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// printCycles выводит отчет о циклических зависимостях в текстовом виде
+func printCycles(w io.Writer, report models.CycleReport) {
+	if !report.HasCycles() {
+		fmt.Fprintln(w, "Циклические зависимости не найдены")
+		return
+	}
+
+	sections := []struct {
+		title  string
+		cycles []models.Cycle
+	}{
+		{"между модулями", report.Modules},
+		{"между константами", report.Constants},
+	}
+
+	for _, section := range sections {
+		if len(section.cycles) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "Найдено циклов %s: %d\n", section.title, len(section.cycles))
+		for i, cycle := range section.cycles {
+			fmt.Fprintf(w, "\nЦикл %d (%d узлов):\n", i+1, len(cycle.Nodes))
+			for j, edge := range cycle.Edges {
+				fmt.Fprintf(w, "  %s -> %s", edge.Source, edge.Target)
+				if j < len(cycle.Locations) {
+					location := cycle.Locations[j]
+					fmt.Fprintf(w, " (%s:%d:%d)", location.File, location.Line, location.Column)
+				}
+				fmt.Fprintln(w)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// printStats выводит сводные показатели графа в текстовом виде
+func printStats(w io.Writer, stats models.GraphStats) {
+	fmt.Fprintf(w, "Файлов:      %d\n", stats.Files)
+	fmt.Fprintf(w, "Констант:    %d\n", stats.Constants)
+	fmt.Fprintf(w, "Зависимостей: %d\n", stats.Dependencies)
+	fmt.Fprintf(w, "Циклов между модулями:   %d\n", stats.ModuleCycles)
+	fmt.Fprintf(w, "Циклов между константами: %d\n", stats.ConstantCycles)
+
+	printCounts := func(title string, counts map[string]int) {
+		if len(counts) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\n%s:\n", title)
+		keys := make([]string, 0, len(counts))
+		for key := range counts {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(w, "  %-10s %d\n", key, counts[key])
+		}
+	}
+	printCounts("Символы по видам", stats.SymbolsByKind)
+	printCounts("Константы по типам", stats.ConstantsByType)
+	printCounts("Зависимости по видам", stats.DependenciesByKind)
+
+	if len(stats.MostDependedOn) > 0 {
+		fmt.Fprintln(w, "\nЧаще всего используются:")
+		for _, degree := range stats.MostDependedOn {
+			fmt.Fprintf(w, "  %4d  %s\n", degree.Dependents, degree.ID)
+		}
+	}
+
+	if len(stats.UnreachableFiles) > 0 {
+		fmt.Fprintln(w, "\nНе достижимы из точек входа:")
+		for _, file := range stats.UnreachableFiles {
+			fmt.Fprintf(w, "  %s\n", file)
+		}
+	}
+}
+
+// printImpact выводит достижимые константы с расстоянием до них
+func printImpact(w io.Writer, impact models.ImpactGraph) {
+	title := "Константы, зависящие от"
+	if impact.Direction == models.ImpactDown {
+		title = "Зависимости константы"
+	}
+	fmt.Fprintf(w, "%s %s: %d\n", title, impact.Root, len(impact.Nodes)-1)
+
+	for _, node := range impact.Nodes {
+		if node.ID == impact.Root {
+			continue
+		}
+		fmt.Fprintf(w, "  %2d  %s\n", node.Distance, node.ID)
+	}
+}
+
+// printViolations выводит нарушения правил в формате "файл:строка:колонка: важность [правило] ..."
+func printViolations(w io.Writer, report models.ViolationReport) {
+	if report.Rules == 0 {
+		fmt.Fprintf(w, "Правила зависимостей не заданы в %s\n", config.FileName)
+		return
+	}
+	if len(report.Violations) == 0 {
+		fmt.Fprintf(w, "Нарушений правил не найдено (проверено правил: %d)\n", report.Rules)
+		return
+	}
+
+	for _, violation := range report.Violations {
+		fmt.Fprintf(w, "%s:%d:%d: %s [%s] %s -> %s", violation.File, violation.Line, violation.Column,
+			violation.Severity, violation.Rule, violation.Source, violation.Target)
+		if violation.Message != "" {
+			fmt.Fprintf(w, ": %s", violation.Message)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "\nНайдено нарушений: %d (ошибок: %d, предупреждений: %d)\n",
+		len(report.Violations), report.Errors, report.Warnings)
+}
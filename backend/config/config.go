@@ -0,0 +1,438 @@
+// Package config читает настройки анализатора из файла .depgraph.json в корне проекта.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/avor0n/dependency-graph-visualizer/utils"
+)
+
+// FileName - имя файла конфигурации в корне проекта
+const FileName = ".depgraph.json"
+
+// Значения по умолчанию
+const (
+	DefaultWorkers = 10
+	DefaultPort    = 8080
+)
+
+// DefaultExtensions содержит расширения анализируемых файлов по умолчанию
+var DefaultExtensions = []string{".js", ".jsx", ".ts", ".tsx"}
+
+// DefaultExclude содержит пропускаемые по умолчанию пути: node_modules и скрытые директории
+var DefaultExclude = []string{"node_modules/", ".*/"}
+
+// Уровни важности правил
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Config содержит настройки анализатора
+type Config struct {
+	// Include ограничивает анализ файлами, совпадающими с шаблонами (пусто - все файлы)
+	Include []string `json:"include,omitempty"`
+	// Exclude исключает файлы и директории из анализа
+	Exclude []string `json:"exclude"`
+	// Extensions содержит расширения анализируемых файлов
+	Extensions []string `json:"extensions"`
+	// Workers ограничивает число файлов, анализируемых одновременно
+	Workers int `json:"workers"`
+	// Port - порт веб-сервера, 0 - выбрать свободный (флаг -port и DEPGRAPH_PORT важнее)
+	Port int `json:"port"`
+	// Aliases сопоставляет спецификаторы импортов с путями относительно
+	// корня проекта, как compilerOptions.paths в tsconfig.json
+	Aliases map[string][]string `json:"aliases,omitempty"`
+	// EntryPoints - точки входа приложения относительно корня проекта
+	EntryPoints []string `json:"entryPoints,omitempty"`
+	// Rules - правила допустимых зависимостей между частями проекта
+	Rules []Rule `json:"rules,omitempty"`
+
+	include *utils.PatternSet
+	exclude *utils.PatternSet
+}
+
+// Rule запрещает зависимости файлов From от файлов Deny. Если Deny пуст,
+// файлам From разрешены только зависимости от Allow; иначе Allow задает
+// исключения из Deny. Пути задаются шаблонами в синтаксисе .gitignore.
+type Rule struct {
+	Name     string   `json:"name"`
+	From     []string `json:"from"`
+	Deny     []string `json:"deny,omitempty"`
+	Allow    []string `json:"allow,omitempty"`
+	Severity string   `json:"severity,omitempty"`
+	Message  string   `json:"message,omitempty"`
+}
+
+// Default возвращает конфигурацию по умолчанию
+func Default() *Config {
+	cfg := newConfig()
+	cfg.setDefaults()
+	// Шаблоны по умолчанию заведомо корректны
+	_ = cfg.compile()
+	return cfg
+}
+
+// Load читает конфигурацию из корня проекта. Если файла нет, возвращается
+// конфигурация по умолчанию.
+func Load(projectPath string) (*Config, error) {
+	path := filepath.Join(projectPath, FileName)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Default(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения %s: %w", FileName, err)
+	}
+
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", FileName, err)
+	}
+	if err := cfg.checkEntryPoints(projectPath); err != nil {
+		return nil, fmt.Errorf("%s: %w", FileName, err)
+	}
+	return cfg, nil
+}
+
+// Parse разбирает и проверяет конфигурацию. Неизвестные параметры считаются ошибкой.
+func Parse(data []byte) (*Config, error) {
+	cfg := newConfig()
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, describeDecodeError(data, err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("после объекта конфигурации обнаружены лишние данные")
+	}
+
+	cfg.setDefaults()
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.compile(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// IncludePatterns возвращает скомпилированные шаблоны Include (nil, если не заданы)
+func (c *Config) IncludePatterns() *utils.PatternSet {
+	return c.include
+}
+
+// ExcludePatterns возвращает скомпилированные шаблоны Exclude
+func (c *Config) ExcludePatterns() *utils.PatternSet {
+	return c.exclude
+}
+
+// newConfig возвращает конфигурацию с числовыми параметрами по умолчанию.
+// Они задаются до разбора файла, чтобы явно указанный 0 не подменялся
+// значением по умолчанию и проходил проверку в validate.
+func newConfig() *Config {
+	return &Config{Workers: DefaultWorkers, Port: DefaultPort}
+}
+
+// setDefaults заполняет незаданные списки значениями по умолчанию
+func (c *Config) setDefaults() {
+	if c.Exclude == nil {
+		c.Exclude = append([]string{}, DefaultExclude...)
+	}
+	if len(c.Extensions) == 0 {
+		c.Extensions = append([]string{}, DefaultExtensions...)
+	}
+	for i := range c.Rules {
+		if c.Rules[i].Severity == "" {
+			c.Rules[i].Severity = SeverityError
+		}
+	}
+}
+
+// validate проверяет значения параметров и возвращает все найденные ошибки
+func (c *Config) validate() error {
+	var problems []string
+	report := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	checkPatterns := func(field string, patterns []string) {
+		for i, pattern := range patterns {
+			if err := utils.ValidatePattern(pattern); err != nil {
+				report("%s[%d]: %v", field, i, err)
+			}
+		}
+	}
+
+	checkPatterns("include", c.Include)
+	checkPatterns("exclude", c.Exclude)
+
+	for i, ext := range c.Extensions {
+		if !strings.HasPrefix(ext, ".") || len(ext) < 2 || strings.ContainsAny(ext, `/\*`) {
+			report("extensions[%d]: расширение %q должно начинаться с точки, например \".ts\"", i, ext)
+		}
+	}
+
+	if c.Workers < 1 {
+		report("workers: значение должно быть не меньше 1, получено %d", c.Workers)
+	}
+	if c.Port < 0 || c.Port > 65535 {
+		report("port: значение должно быть в диапазоне 0-65535 (0 - свободный порт), получено %d", c.Port)
+	}
+
+	for _, alias := range sortedKeys(c.Aliases) {
+		targets := c.Aliases[alias]
+		if strings.Count(alias, "*") > 1 {
+			report("aliases[%q]: допускается не более одной *", alias)
+		}
+		if len(targets) == 0 {
+			report("aliases[%q]: необходимо указать хотя бы один путь", alias)
+		}
+		for _, target := range targets {
+			if strings.Count(target, "*") > 1 {
+				report("aliases[%q]: путь %q содержит больше одной *", alias, target)
+			}
+		}
+	}
+
+	for i, entry := range c.EntryPoints {
+		if entry == "" || filepath.IsAbs(entry) {
+			report("entryPoints[%d]: ожидается путь относительно корня проекта, получено %q", i, entry)
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, rule := range c.Rules {
+		field := fmt.Sprintf("rules[%d]", i)
+		if rule.Name == "" {
+			report("%s.name: необходимо указать имя правила", field)
+		} else if names[rule.Name] {
+			report("%s.name: правило %q уже объявлено", field, rule.Name)
+		}
+		names[rule.Name] = true
+
+		if len(rule.From) == 0 {
+			report("%s.from: необходимо указать хотя бы один шаблон", field)
+		}
+		if len(rule.Deny) == 0 && len(rule.Allow) == 0 {
+			report("%s: необходимо указать deny или allow", field)
+		}
+		checkPatterns(field+".from", rule.From)
+		checkPatterns(field+".deny", rule.Deny)
+		checkPatterns(field+".allow", rule.Allow)
+
+		if rule.Severity != SeverityError && rule.Severity != SeverityWarning {
+			report("%s.severity: ожидается %q или %q, получено %q", field, SeverityError, SeverityWarning, rule.Severity)
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// checkEntryPoints проверяет, что точки входа существуют
+func (c *Config) checkEntryPoints(projectPath string) error {
+	for i, entry := range c.EntryPoints {
+		info, err := os.Stat(filepath.Join(projectPath, filepath.FromSlash(entry)))
+		if err != nil || info.IsDir() {
+			return fmt.Errorf("entryPoints[%d]: файл %q не найден", i, entry)
+		}
+	}
+	return nil
+}
+
+// compile компилирует шаблоны include и exclude
+func (c *Config) compile() error {
+	var err error
+	if len(c.Include) > 0 {
+		if c.include, err = utils.NewPatternSet(c.Include); err != nil {
+			return err
+		}
+	}
+	c.exclude, err = utils.NewPatternSet(c.Exclude)
+	return err
+}
+
+// describeDecodeError переводит ошибку разбора JSON в сообщение с номером строки
+func describeDecodeError(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		line, column := position(data, syntaxErr.Offset)
+		return fmt.Errorf("синтаксическая ошибка в строке %d, столбце %d: %v", line, column, syntaxErr)
+	case errors.As(err, &typeErr):
+		line, column := position(data, typeErr.Offset)
+		return fmt.Errorf("параметр %s в строке %d, столбце %d: ожидается %s, получено %s",
+			typeErr.Field, line, column, describeType(typeErr.Type.Kind().String()), typeErr.Value)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		if unknown := findUnknownField(data); unknown != nil {
+			return unknown
+		}
+		field := strings.TrimPrefix(err.Error(), "json: unknown field ")
+		return fmt.Errorf("неизвестный параметр %s; допустимые параметры: %s",
+			field, strings.Join(fieldNames(reflect.TypeOf(Config{})), ", "))
+	case errors.Is(err, io.EOF):
+		return errors.New("файл пуст, ожидается объект JSON")
+	}
+	return err
+}
+
+// findUnknownField находит первый неизвестный параметр с учетом вложенности
+// (например, rules[0].nmae) и возвращает ошибку с его положением и списком
+// допустимых параметров того же объекта
+func findUnknownField(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	return checkFields(decoder, data, reflect.TypeOf(Config{}), "")
+}
+
+// checkFields читает из decoder одно значение и сверяет ключи объектов
+// с полями типа typ. Для typ == nil значение только пропускается.
+func checkFields(decoder *json.Decoder, data []byte, typ reflect.Type, path string) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	switch delim {
+	case '{':
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil
+			}
+			key, _ := keyToken.(string)
+
+			var fieldType reflect.Type
+			fieldPath := fmt.Sprintf("%s[%q]", path, key)
+			switch {
+			case typ != nil && typ.Kind() == reflect.Struct:
+				fieldPath = key
+				if path != "" {
+					fieldPath = path + "." + key
+				}
+				field, found := fieldByName(typ, key)
+				if !found {
+					// Смещение указывает на конец ключа, отступаем к открывающей кавычке
+					line, column := position(data, decoder.InputOffset()-int64(len(key))-2)
+					return fmt.Errorf("неизвестный параметр %s в строке %d, столбце %d; допустимые параметры: %s",
+						fieldPath, line, column, strings.Join(fieldNames(typ), ", "))
+				}
+				fieldType = field
+			case typ != nil && typ.Kind() == reflect.Map:
+				fieldType = typ.Elem()
+			}
+
+			if err := checkFields(decoder, data, fieldType, fieldPath); err != nil {
+				return err
+			}
+		}
+	case '[':
+		var elemType reflect.Type
+		if typ != nil && typ.Kind() == reflect.Slice {
+			elemType = typ.Elem()
+		}
+		for i := 0; decoder.More(); i++ {
+			if err := checkFields(decoder, data, elemType, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Закрывающая скобка
+	_, _ = decoder.Token()
+	return nil
+}
+
+// fieldNames возвращает имена параметров структуры из тегов json
+func fieldNames(typ reflect.Type) []string {
+	var names []string
+	for i := 0; i < typ.NumField(); i++ {
+		if name := jsonName(typ.Field(i)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// fieldByName возвращает тип поля структуры с параметром name. Как и
+// encoding/json, имена сравниваются без учета регистра.
+func fieldByName(typ reflect.Type, name string) (reflect.Type, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if jsonName(field) != "" && strings.EqualFold(jsonName(field), name) {
+			return field.Type, true
+		}
+	}
+	return nil, false
+}
+
+// jsonName возвращает имя параметра из тега json ("" для неэкспортируемых полей)
+func jsonName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// describeType возвращает понятное название типа значения
+func describeType(kind string) string {
+	switch kind {
+	case "int", "int64":
+		return "целое число"
+	case "string":
+		return "строка"
+	case "slice":
+		return "массив"
+	case "map", "struct":
+		return "объект"
+	case "bool":
+		return "логическое значение"
+	}
+	return kind
+}
+
+// position возвращает номер строки и столбца для смещения в данных
+func position(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+// sortedKeys возвращает ключи в алфавитном порядке
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
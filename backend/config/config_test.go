@@ -0,0 +1,150 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadDefault(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "config-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	if cfg.Workers != DefaultWorkers || cfg.Port != DefaultPort {
+		t.Errorf("Ожидаются значения по умолчанию, получено: %+v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.Extensions, DefaultExtensions) {
+		t.Errorf("Ожидаются расширения %v, получено: %v", DefaultExtensions, cfg.Extensions)
+	}
+	if cfg.IncludePatterns() != nil {
+		t.Errorf("Без include должны анализироваться все файлы")
+	}
+	if !cfg.ExcludePatterns().Match("node_modules/react/index.js", false) || !cfg.ExcludePatterns().Match(".cache", true) {
+		t.Errorf("По умолчанию должны пропускаться node_modules и скрытые директории")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "config-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := os.MkdirAll(filepath.Join(tempDir, "src"), 0755); err != nil {
+		t.Fatalf("Не удалось создать директорию: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "src", "main.ts"), []byte(""), 0644); err != nil {
+		t.Fatalf("Не удалось создать файл: %v", err)
+	}
+
+	content := `{
+  "include": ["src/"],
+  "exclude": ["**/*.test.ts", "node_modules/"],
+  "extensions": [".ts", ".mts"],
+  "workers": 4,
+  "port": 9090,
+  "aliases": {"@/*": ["src/*"]},
+  "entryPoints": ["src/main.ts"],
+  "rules": [
+    {"name": "ui-no-db", "from": ["src/ui/"], "deny": ["src/db/"], "message": "UI не обращается к БД"},
+    {"name": "shared", "from": ["src/shared/"], "allow": ["src/shared/"], "severity": "warning"}
+  ]
+}`
+	if err := os.WriteFile(filepath.Join(tempDir, FileName), []byte(content), 0644); err != nil {
+		t.Fatalf("Не удалось создать файл конфигурации: %v", err)
+	}
+
+	cfg, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	if cfg.Workers != 4 || cfg.Port != 9090 {
+		t.Errorf("Неверные workers/port: %+v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.Extensions, []string{".ts", ".mts"}) {
+		t.Errorf("Неверные расширения: %v", cfg.Extensions)
+	}
+	if !reflect.DeepEqual(cfg.Aliases["@/*"], []string{"src/*"}) {
+		t.Errorf("Неверные псевдонимы: %v", cfg.Aliases)
+	}
+	if len(cfg.Rules) != 2 || cfg.Rules[0].Severity != SeverityError || cfg.Rules[1].Severity != SeverityWarning {
+		t.Errorf("Неверные правила: %+v", cfg.Rules)
+	}
+	if !cfg.IncludePatterns().Match("src/app.ts", false) || cfg.IncludePatterns().Match("scripts/build.ts", false) {
+		t.Errorf("Неверные шаблоны include")
+	}
+	if !cfg.ExcludePatterns().Match("src/app.test.ts", false) || cfg.ExcludePatterns().Match(".cache", true) {
+		t.Errorf("Заданный exclude должен заменять значения по умолчанию")
+	}
+
+	// Несуществующая точка входа
+	content = strings.Replace(content, "src/main.ts", "src/missing.ts", 1)
+	if err := os.WriteFile(filepath.Join(tempDir, FileName), []byte(content), 0644); err != nil {
+		t.Fatalf("Не удалось обновить файл конфигурации: %v", err)
+	}
+	if _, err := Load(tempDir); err == nil || !strings.Contains(err.Error(), "src/missing.ts") {
+		t.Errorf("Ожидается ошибка для несуществующей точки входа, получено: %v", err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{"неизвестный параметр", `{"exlude": ["dist/"]}`,
+			[]string{"неизвестный параметр exlude в строке 1, столбце 2", "допустимые параметры: include, exclude, extensions, workers, port, aliases, entryPoints, rules"}},
+		{"неизвестный параметр правила", "{\n  \"exclude\": [],\n  \"aliases\": {\"@/*\": [\"src/*\"]},\n  \"rules\": [{\"name\": \"a\", \"from\": [\"a/\"]}, {\"nmae\": \"b\"}]\n}",
+			[]string{"неизвестный параметр rules[1].nmae в строке 4, столбце 45", "допустимые параметры: name, from, deny, allow, severity, message"}},
+		{"неверный тип", `{"workers": "4"}`, []string{"workers", "строке 1", "целое число"}},
+		{"синтаксическая ошибка", "{\n  \"workers\": 4,\n}", []string{"строке 3"}},
+		{"пустой файл", ``, []string{"пуст"}},
+		{"лишние данные", `{} {}`, []string{"лишние данные"}},
+		{"workers", `{"workers": -1}`, []string{"workers"}},
+		{"нулевой workers", `{"workers": 0}`, []string{"workers: значение должно быть не меньше 1, получено 0"}},
+		{"port", `{"port": 70000}`, []string{"port: значение должно быть в диапазоне 0-65535"}},
+		{"расширение", `{"extensions": ["ts"]}`, []string{"extensions[0]"}},
+		{"шаблон", `{"exclude": ["dist[/"]}`, []string{"exclude[0]"}},
+		{"псевдоним", `{"aliases": {"@/*/*": ["src/*"], "~": []}}`, []string{`aliases["@/*/*"]`, `aliases["~"]`}},
+		{"точка входа", `{"entryPoints": ["/abs/main.ts"]}`, []string{"entryPoints[0]"}},
+		{"правила", `{"rules": [{"name": "a", "from": ["x/"]}, {"name": "a", "deny": ["y/"], "severity": "fatal"}]}`,
+			[]string{"rules[0]: необходимо указать deny или allow", "rules[1].name", "rules[1].from", "rules[1].severity"}},
+	}
+
+	for _, test := range tests {
+		_, err := Parse([]byte(test.content))
+		if err == nil {
+			t.Errorf("%s: ожидается ошибка", test.name)
+			continue
+		}
+		for _, expected := range test.expected {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("%s: ожидается %q в ошибке, получено: %v", test.name, expected, err)
+			}
+		}
+	}
+}
+
+func TestParseFreePort(t *testing.T) {
+	// Явный 0 означает свободный порт и не заменяется значением по умолчанию
+	cfg, err := Parse([]byte(`{"port": 0}`))
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if cfg.Port != 0 {
+		t.Errorf("Ожидается порт 0, получено: %d", cfg.Port)
+	}
+}
@@ -0,0 +1,72 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// WriteDOT записывает граф в формате Graphviz DOT. Константы группируются
+// в кластеры по директориям и файлам, зависимости через import рисуются пунктиром.
+func WriteDOT(w io.Writer, graph models.DependencyGraph) error {
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintln(out, "digraph dependencies {")
+	fmt.Fprintln(out, "  rankdir=LR;")
+	fmt.Fprintln(out, `  node [shape=box, style="rounded,filled", fillcolor="#f5f5f5", fontname="Helvetica"];`)
+	fmt.Fprintln(out, `  edge [color="#555555"];`)
+
+	writeDOTDirectory(out, groupByDirectory(graph.Nodes), 1)
+
+	for _, edge := range graph.Edges {
+		style := ""
+		if edge.Kind == models.DependencyImport {
+			style = " [style=dashed]"
+		}
+		fmt.Fprintf(out, "  %s -> %s%s;\n", quoteDOT(edge.Source), quoteDOT(edge.Target), style)
+	}
+
+	fmt.Fprintln(out, "}")
+	return out.Flush()
+}
+
+// writeDOTDirectory записывает кластеры директории, ее файлов и вложенных директорий
+func writeDOTDirectory(out *bufio.Writer, dir *directory, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	for _, name := range sortedKeys(dir.Dirs) {
+		child := dir.Dirs[name]
+		fmt.Fprintf(out, "%ssubgraph %s {\n", indent, quoteDOT("cluster_dir_"+child.Path))
+		fmt.Fprintf(out, "%s  label=%s;\n", indent, quoteDOT(child.Path+"/"))
+		fmt.Fprintf(out, "%s  style=dashed;\n", indent)
+		writeDOTDirectory(out, child, depth+1)
+		fmt.Fprintf(out, "%s}\n", indent)
+	}
+
+	for _, file := range sortedKeys(dir.Files) {
+		fmt.Fprintf(out, "%ssubgraph %s {\n", indent, quoteDOT("cluster_file_"+file))
+		fmt.Fprintf(out, "%s  label=%s;\n", indent, quoteDOT(path.Base(file)))
+		fmt.Fprintf(out, "%s  style=\"rounded,filled\";\n", indent)
+		fmt.Fprintf(out, "%s  fillcolor=\"#e8eef7\";\n", indent)
+		for _, node := range dir.Files[file] {
+			label := fmt.Sprintf("%s\\n%s, строка %d", escapeDOT(node.Name), escapeDOT(node.Type), node.LineNum)
+			fmt.Fprintf(out, "%s  %s [label=\"%s\"];\n", indent, quoteDOT(node.ID), label)
+		}
+		fmt.Fprintf(out, "%s}\n", indent)
+	}
+}
+
+// quoteDOT возвращает строку DOT в кавычках
+func quoteDOT(s string) string {
+	return `"` + escapeDOT(s) + `"`
+}
+
+// escapeDOT экранирует символы, имеющие особое значение внутри строки DOT
+func escapeDOT(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", "")
+	return replacer.Replace(s)
+}
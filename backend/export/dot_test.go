@@ -0,0 +1,89 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// testGraph возвращает граф из констант в нескольких директориях
+func testGraph() models.DependencyGraph {
+	return models.DependencyGraph{
+		Nodes: []models.Constant{
+			{ID: "src/config.ts#API_URL", Name: "API_URL", Type: "string", Value: `"https://api"`, FilePath: "/p/src/config.ts", LineNum: 1},
+			{ID: "src/config.ts#$config", Name: "$config", Type: "object", Value: "{ url: API_URL }", FilePath: "/p/src/config.ts", LineNum: 2},
+			{ID: "src/utils/format.ts#ЛИМИТ", Name: "ЛИМИТ", Type: "number", Value: "10", FilePath: "/p/src/utils/format.ts", LineNum: 5},
+			{ID: "main.js#MAIN", Name: "MAIN", Type: "unknown", Value: `API_URL + "\"/"`, FilePath: "/p/main.js", LineNum: 3},
+		},
+		Edges: []models.Dependency{
+			{Source: "src/config.ts#$config", Target: "src/config.ts#API_URL", Kind: models.DependencyLocal},
+			{Source: "main.js#MAIN", Target: "src/config.ts#API_URL", Kind: models.DependencyImport},
+			{Source: "main.js#MAIN", Target: "src/utils/format.ts#ЛИМИТ", Kind: models.DependencyImport},
+		},
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteDOT(&out, testGraph()); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	dot := out.String()
+
+	expected := []string{
+		"digraph dependencies {",
+		`subgraph "cluster_dir_src" {`,
+		`label="src/";`,
+		`subgraph "cluster_dir_src/utils" {`,
+		`subgraph "cluster_file_src/config.ts" {`,
+		`label="config.ts";`,
+		`"src/config.ts#API_URL" [label="API_URL\nstring, строка 1"];`,
+		`"src/utils/format.ts#ЛИМИТ" [label="ЛИМИТ\nnumber, строка 5"];`,
+		`"src/config.ts#$config" -> "src/config.ts#API_URL";`,
+		`"main.js#MAIN" -> "src/config.ts#API_URL" [style=dashed];`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(dot, line) {
+			t.Errorf("Ожидается %q в выводе:\n%s", line, dot)
+		}
+	}
+
+	// Кластер utils вложен в кластер src
+	if strings.Index(dot, `"cluster_dir_src/utils"`) < strings.Index(dot, `"cluster_dir_src"`) {
+		t.Errorf("Кластер src/utils должен быть вложен в src:\n%s", dot)
+	}
+
+	if strings.Count(dot, "{") != strings.Count(dot, "}") {
+		t.Errorf("Несбалансированные фигурные скобки:\n%s", dot)
+	}
+}
+
+func TestEscapeDOT(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`plain`, `plain`},
+		{`say "hi"`, `say \"hi\"`},
+		{`back\slash`, `back\\slash`},
+		{"two\nlines", `two\nlines`},
+	}
+
+	for _, test := range tests {
+		if actual := escapeDOT(test.input); actual != test.expected {
+			t.Errorf("Для %q ожидается %q, получено: %q", test.input, test.expected, actual)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	format, ok := Lookup("DOT")
+	if !ok || format.Name != "dot" || format.Write == nil {
+		t.Errorf("Ожидается формат dot, получено: %+v (ok=%v)", format, ok)
+	}
+	if _, ok := Lookup("pdf"); ok {
+		t.Errorf("Формат pdf не поддерживается")
+	}
+}
@@ -0,0 +1,144 @@
+// Package export преобразует граф зависимостей в текстовые форматы
+// для документации и внешних инструментов визуализации
+package export
+
+import (
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// Format описывает формат экспорта графа
+type Format struct {
+	Name        string // Имя формата в API и CLI
+	ContentType string // MIME-тип ответа
+	Extension   string // Расширение файла
+	Write       func(w io.Writer, graph models.DependencyGraph) error
+}
+
+// formats содержит поддерживаемые форматы по имени
+var formats = map[string]Format{
+	"dot":      {Name: "dot", ContentType: "text/vnd.graphviz; charset=utf-8", Extension: ".dot", Write: WriteDOT},
+	"mermaid":  {Name: "mermaid", ContentType: "text/plain; charset=utf-8", Extension: ".mmd", Write: WriteMermaid},
+	"plantuml": {Name: "plantuml", ContentType: "text/plain; charset=utf-8", Extension: ".puml", Write: WritePlantUML},
+	"graphml":  {Name: "graphml", ContentType: "application/graphml+xml; charset=utf-8", Extension: ".graphml", Write: WriteGraphML},
+	"gexf":     {Name: "gexf", ContentType: "application/gexf+xml; charset=utf-8", Extension: ".gexf", Write: WriteGEXF},
+}
+
+// Lookup возвращает формат по имени
+func Lookup(name string) (Format, bool) {
+	format, ok := formats[strings.ToLower(name)]
+	return format, ok
+}
+
+// Formats возвращает отсортированные имена поддерживаемых форматов
+func Formats() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FilterDirectory возвращает подграф констант из директории dir (путь относительно
+// корня проекта), их зависимости и соседние константы из других директорий
+func FilterDirectory(graph models.DependencyGraph, dir string) models.DependencyGraph {
+	dir = strings.Trim(path.Clean("/"+strings.ReplaceAll(dir, "\\", "/")), "/")
+	if dir == "" {
+		return graph
+	}
+
+	inside := make(map[string]bool)
+	for _, node := range graph.Nodes {
+		if strings.HasPrefix(nodeFile(node), dir+"/") {
+			inside[node.ID] = true
+		}
+	}
+
+	subgraph := models.DependencyGraph{Nodes: []models.Constant{}, Edges: []models.Dependency{}}
+	neighbours := make(map[string]bool)
+	for _, edge := range graph.Edges {
+		if inside[edge.Source] || inside[edge.Target] {
+			subgraph.Edges = append(subgraph.Edges, edge)
+			neighbours[edge.Source] = true
+			neighbours[edge.Target] = true
+		}
+	}
+	for _, node := range graph.Nodes {
+		if inside[node.ID] || neighbours[node.ID] {
+			subgraph.Nodes = append(subgraph.Nodes, node)
+		}
+	}
+
+	return subgraph
+}
+
+// nodeAliases назначает узлам короткие идентификаторы n0, n1, ... для форматов,
+// в которых идентификатор узла не может содержать произвольные символы
+func nodeAliases(nodes []models.Constant) map[string]string {
+	aliases := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		if _, ok := aliases[node.ID]; !ok {
+			aliases[node.ID] = "n" + strconv.Itoa(len(aliases))
+		}
+	}
+	return aliases
+}
+
+// nodeFile возвращает путь к файлу константы относительно корня проекта,
+// извлеченный из ее идентификатора ("src/config.ts#API_URL")
+func nodeFile(node models.Constant) string {
+	if i := strings.LastIndex(node.ID, "#"); i >= 0 {
+		return node.ID[:i]
+	}
+	return path.Base(node.FilePath)
+}
+
+// directory группирует константы по директориям и файлам
+type directory struct {
+	Path  string
+	Dirs  map[string]*directory
+	Files map[string][]models.Constant
+}
+
+// groupByDirectory строит дерево директорий и файлов для узлов графа
+func groupByDirectory(nodes []models.Constant) *directory {
+	root := &directory{Dirs: map[string]*directory{}, Files: map[string][]models.Constant{}}
+
+	for _, node := range nodes {
+		file := nodeFile(node)
+		current := root
+		if dir := path.Dir(file); dir != "." {
+			for _, part := range strings.Split(dir, "/") {
+				child, ok := current.Dirs[part]
+				if !ok {
+					child = &directory{
+						Path:  path.Join(current.Path, part),
+						Dirs:  map[string]*directory{},
+						Files: map[string][]models.Constant{},
+					}
+					current.Dirs[part] = child
+				}
+				current = child
+			}
+		}
+		current.Files[file] = append(current.Files[file], node)
+	}
+
+	return root
+}
+
+// sortedKeys возвращает отсортированные ключи карты
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
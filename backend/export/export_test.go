@@ -0,0 +1,45 @@
+package export
+
+import (
+	"testing"
+)
+
+func TestFilterDirectory(t *testing.T) {
+	graph := testGraph()
+
+	subgraph := FilterDirectory(graph, "src/utils/")
+
+	ids := make(map[string]bool)
+	for _, node := range subgraph.Nodes {
+		ids[node.ID] = true
+	}
+	// Константа директории и соседняя константа, которая от нее зависит
+	if len(ids) != 2 || !ids["src/utils/format.ts#ЛИМИТ"] || !ids["main.js#MAIN"] {
+		t.Errorf("Неожиданные узлы подграфа: %v", ids)
+	}
+	if len(subgraph.Edges) != 1 {
+		t.Errorf("Ожидается 1 ребро, получено: %+v", subgraph.Edges)
+	}
+
+	// Префикс имени директории не должен совпадать с другими директориями
+	if len(FilterDirectory(graph, "sr").Nodes) != 0 {
+		t.Errorf("Ожидается пустой подграф для несуществующей директории")
+	}
+
+	if full := FilterDirectory(graph, "."); len(full.Nodes) != len(graph.Nodes) {
+		t.Errorf("Корень проекта должен возвращать весь граф")
+	}
+}
+
+func TestFormats(t *testing.T) {
+	expected := []string{"dot", "gexf", "graphml", "mermaid", "plantuml"}
+	names := Formats()
+	if len(names) != len(expected) {
+		t.Fatalf("Ожидаются форматы %v, получено: %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("Ожидаются форматы %v, получено: %v", expected, names)
+		}
+	}
+}
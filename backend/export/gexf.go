@@ -0,0 +1,57 @@
+package export
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// WriteGEXF записывает граф в формате GEXF 1.3 (Gephi). Поля константы
+// сохраняются как типизированные атрибуты узла, вид зависимости - как атрибут ребра.
+// Документ записывается по мере обхода графа.
+func WriteGEXF(w io.Writer, graph models.DependencyGraph) error {
+	x := newXMLWriter(w)
+
+	x.start("gexf", "xmlns", "http://gexf.net/1.3", "version", "1.3")
+	x.start("graph", "defaultedgetype", "directed", "mode", "static")
+
+	x.start("attributes", "class", "node")
+	x.empty("attribute", "id", "type", "title", "type", "type", "string")
+	x.empty("attribute", "id", "value", "title", "value", "type", "string")
+	x.empty("attribute", "id", "filePath", "title", "filePath", "type", "string")
+	x.empty("attribute", "id", "lineNum", "title", "lineNum", "type", "integer")
+	x.end("attributes")
+
+	x.start("attributes", "class", "edge")
+	x.empty("attribute", "id", "kind", "title", "kind", "type", "string")
+	x.end("attributes")
+
+	x.start("nodes")
+	for _, node := range graph.Nodes {
+		x.start("node", "id", node.ID, "label", node.Name)
+		x.start("attvalues")
+		x.empty("attvalue", "for", "type", "value", node.Type)
+		x.empty("attvalue", "for", "value", "value", node.Value)
+		x.empty("attvalue", "for", "filePath", "value", node.FilePath)
+		x.empty("attvalue", "for", "lineNum", "value", strconv.Itoa(node.LineNum))
+		x.end("attvalues")
+		x.end("node")
+	}
+	x.end("nodes")
+
+	x.start("edges")
+	for i, edge := range graph.Edges {
+		x.start("edge", "id", strconv.Itoa(i), "source", edge.Source, "target", edge.Target)
+		x.start("attvalues")
+		x.empty("attvalue", "for", "kind", "value", edge.Kind)
+		x.end("attvalues")
+		x.end("edge")
+	}
+	x.end("edges")
+
+	x.end("graph")
+	x.end("gexf")
+
+	return x.close()
+}
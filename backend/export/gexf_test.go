@@ -0,0 +1,76 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+)
+
+func TestWriteGEXF(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteGEXF(&out, testGraph()); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	type attValue struct {
+		For   string `xml:"for,attr"`
+		Value string `xml:"value,attr"`
+	}
+	var document struct {
+		Version string `xml:"version,attr"`
+		Graph   struct {
+			Attributes []struct {
+				Class      string `xml:"class,attr"`
+				Attributes []struct {
+					ID   string `xml:"id,attr"`
+					Type string `xml:"type,attr"`
+				} `xml:"attribute"`
+			} `xml:"attributes"`
+			Nodes []struct {
+				ID        string     `xml:"id,attr"`
+				Label     string     `xml:"label,attr"`
+				AttValues []attValue `xml:"attvalues>attvalue"`
+			} `xml:"nodes>node"`
+			Edges []struct {
+				ID        string     `xml:"id,attr"`
+				Source    string     `xml:"source,attr"`
+				Target    string     `xml:"target,attr"`
+				AttValues []attValue `xml:"attvalues>attvalue"`
+			} `xml:"edges>edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(out.Bytes(), &document); err != nil {
+		t.Fatalf("Некорректный XML: %v\n%s", err, out.String())
+	}
+
+	if document.Version != "1.3" || len(document.Graph.Attributes) != 2 {
+		t.Fatalf("Неожиданный заголовок документа: %+v", document)
+	}
+	for _, attribute := range document.Graph.Attributes[0].Attributes {
+		if attribute.ID == "lineNum" && attribute.Type != "integer" {
+			t.Errorf("Ожидается целочисленный атрибут lineNum, получено: %s", attribute.Type)
+		}
+	}
+
+	if len(document.Graph.Nodes) != 4 || len(document.Graph.Edges) != 3 {
+		t.Fatalf("Ожидается 4 узла и 3 ребра, получено: %d и %d", len(document.Graph.Nodes), len(document.Graph.Edges))
+	}
+
+	node := document.Graph.Nodes[1]
+	if node.ID != "src/config.ts#$config" || node.Label != "$config" {
+		t.Errorf("Неожиданный узел: %+v", node)
+	}
+	values := make(map[string]string)
+	for _, value := range node.AttValues {
+		values[value.For] = value.Value
+	}
+	if values["type"] != "object" || values["value"] != "{ url: API_URL }" || values["lineNum"] != "2" {
+		t.Errorf("Неожиданные атрибуты узла: %v", values)
+	}
+
+	edge := document.Graph.Edges[2]
+	if edge.ID != "2" || edge.Target != "src/utils/format.ts#ЛИМИТ" ||
+		len(edge.AttValues) != 1 || edge.AttValues[0].Value != "import" {
+		t.Errorf("Неожиданное ребро: %+v", edge)
+	}
+}
@@ -0,0 +1,52 @@
+package export
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// WriteGraphML записывает граф в формате GraphML (yEd, Gephi). Поля константы
+// сохраняются как типизированные атрибуты узла, вид зависимости - как атрибут ребра.
+// Документ записывается по мере обхода графа.
+func WriteGraphML(w io.Writer, graph models.DependencyGraph) error {
+	x := newXMLWriter(w)
+
+	x.start("graphml", "xmlns", "http://graphml.graphdrawing.org/xmlns")
+
+	keys := []struct{ id, target, kind string }{
+		{"name", "node", "string"},
+		{"type", "node", "string"},
+		{"value", "node", "string"},
+		{"filePath", "node", "string"},
+		{"lineNum", "node", "int"},
+		{"kind", "edge", "string"},
+	}
+	for _, key := range keys {
+		x.empty("key", "id", key.id, "for", key.target, "attr.name", key.id, "attr.type", key.kind)
+	}
+
+	x.start("graph", "id", "dependencies", "edgedefault", "directed")
+
+	for _, node := range graph.Nodes {
+		x.start("node", "id", node.ID)
+		x.text("data", node.Name, "key", "name")
+		x.text("data", node.Type, "key", "type")
+		x.text("data", node.Value, "key", "value")
+		x.text("data", node.FilePath, "key", "filePath")
+		x.text("data", strconv.Itoa(node.LineNum), "key", "lineNum")
+		x.end("node")
+	}
+
+	for _, edge := range graph.Edges {
+		x.start("edge", "source", edge.Source, "target", edge.Target)
+		x.text("data", edge.Kind, "key", "kind")
+		x.end("edge")
+	}
+
+	x.end("graph")
+	x.end("graphml")
+
+	return x.close()
+}
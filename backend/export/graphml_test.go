@@ -0,0 +1,89 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"testing"
+)
+
+func TestWriteGraphML(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteGraphML(&out, testGraph()); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	var document struct {
+		Keys []struct {
+			ID   string `xml:"id,attr"`
+			For  string `xml:"for,attr"`
+			Type string `xml:"attr.type,attr"`
+		} `xml:"key"`
+		Graph struct {
+			EdgeDefault string `xml:"edgedefault,attr"`
+			Nodes       []struct {
+				ID   string `xml:"id,attr"`
+				Data []struct {
+					Key   string `xml:"key,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+				Kind   string `xml:"data"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(out.Bytes(), &document); err != nil {
+		t.Fatalf("Некорректный XML: %v\n%s", err, out.String())
+	}
+
+	keyTypes := make(map[string]string)
+	for _, key := range document.Keys {
+		keyTypes[key.For+"."+key.ID] = key.Type
+	}
+	for key, expected := range map[string]string{
+		"node.type": "string", "node.value": "string", "node.filePath": "string",
+		"node.lineNum": "int", "edge.kind": "string",
+	} {
+		if keyTypes[key] != expected {
+			t.Errorf("Ожидается атрибут %s типа %s, получено: %q", key, expected, keyTypes[key])
+		}
+	}
+
+	if document.Graph.EdgeDefault != "directed" || len(document.Graph.Nodes) != 4 || len(document.Graph.Edges) != 3 {
+		t.Fatalf("Неожиданная структура графа: %+v", document.Graph)
+	}
+
+	// Значение со спецсимволами должно сохраниться без искажений
+	values := make(map[string]string)
+	for _, data := range document.Graph.Nodes[3].Data {
+		values[data.Key] = data.Value
+	}
+	if values["value"] != `API_URL + "\"/"` || values["lineNum"] != "3" || values["filePath"] != "/p/main.js" {
+		t.Errorf("Неожиданные атрибуты узла: %v", values)
+	}
+
+	if edge := document.Graph.Edges[1]; edge.Source != "main.js#MAIN" || edge.Kind != "import" {
+		t.Errorf("Неожиданное ребро: %+v", edge)
+	}
+}
+
+// failingWriter возвращает ошибку при любой записи
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestXMLWritersReportErrors(t *testing.T) {
+	for name, write := range map[string]func() error{
+		"graphml": func() error { return WriteGraphML(failingWriter{}, testGraph()) },
+		"gexf":    func() error { return WriteGEXF(failingWriter{}, testGraph()) },
+	} {
+		if err := write(); err == nil {
+			t.Errorf("%s: ожидается ошибка записи", name)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// WriteMermaid записывает граф в виде диаграммы Mermaid flowchart. Узлы получают
+// короткие идентификаторы, а имена констант выводятся только в экранированных подписях,
+// поэтому имена вроде $config или идентификаторы в Юникоде не ломают разметку.
+func WriteMermaid(w io.Writer, graph models.DependencyGraph) error {
+	out := bufio.NewWriter(w)
+	aliases := nodeAliases(graph.Nodes)
+
+	fmt.Fprintln(out, "flowchart LR")
+
+	counter := 0
+	writeMermaidDirectory(out, groupByDirectory(graph.Nodes), aliases, &counter, 1)
+
+	for _, edge := range graph.Edges {
+		source, ok := aliases[edge.Source]
+		if !ok {
+			continue
+		}
+		target, ok := aliases[edge.Target]
+		if !ok {
+			continue
+		}
+		arrow := "-->"
+		if edge.Kind == models.DependencyImport {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(out, "  %s %s %s\n", source, arrow, target)
+	}
+
+	return out.Flush()
+}
+
+// writeMermaidDirectory записывает подграфы директории, ее файлов и вложенных директорий
+func writeMermaidDirectory(out *bufio.Writer, dir *directory, aliases map[string]string, counter *int, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	for _, name := range sortedKeys(dir.Dirs) {
+		child := dir.Dirs[name]
+		fmt.Fprintf(out, "%ssubgraph g%d[\"%s\"]\n", indent, *counter, escapeMermaid(child.Path+"/"))
+		*counter++
+		writeMermaidDirectory(out, child, aliases, counter, depth+1)
+		fmt.Fprintf(out, "%send\n", indent)
+	}
+
+	for _, file := range sortedKeys(dir.Files) {
+		fmt.Fprintf(out, "%ssubgraph g%d[\"%s\"]\n", indent, *counter, escapeMermaid(path.Base(file)))
+		*counter++
+		for _, node := range dir.Files[file] {
+			fmt.Fprintf(out, "%s  %s[\"%s<br/>%s, строка %d\"]\n", indent, aliases[node.ID],
+				escapeMermaid(node.Name), escapeMermaid(node.Type), node.LineNum)
+		}
+		fmt.Fprintf(out, "%send\n", indent)
+	}
+}
+
+// escapeMermaid заменяет символы, имеющие особое значение в подписях Mermaid,
+// на коды сущностей
+func escapeMermaid(s string) string {
+	replacer := strings.NewReplacer(
+		"#", "#35;",
+		`"`, "#quot;",
+		"<", "#lt;",
+		">", "#gt;",
+		"`", "#96;",
+		"\n", " ",
+		"\r", "",
+	)
+	return replacer.Replace(s)
+}
@@ -0,0 +1,65 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteMermaid(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteMermaid(&out, testGraph()); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	mermaid := out.String()
+
+	expected := []string{
+		"flowchart LR",
+		`["src/"]`,
+		`["config.ts"]`,
+		`["$config<br/>object, строка 2"]`,
+		`["ЛИМИТ<br/>number, строка 5"]`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(mermaid, line) {
+			t.Errorf("Ожидается %q в выводе:\n%s", line, mermaid)
+		}
+	}
+
+	// Идентификаторы констант не попадают в разметку: используются псевдонимы
+	if strings.Contains(mermaid, "#API_URL") || strings.Contains(mermaid, "src/config.ts#") {
+		t.Errorf("Идентификаторы констант не должны использоваться как узлы Mermaid:\n%s", mermaid)
+	}
+
+	aliases := nodeAliases(testGraph().Nodes)
+	local := aliases["src/config.ts#$config"] + " --> " + aliases["src/config.ts#API_URL"]
+	imported := aliases["main.js#MAIN"] + " -.-> " + aliases["src/config.ts#API_URL"]
+	for _, edge := range []string{local, imported} {
+		if !strings.Contains(mermaid, edge) {
+			t.Errorf("Ожидается ребро %q в выводе:\n%s", edge, mermaid)
+		}
+	}
+
+	if strings.Count(mermaid, "subgraph") != strings.Count(mermaid, "end\n") {
+		t.Errorf("Несбалансированные подграфы:\n%s", mermaid)
+	}
+}
+
+func TestEscapeMermaid(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"$config", "$config"},
+		{`say "hi"`, "say #quot;hi#quot;"},
+		{"a<b>", "a#lt;b#gt;"},
+		{"#private", "#35;private"},
+		{"`tick`", "#96;tick#96;"},
+	}
+
+	for _, test := range tests {
+		if actual := escapeMermaid(test.input); actual != test.expected {
+			t.Errorf("Для %q ожидается %q, получено: %q", test.input, test.expected, actual)
+		}
+	}
+}
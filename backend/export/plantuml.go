@@ -0,0 +1,80 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// WritePlantUML записывает граф в виде диаграммы компонентов PlantUML.
+// Директории и файлы становятся пакетами, константы - компонентами
+// с короткими псевдонимами и экранированными подписями.
+func WritePlantUML(w io.Writer, graph models.DependencyGraph) error {
+	out := bufio.NewWriter(w)
+	aliases := nodeAliases(graph.Nodes)
+
+	fmt.Fprintln(out, "@startuml")
+	fmt.Fprintln(out, "skinparam componentStyle rectangle")
+
+	counter := 0
+	writePlantUMLDirectory(out, groupByDirectory(graph.Nodes), aliases, &counter, 0)
+
+	for _, edge := range graph.Edges {
+		source, ok := aliases[edge.Source]
+		if !ok {
+			continue
+		}
+		target, ok := aliases[edge.Target]
+		if !ok {
+			continue
+		}
+		arrow := "-->"
+		if edge.Kind == models.DependencyImport {
+			arrow = "..>"
+		}
+		fmt.Fprintf(out, "%s %s %s\n", source, arrow, target)
+	}
+
+	fmt.Fprintln(out, "@enduml")
+	return out.Flush()
+}
+
+// writePlantUMLDirectory записывает пакеты директории, ее файлов и вложенных директорий
+func writePlantUMLDirectory(out *bufio.Writer, dir *directory, aliases map[string]string, counter *int, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	for _, name := range sortedKeys(dir.Dirs) {
+		child := dir.Dirs[name]
+		fmt.Fprintf(out, "%spackage \"%s\" as g%d {\n", indent, escapePlantUML(child.Path+"/"), *counter)
+		*counter++
+		writePlantUMLDirectory(out, child, aliases, counter, depth+1)
+		fmt.Fprintf(out, "%s}\n", indent)
+	}
+
+	for _, file := range sortedKeys(dir.Files) {
+		fmt.Fprintf(out, "%spackage \"%s\" as g%d {\n", indent, escapePlantUML(path.Base(file)), *counter)
+		*counter++
+		for _, node := range dir.Files[file] {
+			fmt.Fprintf(out, "%s  component \"%s\\n%s, строка %d\" as %s\n", indent,
+				escapePlantUML(node.Name), escapePlantUML(node.Type), node.LineNum, aliases[node.ID])
+		}
+		fmt.Fprintf(out, "%s}\n", indent)
+	}
+}
+
+// escapePlantUML заменяет символы, которые нельзя использовать внутри
+// строки PlantUML в кавычках, на числовые ссылки на символы
+func escapePlantUML(s string) string {
+	replacer := strings.NewReplacer(
+		"&", "&#38;",
+		`"`, "&#34;",
+		`\`, "&#92;",
+		"\n", " ",
+		"\r", "",
+	)
+	return replacer.Replace(s)
+}
@@ -0,0 +1,54 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWritePlantUML(t *testing.T) {
+	var out bytes.Buffer
+	if err := WritePlantUML(&out, testGraph()); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	uml := out.String()
+
+	aliases := nodeAliases(testGraph().Nodes)
+	expected := []string{
+		"@startuml",
+		`package "src/" as `,
+		`package "src/utils/" as `,
+		`component "$config\nobject, строка 2" as ` + aliases["src/config.ts#$config"],
+		`component "ЛИМИТ\nnumber, строка 5" as ` + aliases["src/utils/format.ts#ЛИМИТ"],
+		aliases["src/config.ts#$config"] + " --> " + aliases["src/config.ts#API_URL"],
+		aliases["main.js#MAIN"] + " ..> " + aliases["src/utils/format.ts#ЛИМИТ"],
+		"@enduml",
+	}
+	for _, line := range expected {
+		if !strings.Contains(uml, line) {
+			t.Errorf("Ожидается %q в выводе:\n%s", line, uml)
+		}
+	}
+
+	if strings.Count(uml, "{") != strings.Count(uml, "}") {
+		t.Errorf("Несбалансированные фигурные скобки:\n%s", uml)
+	}
+}
+
+func TestEscapePlantUML(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"$config", "$config"},
+		{`say "hi"`, "say &#34;hi&#34;"},
+		{`a\b`, "a&#92;b"},
+		{"a&b", "a&#38;b"},
+	}
+
+	for _, test := range tests {
+		if actual := escapePlantUML(test.input); actual != test.expected {
+			t.Errorf("Для %q ожидается %q, получено: %q", test.input, test.expected, actual)
+		}
+	}
+}
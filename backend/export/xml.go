@@ -0,0 +1,63 @@
+package export
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// xmlWriter последовательно записывает XML-документ, не строя его в памяти.
+// Первая ошибка запоминается, последующие вызовы ничего не делают.
+type xmlWriter struct {
+	encoder *xml.Encoder
+	err     error
+}
+
+func newXMLWriter(w io.Writer) *xmlWriter {
+	x := &xmlWriter{encoder: xml.NewEncoder(w)}
+	x.encoder.Indent("", "  ")
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		x.err = err
+	}
+	return x
+}
+
+// start открывает элемент с атрибутами, заданными парами имя-значение
+func (x *xmlWriter) start(name string, attrs ...string) {
+	element := xml.StartElement{Name: xml.Name{Local: name}}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		element.Attr = append(element.Attr, xml.Attr{Name: xml.Name{Local: attrs[i]}, Value: attrs[i+1]})
+	}
+	x.token(element)
+}
+
+// end закрывает элемент
+func (x *xmlWriter) end(name string) {
+	x.token(xml.EndElement{Name: xml.Name{Local: name}})
+}
+
+// empty записывает элемент без содержимого
+func (x *xmlWriter) empty(name string, attrs ...string) {
+	x.start(name, attrs...)
+	x.end(name)
+}
+
+// text записывает элемент с текстовым содержимым
+func (x *xmlWriter) text(name, value string, attrs ...string) {
+	x.start(name, attrs...)
+	x.token(xml.CharData(value))
+	x.end(name)
+}
+
+func (x *xmlWriter) token(token xml.Token) {
+	if x.err == nil {
+		x.err = x.encoder.EncodeToken(token)
+	}
+}
+
+// close дописывает буферизованные данные и возвращает первую ошибку
+func (x *xmlWriter) close() error {
+	if x.err == nil {
+		x.err = x.encoder.Flush()
+	}
+	return x.err
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// eventBufferSize ограничивает число неотправленных изменений для одного клиента
+const eventBufferSize = 16
+
+// keepAliveInterval задает период отправки комментариев, не дающих прокси закрыть соединение
+const keepAliveInterval = 30 * time.Second
+
+// EventBroker рассылает изменения графа подключенным клиентам
+type EventBroker struct {
+	mutex   sync.Mutex
+	clients map[chan models.GraphDelta]struct{}
+}
+
+// NewEventBroker создает новый экземпляр EventBroker
+func NewEventBroker() *EventBroker {
+	return &EventBroker{
+		clients: make(map[chan models.GraphDelta]struct{}),
+	}
+}
+
+// Publish отправляет изменения всем клиентам. Клиент, который не успевает
+// получать изменения, отключается: пропуск изменения сделал бы его граф
+// несогласованным, а после переподключения он заново загрузит граф целиком.
+func (b *EventBroker) Publish(delta models.GraphDelta) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for client := range b.clients {
+		select {
+		case client <- delta:
+		default:
+			delete(b.clients, client)
+			close(client)
+		}
+	}
+}
+
+// subscribe регистрирует нового клиента
+func (b *EventBroker) subscribe() chan models.GraphDelta {
+	client := make(chan models.GraphDelta, eventBufferSize)
+
+	b.mutex.Lock()
+	b.clients[client] = struct{}{}
+	b.mutex.Unlock()
+
+	return client
+}
+
+// unsubscribe отключает клиента, если он еще не отключен
+func (b *EventBroker) unsubscribe(client chan models.GraphDelta) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if _, ok := b.clients[client]; ok {
+		delete(b.clients, client)
+		close(client)
+	}
+}
+
+// HandleEvents передает изменения графа клиенту в формате Server-Sent Events.
+// После подключения отправляется событие "ready", затем событие "delta"
+// на каждое изменение графа или дерева файлов.
+func (h *Handler) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if h.Events == nil {
+		http.Error(w, "Live updates are disabled", http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	client := h.Events.subscribe()
+	defer h.Events.unsubscribe(client)
+
+	fmt.Fprint(w, "event: ready\ndata: {}\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case delta, ok := <-client:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(delta)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: delta\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// readEvent читает одно событие SSE и возвращает его тип и данные
+func readEvent(t *testing.T, reader *bufio.Reader) (string, string) {
+	t.Helper()
+
+	var event, data string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Ошибка чтения события: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			if event != "" {
+				return event, data
+			}
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestHandleEvents(t *testing.T) {
+	broker := NewEventBroker()
+	handler := &Handler{Events: broker}
+
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleEvents))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Ошибка подключения: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("Ожидается Content-Type=text/event-stream, получено: %s", contentType)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	if event, _ := readEvent(t, reader); event != "ready" {
+		t.Fatalf("Ожидается событие ready, получено: %s", event)
+	}
+
+	broker.Publish(models.GraphDelta{
+		AddedNodes:   []models.Constant{{ID: "a.js#A", Name: "A"}},
+		RemovedNodes: []string{"a.js#OLD"},
+		AddedFiles:   []string{"a.js"},
+	})
+
+	event, data := readEvent(t, reader)
+	if event != "delta" {
+		t.Fatalf("Ожидается событие delta, получено: %s", event)
+	}
+
+	var delta models.GraphDelta
+	if err := json.Unmarshal([]byte(data), &delta); err != nil {
+		t.Fatalf("Ошибка декодирования изменений: %v", err)
+	}
+	if len(delta.AddedNodes) != 1 || delta.AddedNodes[0].ID != "a.js#A" ||
+		len(delta.RemovedNodes) != 1 || len(delta.AddedFiles) != 1 {
+		t.Errorf("Неожиданные изменения: %+v", delta)
+	}
+}
+
+func TestHandleEventsDisabled(t *testing.T) {
+	handler := &Handler{}
+
+	rec := httptest.NewRecorder()
+	handler.HandleEvents(rec, httptest.NewRequest("GET", "/api/events", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Ожидается статус %d, получено: %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestEventBrokerDropsSlowClients(t *testing.T) {
+	broker := NewEventBroker()
+	client := broker.subscribe()
+
+	// Клиент не читает изменения: после заполнения буфера он отключается
+	for i := 0; i <= eventBufferSize; i++ {
+		broker.Publish(models.GraphDelta{})
+	}
+
+	received := 0
+	for range client {
+		received++
+	}
+	if received != eventBufferSize {
+		t.Errorf("Ожидается %d изменений до отключения, получено: %d", eventBufferSize, received)
+	}
+
+	// Повторное отключение не должно приводить к панике
+	broker.unsubscribe(client)
+}
@@ -2,10 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"path/filepath"
+	"strconv"
+	"strings"
 
+	"github.com/avor0n/dependency-graph-visualizer/config"
+	"github.com/avor0n/dependency-graph-visualizer/export"
 	"github.com/avor0n/dependency-graph-visualizer/models"
+	"github.com/avor0n/dependency-graph-visualizer/sarif"
 	"github.com/avor0n/dependency-graph-visualizer/services"
 )
 
@@ -18,7 +25,12 @@ type FileServiceInterface interface {
 // DependencyServiceInterface определяет интерфейс для DependencyService
 type DependencyServiceInterface interface {
 	GetFileDependencies(filePath string) models.DependencyGraph
+	GetModuleGraph(kinds []string) (models.ModuleGraph, error)
 	BuildDependencyGraph()
+	FindCycles() models.CycleReport
+	GetImpact(nodeID, direction string, depth int) (models.ImpactGraph, error)
+	Aggregate(by string, depth int, expand []string) (models.AggregateGraph, error)
+	CheckRules(rules []config.Rule) (models.ViolationReport, error)
 }
 
 // Handler представляет обработчики HTTP запросов
@@ -26,13 +38,16 @@ type Handler struct {
 	FileService       FileServiceInterface
 	DependencyService DependencyServiceInterface
 	ProjectPath       string
+	Events            *EventBroker  // Рассылка изменений графа (nil, если отслеживание выключено)
+	Rules             []config.Rule // Правила зависимостей из конфигурации проекта
 }
 
-// NewHandler создает новый экземпляр Handler
-func NewHandler(fileService services.FileService, dependencyService services.DependencyService, projectPath string) *Handler {
+// NewHandler создает новый экземпляр Handler. Сервисы передаются по указателю:
+// в режиме отслеживания обработчики читают граф, который обновляет Watcher.
+func NewHandler(fileService *services.FileService, dependencyService *services.DependencyService, projectPath string) *Handler {
 	return &Handler{
-		FileService:       &fileService,
-		DependencyService: &dependencyService,
+		FileService:       fileService,
+		DependencyService: dependencyService,
 		ProjectPath:       projectPath,
 	}
 }
@@ -97,6 +112,27 @@ func (h *Handler) HandleDependencyGraph(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(graph)
 }
 
+// HandleModuleGraph обрабатывает запрос графа импортов между файлами:
+// GET /api/module-graph?kind=<вид импорта>
+// Параметр kind можно повторять; kind=dynamic оставляет границы разделения кода.
+func (h *Handler) HandleModuleGraph(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "GET" {
+		http.Error(w, "Only GET method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	graph, err := h.DependencyService.GetModuleGraph(r.URL.Query()["kind"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	json.NewEncoder(w).Encode(graph)
+}
+
 // HandleFileDependencies обрабатывает запрос зависимостей конкретного файла
 func (h *Handler) HandleFileDependencies(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -122,3 +158,196 @@ func (h *Handler) HandleFileDependencies(w http.ResponseWriter, r *http.Request)
 
 	json.NewEncoder(w).Encode(fileDependencies)
 }
+
+// HandleCycles обрабатывает запрос циклических зависимостей.
+// Параметр format=sarif возвращает отчет в формате SARIF 2.1.0.
+func (h *Handler) HandleCycles(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "GET" {
+		http.Error(w, "Only GET method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	asSARIF, ok := sarifRequested(w, r)
+	if !ok {
+		return
+	}
+
+	report := h.DependencyService.FindCycles()
+	if asSARIF {
+		w.Header().Set("Content-Type", sarif.ContentType)
+		json.NewEncoder(w).Encode(sarif.FromCycles(report, h.ProjectPath))
+		return
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// sarifRequested проверяет параметр format: пустой или json - ответ JSON, sarif - отчет SARIF.
+// Для неизвестного формата отправляет ошибку 400 и возвращает ok = false.
+func sarifRequested(w http.ResponseWriter, r *http.Request) (asSARIF, ok bool) {
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		return false, true
+	case "sarif":
+		return true, true
+	default:
+		http.Error(w, "Unknown format, supported: json, sarif", http.StatusBadRequest)
+		return false, false
+	}
+}
+
+// HandleAggregate обрабатывает запрос графа, сгруппированного по директориям или пакетам:
+// GET /api/aggregate?by=directory|package&depth=N&expand=<группа>
+// Параметр expand можно повторять, чтобы раскрыть несколько групп в константы.
+func (h *Handler) HandleAggregate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "GET" {
+		http.Error(w, "Only GET method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	by := query.Get("by")
+	if by == "" {
+		by = models.AggregateDirectory
+	}
+
+	depth := 1
+	if value := query.Get("depth"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			http.Error(w, "Parameter depth must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		depth = parsed
+	}
+
+	graph, err := h.DependencyService.Aggregate(by, depth, query["expand"])
+	if errors.Is(err, services.ErrAggregateNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	json.NewEncoder(w).Encode(graph)
+}
+
+// HandleViolations обрабатывает запрос нарушений правил зависимостей.
+// Параметр format=sarif возвращает отчет в формате SARIF 2.1.0.
+func (h *Handler) HandleViolations(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "GET" {
+		http.Error(w, "Only GET method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	asSARIF, ok := sarifRequested(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.DependencyService.CheckRules(h.Rules)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if asSARIF {
+		w.Header().Set("Content-Type", sarif.ContentType)
+		json.NewEncoder(w).Encode(sarif.FromViolations(report, h.Rules, h.ProjectPath))
+		return
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// HandleImpact обрабатывает запрос транзитивных зависимостей и зависимых констант узла:
+// GET /api/impact?node=<id>&direction=up|down&depth=N
+func (h *Handler) HandleImpact(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "GET" {
+		http.Error(w, "Only GET method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	nodeID := query.Get("node")
+	if nodeID == "" {
+		http.Error(w, "Parameter node is required", http.StatusBadRequest)
+		return
+	}
+
+	direction := query.Get("direction")
+	if direction == "" {
+		direction = models.ImpactUp
+	}
+
+	depth := 0
+	if value := query.Get("depth"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Parameter depth must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		depth = parsed
+	}
+
+	impact, err := h.DependencyService.GetImpact(nodeID, direction, depth)
+	if errors.Is(err, services.ErrNodeNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	json.NewEncoder(w).Encode(impact)
+}
+
+// HandleExport обрабатывает запрос экспорта графа в текстовом формате:
+// GET /api/export?format=dot&file=<путь> или GET /api/export?format=mermaid&dir=<директория>
+func (h *Handler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if r.Method != "GET" {
+		http.Error(w, "Only GET method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	format, ok := export.Lookup(query.Get("format"))
+	if !ok {
+		http.Error(w, "Unknown format, supported: "+strings.Join(export.Formats(), ", "), http.StatusBadRequest)
+		return
+	}
+
+	file, dir := query.Get("file"), query.Get("dir")
+	if file != "" && dir != "" {
+		http.Error(w, "Parameters file and dir are mutually exclusive", http.StatusBadRequest)
+		return
+	}
+
+	// Пустой путь означает весь граф
+	graph := h.DependencyService.GetFileDependencies(file)
+	if dir != "" {
+		graph = export.FilterDirectory(graph, dir)
+	}
+
+	w.Header().Set("Content-Type", format.ContentType)
+	w.Header().Set("Content-Disposition", `inline; filename="dependencies`+format.Extension+`"`)
+	// Заголовки уже отправлены, поэтому ошибку записи можно только залогировать
+	if err := format.Write(w, graph); err != nil {
+		log.Printf("Error writing %s export: %v\n", format.Name, err)
+	}
+}
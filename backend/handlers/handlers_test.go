@@ -2,12 +2,16 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/avor0n/dependency-graph-visualizer/config"
 	"github.com/avor0n/dependency-graph-visualizer/models"
+	"github.com/avor0n/dependency-graph-visualizer/sarif"
+	"github.com/avor0n/dependency-graph-visualizer/services"
 	"github.com/avor0n/dependency-graph-visualizer/utils"
 )
 
@@ -34,6 +38,12 @@ type MockDependencyService struct {
 	FileService            *MockFileService
 	Graph                  models.DependencyGraph
 	GetFileDependenciesFunc func(filePath string) models.DependencyGraph
+	ModuleGraph             models.ModuleGraph
+	GetModuleGraphFunc      func(kinds []string) (models.ModuleGraph, error)
+	Cycles                  models.CycleReport
+	GetImpactFunc           func(nodeID, direction string, depth int) (models.ImpactGraph, error)
+	AggregateFunc           func(by string, depth int, expand []string) (models.AggregateGraph, error)
+	CheckRulesFunc          func(rules []config.Rule) (models.ViolationReport, error)
 }
 
 func (m *MockDependencyService) GetFileDependencies(filePath string) models.DependencyGraph {
@@ -49,10 +59,42 @@ func (m *MockDependencyService) GetFileDependencies(filePath string) models.Depe
 	return models.DependencyGraph{}
 }
 
+func (m *MockDependencyService) GetModuleGraph(kinds []string) (models.ModuleGraph, error) {
+	if m.GetModuleGraphFunc != nil {
+		return m.GetModuleGraphFunc(kinds)
+	}
+	return m.ModuleGraph, nil
+}
+
 func (m *MockDependencyService) BuildDependencyGraph() {
 	// Пустая реализация для интерфейса
 }
 
+func (m *MockDependencyService) FindCycles() models.CycleReport {
+	return m.Cycles
+}
+
+func (m *MockDependencyService) GetImpact(nodeID, direction string, depth int) (models.ImpactGraph, error) {
+	if m.GetImpactFunc != nil {
+		return m.GetImpactFunc(nodeID, direction, depth)
+	}
+	return models.ImpactGraph{}, nil
+}
+
+func (m *MockDependencyService) Aggregate(by string, depth int, expand []string) (models.AggregateGraph, error) {
+	if m.AggregateFunc != nil {
+		return m.AggregateFunc(by, depth, expand)
+	}
+	return models.AggregateGraph{}, nil
+}
+
+func (m *MockDependencyService) CheckRules(rules []config.Rule) (models.ViolationReport, error) {
+	if m.CheckRulesFunc != nil {
+		return m.CheckRulesFunc(rules)
+	}
+	return models.ViolationReport{}, nil
+}
+
 func TestNewHandler(t *testing.T) {
 	// Создаем мок-сервисы
 	mockFileService := &MockFileService{
@@ -248,6 +290,7 @@ func TestHandleDependencyGraph(t *testing.T) {
 		Graph: models.DependencyGraph{
 			Nodes: []models.Constant{
 				{
+					ID:       "file.js#CONST1",
 					Name:     "CONST1",
 					Value:    "value1",
 					Type:     "string",
@@ -255,6 +298,7 @@ func TestHandleDependencyGraph(t *testing.T) {
 					LineNum:  10,
 				},
 				{
+					ID:       "file.js#CONST2",
 					Name:     "CONST2",
 					Value:    "value2",
 					Type:     "string",
@@ -264,8 +308,8 @@ func TestHandleDependencyGraph(t *testing.T) {
 			},
 			Edges: []models.Dependency{
 				{
-					Source: "CONST2",
-					Target: "CONST1",
+					Source: "file.js#CONST2",
+					Target: "file.js#CONST1",
 				},
 			},
 		},
@@ -304,6 +348,11 @@ func TestHandleDependencyGraph(t *testing.T) {
 	if len(response.Edges) != expectedEdges {
 		t.Errorf("Ожидается %d ребер, получено: %d", expectedEdges, len(response.Edges))
 	}
+
+	// Ребра ссылаются на идентификаторы узлов
+	if response.Nodes[0].ID != "file.js#CONST1" || response.Edges[0].Target != response.Nodes[0].ID {
+		t.Errorf("Ожидается ребро к узлу file.js#CONST1, получено: %+v", response.Edges[0])
+	}
 }
 
 func TestHandleFileDependencies(t *testing.T) {
@@ -375,3 +424,347 @@ func TestHandleFileDependencies(t *testing.T) {
 		t.Errorf("Ожидается %d узлов, получено: %d", expectedNodes, len(response.Nodes))
 	}
 }
+
+func TestHandleModuleGraph(t *testing.T) {
+	handler := &Handler{
+		DependencyService: &MockDependencyService{
+			ModuleGraph: models.ModuleGraph{
+				Nodes: []models.ModuleNode{{ID: "src/app.ts", Name: "app.ts"}, {ID: "src/page.tsx", Name: "page.tsx"}},
+				Edges: []models.ModuleEdge{{Source: "src/app.ts", Target: "src/page.tsx", Kind: models.ImportDynamic, Symbols: []string{}, Line: 3}},
+			},
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	handler.HandleModuleGraph(rec, httptest.NewRequest("GET", "/api/module-graph", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Ожидается статус %d, получен %d", http.StatusOK, rec.Code)
+	}
+	var response models.ModuleGraph
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	if len(response.Nodes) != 2 || len(response.Edges) != 1 || response.Edges[0].Kind != models.ImportDynamic {
+		t.Errorf("Неверный ответ: %+v", response)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.HandleModuleGraph(rec, httptest.NewRequest("POST", "/api/module-graph", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Ожидается статус %d для POST, получен %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+
+	// Параметр kind можно повторять; неизвестный вид импорта - ошибка запроса
+	var kinds []string
+	handler.DependencyService = &MockDependencyService{
+		GetModuleGraphFunc: func(k []string) (models.ModuleGraph, error) {
+			kinds = k
+			if len(k) > 0 && k[0] == "lazy" {
+				return models.ModuleGraph{}, fmt.Errorf("unknown import kind %q", k[0])
+			}
+			return models.ModuleGraph{}, nil
+		},
+	}
+
+	rec = httptest.NewRecorder()
+	handler.HandleModuleGraph(rec, httptest.NewRequest("GET", "/api/module-graph?kind=dynamic&kind=side-effect", nil))
+	if rec.Code != http.StatusOK || fmt.Sprint(kinds) != "[dynamic side-effect]" {
+		t.Errorf("Ожидается фильтр [dynamic side-effect], получено %v (статус %d)", kinds, rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.HandleModuleGraph(rec, httptest.NewRequest("GET", "/api/module-graph?kind=lazy", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Ожидается статус %d для неизвестного вида, получен %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestHandleCycles(t *testing.T) {
+	mockDependencyService := &MockDependencyService{
+		Cycles: models.CycleReport{
+			Constants: []models.Cycle{
+				{
+					Nodes: []string{"a.js#A", "b.js#B"},
+					Edges: []models.Dependency{
+						{Source: "a.js#A", Target: "b.js#B", Kind: models.DependencyImport},
+						{Source: "b.js#B", Target: "a.js#A", Kind: models.DependencyImport},
+					},
+					Locations: []models.Location{
+						{File: "a.js", Line: 2, Column: 14, EndLine: 2, EndColumn: 15},
+						{File: "b.js", Line: 2, Column: 14, EndLine: 2, EndColumn: 15},
+					},
+				},
+			},
+			Modules: []models.Cycle{},
+		},
+	}
+
+	handler := &Handler{
+		DependencyService: mockDependencyService,
+	}
+
+	req := httptest.NewRequest("GET", "/api/cycles", nil)
+	rec := httptest.NewRecorder()
+	handler.HandleCycles(rec, req)
+
+	if contentType := rec.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Ожидается Content-Type=application/json, получено: %s", contentType)
+	}
+
+	var response models.CycleReport
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+
+	if len(response.Constants) != 1 || len(response.Constants[0].Edges) != 2 {
+		t.Errorf("Ожидается 1 цикл из 2 ребер, получено: %+v", response.Constants)
+	}
+
+	// Отчет SARIF с путями относительно корня проекта
+	handler.ProjectPath = "/project"
+	rec = httptest.NewRecorder()
+	handler.HandleCycles(rec, httptest.NewRequest("GET", "/api/cycles?format=sarif", nil))
+	var log sarif.Log
+	if err := json.NewDecoder(rec.Body).Decode(&log); err != nil {
+		t.Fatalf("Ошибка декодирования отчета SARIF: %v", err)
+	}
+	if len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 || log.Runs[0].Results[0].RuleID != sarif.RuleConstantCycle {
+		t.Fatalf("Неверный отчет SARIF: %+v", log)
+	}
+	if base := log.Runs[0].OriginalURIBaseIDs[sarif.SourceRoot].URI; !strings.HasPrefix(base, "file:///") {
+		t.Errorf("Ожидается URI корня проекта, получено: %s", base)
+	}
+
+	// Проверяем, что другие методы не разрешены
+	req = httptest.NewRequest("POST", "/api/cycles", nil)
+	rec = httptest.NewRecorder()
+	handler.HandleCycles(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Ожидается статус %d, получено: %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
+
+func TestHandleAggregate(t *testing.T) {
+	var calls []string
+	handler := &Handler{
+		DependencyService: &MockDependencyService{
+			AggregateFunc: func(by string, depth int, expand []string) (models.AggregateGraph, error) {
+				calls = append(calls, fmt.Sprintf("%s %d %v", by, depth, expand))
+				if len(expand) > 0 && expand[0] == "missing" {
+					return models.AggregateGraph{}, services.ErrAggregateNotFound
+				}
+				if by != models.AggregateDirectory && by != models.AggregatePackage {
+					return models.AggregateGraph{}, fmt.Errorf("unknown aggregation %q", by)
+				}
+				return models.AggregateGraph{
+					By:    by,
+					Depth: depth,
+					Nodes: []models.AggregateNode{{ID: "src", Label: "src", Kind: by, Constants: 2}},
+					Edges: []models.AggregateEdge{},
+				}, nil
+			},
+		},
+	}
+
+	tests := []struct {
+		url    string
+		status int
+		call   string
+	}{
+		{"/api/aggregate", http.StatusOK, "directory 1 []"},
+		{"/api/aggregate?by=package", http.StatusOK, "package 1 []"},
+		{"/api/aggregate?depth=2&expand=src/ui&expand=src/db", http.StatusOK, "directory 2 [src/ui src/db]"},
+		{"/api/aggregate?depth=0", http.StatusBadRequest, ""},
+		{"/api/aggregate?by=layer", http.StatusBadRequest, "layer 1 []"},
+		{"/api/aggregate?expand=missing", http.StatusNotFound, "directory 1 [missing]"},
+	}
+
+	for _, test := range tests {
+		calls = nil
+		rec := httptest.NewRecorder()
+		handler.HandleAggregate(rec, httptest.NewRequest("GET", test.url, nil))
+
+		if rec.Code != test.status {
+			t.Errorf("%s: ожидается статус %d, получен %d", test.url, test.status, rec.Code)
+		}
+		if test.call != "" && (len(calls) != 1 || calls[0] != test.call) {
+			t.Errorf("%s: ожидается вызов %q, получено %v", test.url, test.call, calls)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler.HandleAggregate(rec, httptest.NewRequest("GET", "/api/aggregate?by=package", nil))
+	var graph models.AggregateGraph
+	if err := json.NewDecoder(rec.Body).Decode(&graph); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	if graph.By != models.AggregatePackage || len(graph.Nodes) != 1 {
+		t.Errorf("Неверный ответ: %+v", graph)
+	}
+}
+
+func TestHandleImpact(t *testing.T) {
+	mockDependencyService := &MockDependencyService{
+		GetImpactFunc: func(nodeID, direction string, depth int) (models.ImpactGraph, error) {
+			if nodeID != "a.js#A" {
+				return models.ImpactGraph{}, services.ErrNodeNotFound
+			}
+			return models.ImpactGraph{
+				Root:      nodeID,
+				Direction: direction,
+				Depth:     depth,
+				Nodes: []models.ImpactNode{
+					{Constant: models.Constant{ID: "a.js#A"}, Distance: 0},
+					{Constant: models.Constant{ID: "b.js#B"}, Distance: 1},
+				},
+				Edges: []models.Dependency{{Source: "b.js#B", Target: "a.js#A"}},
+			}, nil
+		},
+	}
+
+	handler := &Handler{
+		DependencyService: mockDependencyService,
+	}
+
+	req := httptest.NewRequest("GET", "/api/impact?node=a.js%23A&direction=down&depth=2", nil)
+	rec := httptest.NewRecorder()
+	handler.HandleImpact(rec, req)
+
+	var response models.ImpactGraph
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+
+	if response.Root != "a.js#A" || response.Direction != models.ImpactDown || response.Depth != 2 {
+		t.Errorf("Ожидаются параметры запроса в ответе, получено: %+v", response)
+	}
+	if len(response.Nodes) != 2 || response.Nodes[1].Distance != 1 {
+		t.Errorf("Ожидается 2 узла с расстояниями, получено: %+v", response.Nodes)
+	}
+
+	// Ошибочные запросы
+	tests := []struct {
+		url    string
+		status int
+	}{
+		{"/api/impact", http.StatusBadRequest},
+		{"/api/impact?node=a.js%23A&depth=-1", http.StatusBadRequest},
+		{"/api/impact?node=a.js%23A&depth=x", http.StatusBadRequest},
+		{"/api/impact?node=missing", http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		handler.HandleImpact(rec, httptest.NewRequest("GET", test.url, nil))
+		if rec.Code != test.status {
+			t.Errorf("Для %s ожидается статус %d, получено: %d", test.url, test.status, rec.Code)
+		}
+	}
+}
+
+func TestHandleExport(t *testing.T) {
+	var requestedFile string
+	mockDependencyService := &MockDependencyService{
+		GetFileDependenciesFunc: func(filePath string) models.DependencyGraph {
+			requestedFile = filePath
+			return models.DependencyGraph{
+				Nodes: []models.Constant{{ID: "a.js#A", Name: "A", Type: "number", LineNum: 1}},
+			}
+		},
+	}
+
+	handler := &Handler{
+		DependencyService: mockDependencyService,
+	}
+
+	req := httptest.NewRequest("GET", "/api/export?format=dot&file=a.js", nil)
+	rec := httptest.NewRecorder()
+	handler.HandleExport(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Ожидается статус %d, получено: %d", http.StatusOK, rec.Code)
+	}
+	if contentType := rec.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/vnd.graphviz") {
+		t.Errorf("Ожидается Content-Type text/vnd.graphviz, получено: %s", contentType)
+	}
+	if requestedFile != "a.js" {
+		t.Errorf("Ожидается запрос зависимостей файла a.js, получено: %q", requestedFile)
+	}
+	if !strings.Contains(rec.Body.String(), `"a.js#A"`) {
+		t.Errorf("Ожидается узел a.js#A в выводе, получено:\n%s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.HandleExport(rec, httptest.NewRequest("GET", "/api/export?format=pdf", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Ожидается статус %d для неизвестного формата, получено: %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestHandleViolations(t *testing.T) {
+	rules := []config.Rule{{Name: "ui-no-db", From: []string{"src/ui/"}, Deny: []string{"src/db/"}, Severity: config.SeverityError}}
+
+	var received []config.Rule
+	handler := &Handler{
+		Rules: rules,
+		DependencyService: &MockDependencyService{
+			CheckRulesFunc: func(rules []config.Rule) (models.ViolationReport, error) {
+				received = rules
+				return models.ViolationReport{
+					Rules:  1,
+					Errors: 1,
+					Violations: []models.Violation{{
+						Rule: "ui-no-db", Severity: config.SeverityError, Kind: models.ViolationModule,
+						Source: "src/ui/button.ts", Target: "src/db/client.ts",
+						Location: models.Location{File: "src/ui/button.ts", Line: 3, Column: 24, EndLine: 3, EndColumn: 38},
+					}},
+				}, nil
+			},
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	handler.HandleViolations(rec, httptest.NewRequest("GET", "/api/violations", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Ожидается статус %d, получен %d", http.StatusOK, rec.Code)
+	}
+	if len(received) != 1 || received[0].Name != "ui-no-db" {
+		t.Errorf("Ожидается, что в сервис переданы правила из конфигурации, получено: %+v", received)
+	}
+
+	var response models.ViolationReport
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	if response.Errors != 1 || len(response.Violations) != 1 || response.Violations[0].Line != 3 {
+		t.Errorf("Неверный ответ: %+v", response)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.HandleViolations(rec, httptest.NewRequest("GET", "/api/violations?format=sarif", nil))
+	if contentType := rec.Header().Get("Content-Type"); contentType != sarif.ContentType {
+		t.Errorf("Ожидается тип %s, получен %s", sarif.ContentType, contentType)
+	}
+	var log sarif.Log
+	if err := json.NewDecoder(rec.Body).Decode(&log); err != nil {
+		t.Fatalf("Ошибка декодирования отчета SARIF: %v", err)
+	}
+	if len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 || log.Runs[0].Results[0].RuleID != "ui-no-db" {
+		t.Errorf("Неверный отчет SARIF: %+v", log)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.HandleViolations(rec, httptest.NewRequest("GET", "/api/violations?format=xml", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Ожидается статус %d для неизвестного формата, получен %d", http.StatusBadRequest, rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.HandleViolations(rec, httptest.NewRequest("POST", "/api/violations", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Ожидается статус %d для POST, получен %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
@@ -1,78 +1,186 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
-	"log"
-	"net/http"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
-	"github.com/avor0n/dependency-graph-visualizer/handlers"
+	"github.com/avor0n/dependency-graph-visualizer/config"
 	"github.com/avor0n/dependency-graph-visualizer/services"
 	"github.com/avor0n/dependency-graph-visualizer/utils"
 )
 
+// Коды завершения программы
+const (
+	exitOK    = 0 // Успешное выполнение
+	exitError = 1 // Ошибка выполнения или найдены циклы (cycles)
+	exitUsage = 2 // Неверные аргументы командной строки
+)
+
+// command описывает подкоманду CLI
+type command struct {
+	name        string
+	description string
+	// run выполняет команду и возвращает код завершения
+	run func(args []string, stdout, stderr io.Writer) int
+}
+
+// commands содержит подкоманды в порядке вывода в справке
+var commands = []command{
+	{"serve", "Запустить веб-интерфейс и HTTP API", runServe},
+	{"analyze", "Вывести граф зависимостей в формате JSON", runAnalyze},
+	{"export", "Вывести граф в формате dot, mermaid, plantuml, graphml или gexf", runExport},
+	{"cycles", "Вывести циклические зависимости (код 1, если циклы найдены)", runCycles},
+	{"stats", "Вывести сводные показатели графа", runStats},
+	{"query", "Вывести константы, зависящие от константы или от которых она зависит", runQuery},
+	{"check", "Проверить правила зависимостей из " + config.FileName + " (код 1 при ошибках)", runCheck},
+}
+
 func main() {
-	// Определяем флаг командной строки для пути к проекту
-	projectPathPtr := flag.String("path", "", "Путь к JavaScript/TypeScript проекту")
-	flag.Parse()
-
-	if *projectPathPtr == "" {
-		fmt.Println("Ошибка: необходимо указать путь к проекту с помощью флага -path")
-		fmt.Println("Пример: ./dependency-graph-visualizer -path /path/to/js/project")
-		os.Exit(1)
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run выбирает подкоманду по первому аргументу. Без подкоманды выполняется serve,
+// чтобы сохранить прежний запуск: ./dependency-graph-visualizer -path /path/to/project
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "-help" || args[0] == "--help") {
+			printUsage(stdout)
+			return exitOK
+		}
+		return runServe(args, stdout, stderr)
 	}
 
-	// Проверяем, существует ли директория
-	fileInfo, err := os.Stat(*projectPathPtr)
+	name := args[0]
+	if name == "help" {
+		printUsage(stdout)
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(args[1:], stdout, stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "Ошибка: неизвестная команда %q\n\n", name)
+	printUsage(stderr)
+	return exitUsage
+}
+
+// printUsage выводит список подкоманд
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Использование: dependency-graph-visualizer <команда> -path /path/to/js/project [флаги]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Команды:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-8s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Флаги команды: dependency-graph-visualizer <команда> -h")
+}
+
+// newFlagSet создает набор флагов подкоманды с обязательным флагом -path
+func newFlagSet(name string, stderr io.Writer) (*flag.FlagSet, *string) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	projectPath := flags.String("path", "", "Путь к JavaScript/TypeScript проекту (можно указать первым позиционным аргументом)")
+	return flags, projectPath
+}
+
+// parseFlags разбирает аргументы подкоманды. Путь к проекту можно передать
+// флагом -path или позиционным аргументом. Возвращает код завершения,
+// если выполнение нужно прекратить.
+func parseFlags(flags *flag.FlagSet, projectPath *string, args []string) ([]string, int, bool) {
+	// Флаги можно указывать и после позиционных аргументов
+	var rest []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, exitOK, false
+			}
+			return nil, exitUsage, false
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			break
+		}
+		rest = append(rest, args[0])
+		args = args[1:]
+	}
+
+	if *projectPath == "" && len(rest) > 0 {
+		*projectPath, rest = rest[0], rest[1:]
+	}
+	if *projectPath == "" {
+		fmt.Fprintln(flags.Output(), "Ошибка: необходимо указать путь к проекту с помощью флага -path")
+		fmt.Fprintf(flags.Output(), "Пример: ./dependency-graph-visualizer %s -path /path/to/js/project\n", flags.Name())
+		return nil, exitUsage, false
+	}
+
+	return rest, exitOK, true
+}
+
+// project содержит настройки и сервисы проанализированного проекта
+type project struct {
+	config            *config.Config
+	fileService       *services.FileService
+	dependencyService *services.DependencyService
+}
+
+// loadProject проверяет путь к проекту, читает его конфигурацию и строит
+// граф зависимостей. Ход анализа выводится в stderr.
+func loadProject(path string, stderr io.Writer) (*project, error) {
+	project, err := openProject(path, stderr)
 	if err != nil {
-		fmt.Println("Ошибка: указанный путь не существует")
-		os.Exit(1)
+		return nil, err
 	}
+	project.dependencyService.BuildDependencyGraph()
+	return project, nil
+}
 
+// openProject проверяет путь к проекту, читает его конфигурацию и создает
+// сервисы анализа, не строя граф зависимостей
+func openProject(path string, stderr io.Writer) (*project, error) {
+	// Проверяем, существует ли директория
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("указанный путь не существует")
+	}
 	if !fileInfo.IsDir() {
-		fmt.Println("Ошибка: указанный путь не является директорией")
-		os.Exit(1)
+		return nil, fmt.Errorf("указанный путь не является директорией")
 	}
 
 	// Сохраняем абсолютный путь к проекту
-	projectPath, err := filepath.Abs(*projectPathPtr)
+	projectPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении абсолютного пути: %w", err)
+	}
+
+	// Читаем настройки анализатора из .depgraph.json
+	cfg, err := config.Load(projectPath)
 	if err != nil {
-		fmt.Println("Ошибка при получении абсолютного пути:", err)
-		os.Exit(1)
+		return nil, err
 	}
 
-	fmt.Printf("Запуск визуализатора для проекта: %s\n", projectPath)
+	fmt.Fprintf(stderr, "Анализ зависимостей в проекте: %s\n", projectPath)
 
 	// Загружаем правила .gitignore
 	gitIgnore := utils.LoadGitIgnore(projectPath)
 
 	// Инициализируем сервисы
 	fileService := services.NewFileService(projectPath, gitIgnore)
+	fileService.Configure(cfg)
 	dependencyService := services.NewDependencyService(fileService)
+	dependencyService.Configure(cfg)
 
-	// Анализируем зависимости перед запуском сервера
-	fmt.Println("Анализ зависимостей в проекте...")
-	dependencyService.BuildDependencyGraph()
-
-	// Инициализируем обработчики с указателями на сервисы
-	handler := &handlers.Handler{
-		FileService:       fileService,
-		DependencyService: dependencyService,
-		ProjectPath:       projectPath,
-	}
-
-	// Регистрируем API endpoints
-	http.HandleFunc("/api/project-info", handler.HandleProjectInfo)
-	http.HandleFunc("/api/file-tree", handler.HandleFileTree)
-	http.HandleFunc("/api/dependency-graph", handler.HandleDependencyGraph)
-	http.HandleFunc("/api/file-dependencies", handler.HandleFileDependencies)
-
-	// Указываем статическую директорию для фронтенда
-	fs := http.FileServer(http.Dir("../frontend/dist"))
-	http.Handle("/", handlers.EnableCORS(fs))
-
-	log.Println("Сервер запущен на http://localhost:8080")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	return &project{
+		config:            cfg,
+		fileService:       fileService,
+		dependencyService: dependencyService,
+	}, nil
 }
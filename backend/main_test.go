@@ -1,26 +1,23 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
-)
 
-// Примечание: тестирование функции main напрямую затруднительно,
-// поскольку она вызывает os.Exit() и http.ListenAndServe().
-// В реальном проекте лучше выделять логику в отдельные функции,
-// которые можно тестировать независимо.
+	"github.com/avor0n/dependency-graph-visualizer/config"
+	"github.com/avor0n/dependency-graph-visualizer/handlers"
+	"github.com/avor0n/dependency-graph-visualizer/models"
+	"github.com/avor0n/dependency-graph-visualizer/sarif"
+)
 
 // TestHTTPHandlersRegistration проверяет регистрацию HTTP-обработчиков
 func TestHTTPHandlersRegistration(t *testing.T) {
-	// Создаем тестовый ServeMux
-	mux := http.NewServeMux()
-
-	// Регистрируем фиктивные обработчики для проверки валидности путей
-	mux.HandleFunc("/api/project-info", func(w http.ResponseWriter, r *http.Request) {})
-	mux.HandleFunc("/api/file-tree", func(w http.ResponseWriter, r *http.Request) {})
-	mux.HandleFunc("/api/dependency-graph", func(w http.ResponseWriter, r *http.Request) {})
-	mux.HandleFunc("/api/file-dependencies", func(w http.ResponseWriter, r *http.Request) {})
-	mux.Handle("/", http.FileServer(http.Dir(".")))
+	mux := newServeMux(&handlers.Handler{}, nil)
 
 	// Проверяем, что все ожидаемые пути обрабатываются
 	paths := []string{
@@ -28,6 +25,13 @@ func TestHTTPHandlersRegistration(t *testing.T) {
 		"/api/file-tree",
 		"/api/dependency-graph",
 		"/api/file-dependencies",
+		"/api/module-graph",
+		"/api/cycles",
+		"/api/impact",
+		"/api/aggregate",
+		"/api/events",
+		"/api/export",
+		"/api/violations",
 		"/",
 	}
 
@@ -37,9 +41,323 @@ func TestHTTPHandlersRegistration(t *testing.T) {
 			t.Fatalf("Ошибка создания запроса для %s: %v", path, err)
 		}
 
-		handler, _ := mux.Handler(req)
-		if handler == nil {
-			t.Errorf("Не найден обработчик для пути %s", path)
+		_, pattern := mux.Handler(req)
+		if pattern != path {
+			t.Errorf("Не найден обработчик для пути %s (совпал шаблон %q)", path, pattern)
 		}
 	}
 }
+
+// createTestProject создает проект с циклической зависимостью между модулями
+func createTestProject(t *testing.T) string {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "cli-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	files := map[string]string{
+		"config.js": "export const API_URL = 'https://example.com';\nexport const TIMEOUT = 1000;\n",
+		"api.js":    "import { API_URL, TIMEOUT } from './config';\nexport const ENDPOINT = API_URL + '/v1';\nexport const LIMIT = TIMEOUT;\n",
+		"a.js":      "import { B } from './b';\nexport const A = 1;\n",
+		"b.js":      "import { A } from './a';\nexport const B = 2;\n",
+		"other.js":  "export const LIMIT = 5;\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Не удалось создать файл %s: %v", name, err)
+		}
+	}
+
+	return dir
+}
+
+// runCLI выполняет run и возвращает код завершения и вывод
+func runCLI(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// TestRunUsage проверяет справку и обработку неверных аргументов
+func TestRunUsage(t *testing.T) {
+	code, stdout, _ := runCLI("help")
+	if code != exitOK {
+		t.Errorf("Ожидается код %d для help, получен %d", exitOK, code)
+	}
+	for _, cmd := range commands {
+		if !strings.Contains(stdout, cmd.name) {
+			t.Errorf("Ожидается команда %s в справке:\n%s", cmd.name, stdout)
+		}
+	}
+
+	if code, _, _ := runCLI("unknown"); code != exitUsage {
+		t.Errorf("Ожидается код %d для неизвестной команды, получен %d", exitUsage, code)
+	}
+	if code, _, stderr := runCLI("stats"); code != exitUsage || !strings.Contains(stderr, "-path") {
+		t.Errorf("Ожидается код %d и подсказка о -path без пути, получено %d: %s", exitUsage, code, stderr)
+	}
+	if code, _, _ := runCLI("stats", "-path", "/nonexistent/project"); code != exitError {
+		t.Errorf("Ожидается код %d для несуществующего пути, получен %d", exitError, code)
+	}
+}
+
+// TestRunAnalyze проверяет вывод графа в формате JSON
+func TestRunAnalyze(t *testing.T) {
+	dir := createTestProject(t)
+
+	code, stdout, stderr := runCLI("analyze", dir)
+	if code != exitOK {
+		t.Fatalf("Ожидается код %d, получен %d: %s", exitOK, code, stderr)
+	}
+
+	var graph models.DependencyGraph
+	if err := json.Unmarshal([]byte(stdout), &graph); err != nil {
+		t.Fatalf("Ошибка разбора JSON: %v\n%s", err, stdout)
+	}
+	if len(graph.Nodes) != 7 {
+		t.Errorf("Ожидается 7 констант, получено %d", len(graph.Nodes))
+	}
+	if strings.Contains(stdout, "Анализ зависимостей") {
+		t.Error("Ход анализа не должен попадать в stdout")
+	}
+
+	code, stdout, _ = runCLI("analyze", "-path", dir, "-file", "api.js")
+	if code != exitOK {
+		t.Fatalf("Ожидается код %d, получен %d", exitOK, code)
+	}
+	if err := json.Unmarshal([]byte(stdout), &graph); err != nil {
+		t.Fatalf("Ошибка разбора JSON: %v", err)
+	}
+	if len(graph.Nodes) != 4 {
+		t.Errorf("Ожидается 4 константы для api.js и его зависимостей, получено %d", len(graph.Nodes))
+	}
+}
+
+// TestRunExport проверяет экспорт графа в stdout и в файл
+func TestRunExport(t *testing.T) {
+	dir := createTestProject(t)
+
+	code, stdout, _ := runCLI("export", "-format", "mermaid", dir)
+	if code != exitOK || !strings.HasPrefix(stdout, "flowchart LR") {
+		t.Errorf("Ожидается граф Mermaid, получен код %d:\n%s", code, stdout)
+	}
+
+	output := filepath.Join(dir, "graph.dot")
+	if code, _, stderr := runCLI("export", "-path", dir, "-output", output); code != exitOK {
+		t.Fatalf("Ожидается код %d, получен %d: %s", exitOK, code, stderr)
+	}
+	content, err := os.ReadFile(output)
+	if err != nil || !strings.HasPrefix(string(content), "digraph") {
+		t.Errorf("Ожидается граф DOT в файле, получено %q (%v)", content, err)
+	}
+
+	if code, _, _ := runCLI("export", "-format", "svg", dir); code != exitUsage {
+		t.Errorf("Ожидается код %d для неизвестного формата, получен %d", exitUsage, code)
+	}
+	if code, _, _ := runCLI("export", "-file", "a.js", "-dir", "src", dir); code != exitUsage {
+		t.Errorf("Ожидается код %d для -file вместе с -dir, получен %d", exitUsage, code)
+	}
+}
+
+// TestRunCycles проверяет код завершения и JSON-отчет о циклах
+func TestRunCycles(t *testing.T) {
+	dir := createTestProject(t)
+
+	code, stdout, _ := runCLI("cycles", dir, "-json")
+	if code != exitError {
+		t.Errorf("Ожидается код %d при найденных циклах, получен %d", exitError, code)
+	}
+	var report models.CycleReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("Ошибка разбора JSON: %v\n%s", err, stdout)
+	}
+	if len(report.Modules) != 1 {
+		t.Errorf("Ожидается 1 цикл между модулями, получено %d", len(report.Modules))
+	}
+
+	code, stdout, _ = runCLI("cycles", dir, "-sarif")
+	var sarifLog sarif.Log
+	if err := json.Unmarshal([]byte(stdout), &sarifLog); err != nil {
+		t.Fatalf("Ошибка разбора SARIF: %v\n%s", err, stdout)
+	}
+	if code != exitError || len(sarifLog.Runs) != 1 || len(sarifLog.Runs[0].Results) != 1 {
+		t.Fatalf("Ожидается 1 результат SARIF и код %d, получено %d: %+v", exitError, code, sarifLog)
+	}
+	region := sarifLog.Runs[0].Results[0].Locations[0].PhysicalLocation.Region
+	if region == nil || region.StartLine != 1 || region.StartColumn != 19 {
+		t.Errorf("Ожидается место импорта в a.js, получено: %+v", sarifLog.Runs[0].Results[0].Locations)
+	}
+
+	if code, _, _ := runCLI("cycles", dir, "-json", "-sarif"); code != exitUsage {
+		t.Errorf("Ожидается код %d для -json вместе с -sarif, получен %d", exitUsage, code)
+	}
+
+	os.Remove(filepath.Join(dir, "b.js"))
+	if code, stdout, _ := runCLI("cycles", dir); code != exitOK {
+		t.Errorf("Ожидается код %d без циклов, получен %d:\n%s", exitOK, code, stdout)
+	}
+}
+
+// TestRunStats проверяет вывод сводных показателей
+func TestRunStats(t *testing.T) {
+	dir := createTestProject(t)
+
+	code, stdout, _ := runCLI("stats", "-json", dir)
+	if code != exitOK {
+		t.Fatalf("Ожидается код %d, получен %d", exitOK, code)
+	}
+	var stats models.GraphStats
+	if err := json.Unmarshal([]byte(stdout), &stats); err != nil {
+		t.Fatalf("Ошибка разбора JSON: %v\n%s", err, stdout)
+	}
+	if stats.Files != 5 || stats.Constants != 7 || stats.ModuleCycles != 1 {
+		t.Errorf("Неверные показатели: %+v", stats)
+	}
+
+	code, stdout, _ = runCLI("stats", dir)
+	if code != exitOK || !strings.Contains(stdout, "config.js#API_URL") {
+		t.Errorf("Ожидается список часто используемых констант, получен код %d:\n%s", code, stdout)
+	}
+}
+
+// TestRunQuery проверяет поиск зависимых констант по идентификатору и имени
+func TestRunQuery(t *testing.T) {
+	dir := createTestProject(t)
+
+	code, stdout, stderr := runCLI("query", dir, "API_URL", "-depth", "1")
+	if code != exitOK {
+		t.Fatalf("Ожидается код %d, получен %d: %s", exitOK, code, stderr)
+	}
+	if !strings.Contains(stdout, "api.js#ENDPOINT") {
+		t.Errorf("Ожидается api.js#ENDPOINT среди зависящих констант:\n%s", stdout)
+	}
+
+	code, stdout, _ = runCLI("query", "-path", dir, "-node", "api.js#ENDPOINT", "-direction", "down", "-json")
+	if code != exitOK {
+		t.Fatalf("Ожидается код %d, получен %d", exitOK, code)
+	}
+	var impact models.ImpactGraph
+	if err := json.Unmarshal([]byte(stdout), &impact); err != nil {
+		t.Fatalf("Ошибка разбора JSON: %v", err)
+	}
+	if len(impact.Nodes) != 2 {
+		t.Errorf("Ожидается 2 узла (ENDPOINT и API_URL), получено %d", len(impact.Nodes))
+	}
+
+	// Имя LIMIT объявлено в двух файлах
+	code, _, stderr = runCLI("query", dir, "LIMIT")
+	if code != exitUsage || !strings.Contains(stderr, "other.js#LIMIT") {
+		t.Errorf("Ожидается код %d и список кандидатов, получено %d: %s", exitUsage, code, stderr)
+	}
+	if code, _, _ := runCLI("query", dir, "MISSING"); code != exitError {
+		t.Errorf("Ожидается код %d для неизвестной константы, получен %d", exitError, code)
+	}
+	if code, _, _ := runCLI("query", dir); code != exitUsage {
+		t.Errorf("Ожидается код %d без константы, получен %d", exitUsage, code)
+	}
+}
+
+// TestPrintCycles проверяет текстовый отчет о циклах
+func TestPrintCycles(t *testing.T) {
+	var out bytes.Buffer
+	printCycles(&out, models.CycleReport{})
+	if !strings.Contains(out.String(), "не найдены") {
+		t.Errorf("Ожидается сообщение об отсутствии циклов, получено: %q", out.String())
+	}
+
+	out.Reset()
+	printCycles(&out, models.CycleReport{
+		Modules: []models.Cycle{{
+			Nodes: []string{"a.js", "b.js"},
+			Edges: []models.Dependency{
+				{Source: "a.js", Target: "b.js"},
+				{Source: "b.js", Target: "a.js"},
+			},
+			Locations: []models.Location{
+				{File: "a.js", Line: 1, Column: 19},
+				{File: "b.js", Line: 2, Column: 19},
+			},
+		}},
+	})
+	for _, expected := range []string{"между модулями: 1", "a.js -> b.js (a.js:1:19)", "b.js -> a.js (b.js:2:19)"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Ожидается %q в отчете, получено:\n%s", expected, out.String())
+		}
+	}
+}
+
+// TestRunConfig проверяет применение и проверку .depgraph.json
+func TestRunConfig(t *testing.T) {
+	dir := createTestProject(t)
+
+	configPath := filepath.Join(dir, config.FileName)
+	if err := os.WriteFile(configPath, []byte(`{"exclude": ["a.js", "b.js"]}`), 0644); err != nil {
+		t.Fatalf("Не удалось создать файл конфигурации: %v", err)
+	}
+	if code, _, stderr := runCLI("cycles", dir); code != exitOK {
+		t.Errorf("Ожидается код %d, если файлы цикла исключены, получен %d: %s", exitOK, code, stderr)
+	}
+
+	if err := os.WriteFile(configPath, []byte(`{"workers": 2, "exlude": []}`), 0644); err != nil {
+		t.Fatalf("Не удалось обновить файл конфигурации: %v", err)
+	}
+	code, _, stderr := runCLI("stats", dir)
+	if code != exitError || !strings.Contains(stderr, config.FileName) || !strings.Contains(stderr, "exlude в строке 1") {
+		t.Errorf("Ожидается код %d и ошибка с именем параметра, получено %d: %s", exitError, code, stderr)
+	}
+}
+
+// TestRunCheck проверяет код завершения и вывод проверки правил
+func TestRunCheck(t *testing.T) {
+	dir := createTestProject(t)
+
+	if code, stdout, _ := runCLI("check", dir); code != exitOK || !strings.Contains(stdout, "не заданы") {
+		t.Errorf("Без правил ожидается код %d, получено %d:\n%s", exitOK, code, stdout)
+	}
+
+	configPath := filepath.Join(dir, config.FileName)
+	rules := `{"rules": [{"name": "no-config", "from": ["api.js"], "deny": ["config.js"], "message": "используйте env"}]}`
+	if err := os.WriteFile(configPath, []byte(rules), 0644); err != nil {
+		t.Fatalf("Не удалось создать файл конфигурации: %v", err)
+	}
+
+	code, stdout, _ := runCLI("check", dir)
+	if code != exitError {
+		t.Errorf("Ожидается код %d при нарушениях, получен %d", exitError, code)
+	}
+	for _, expected := range []string{"api.js:1:34: error [no-config] api.js -> config.js: используйте env", "ошибок: 3"} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("Ожидается %q в выводе:\n%s", expected, stdout)
+		}
+	}
+
+	code, stdout, _ = runCLI("check", dir, "-json")
+	var report models.ViolationReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("Ошибка разбора JSON: %v\n%s", err, stdout)
+	}
+	if code != exitError || len(report.Violations) != 3 {
+		t.Errorf("Ожидается 3 нарушения и код %d, получено %d: %+v", exitError, code, report)
+	}
+
+	code, stdout, _ = runCLI("check", dir, "-sarif")
+	var sarifLog sarif.Log
+	if err := json.Unmarshal([]byte(stdout), &sarifLog); err != nil {
+		t.Fatalf("Ошибка разбора SARIF: %v\n%s", err, stdout)
+	}
+	if code != exitError || len(sarifLog.Runs[0].Results) != 3 || sarifLog.Runs[0].Tool.Driver.Rules[0].ID != "no-config" {
+		t.Errorf("Ожидается 3 результата SARIF по правилу no-config и код %d, получено %d: %+v", exitError, code, sarifLog)
+	}
+
+	// Предупреждения не приводят к ненулевому коду
+	rules = strings.Replace(rules, `"message"`, `"severity": "warning", "message"`, 1)
+	if err := os.WriteFile(configPath, []byte(rules), 0644); err != nil {
+		t.Fatalf("Не удалось обновить файл конфигурации: %v", err)
+	}
+	if code, _, _ := runCLI("check", dir); code != exitOK {
+		t.Errorf("Ожидается код %d при одних предупреждениях, получен %d", exitOK, code)
+	}
+}
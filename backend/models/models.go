@@ -1,5 +1,9 @@
 package models
 
+import (
+	"path/filepath"
+)
+
 // FileNode представляет узел в дереве файлов
 type FileNode struct {
 	Name     string     `json:"name"`
@@ -8,19 +12,83 @@ type FileNode struct {
 	Children []FileNode `json:"children,omitempty"`
 }
 
-// Constant представляет константу в коде
-type Constant struct {
-	Name     string `json:"name"`     // Имя константы
-	Value    string `json:"value"`    // Значение константы
-	Type     string `json:"type"`     // Тип константы
-	FilePath string `json:"filePath"` // Путь к файлу, где объявлена константа
-	LineNum  int    `json:"lineNum"`  // Номер строки в файле
+// Position представляет позицию в исходном файле
+type Position struct {
+	Line   int `json:"line"`   // Номер строки (с 1)
+	Column int `json:"column"` // Номер колонки (с 1)
+	Offset int `json:"offset"` // Смещение от начала файла в байтах
+}
+
+// Location указывает на фрагмент исходного файла
+type Location struct {
+	File      string `json:"file"`      // Путь к файлу относительно корня проекта
+	Line      int    `json:"line"`      // Строка начала фрагмента (с 1)
+	Column    int    `json:"column"`    // Колонка начала фрагмента (с 1)
+	EndLine   int    `json:"endLine"`   // Строка конца фрагмента
+	EndColumn int    `json:"endColumn"` // Колонка сразу после конца фрагмента
+}
+
+// NewLocation создает фрагмент файла между позициями start и end
+func NewLocation(file string, start, end Position) Location {
+	return Location{File: file, Line: start.Line, Column: start.Column, EndLine: end.Line, EndColumn: end.Column}
+}
+
+// Виды символов верхнего уровня модуля
+const (
+	SymbolConst     = "const"     // const NAME = ...
+	SymbolLet       = "let"       // let NAME
+	SymbolVar       = "var"       // var NAME
+	SymbolFunction  = "function"  // function NAME() {} или const NAME = () => {}
+	SymbolClass     = "class"     // class NAME {} или const NAME = class {}
+	SymbolEnum      = "enum"      // enum NAME {} (TypeScript)
+	SymbolInterface = "interface" // interface NAME {} (TypeScript)
+	SymbolType      = "type"      // type NAME = ... (TypeScript)
+)
+
+// Symbol представляет объявление верхнего уровня в коде: переменную, функцию,
+// класс, перечисление, интерфейс или псевдоним типа
+type Symbol struct {
+	ID         string   `json:"id"`         // Уникальный идентификатор символа в проекте
+	Name       string   `json:"name"`       // Имя символа
+	Kind       string   `json:"kind"`       // Вид объявления (SymbolConst, SymbolFunction, ...)
+	Value      string   `json:"value"`      // Инициализатор, определение типа или заголовок функции и класса
+	Type       string   `json:"type"`       // Тип символа
+	FilePath   string   `json:"filePath"`   // Путь к файлу, где объявлен символ
+	LineNum    int      `json:"lineNum"`    // Номер строки в файле
+	NameStart  Position `json:"nameStart"`  // Начало имени в объявлении
+	NameEnd    Position `json:"nameEnd"`    // Конец имени в объявлении (не включительно)
+	ValueStart Position `json:"valueStart"` // Начало значения
+	ValueEnd   Position `json:"valueEnd"`   // Конец значения (не включительно)
 }
 
+// Constant - прежнее имя Symbol, когда граф содержал только константы
+type Constant = Symbol
+
+// Виды зависимостей между константами
+const (
+	DependencyLocal  = "local"  // Ссылка на константу из того же файла
+	DependencyImport = "import" // Ссылка на константу из другого модуля через import/export
+)
+
 // Dependency представляет зависимость между константами
 type Dependency struct {
-	Source string `json:"source"` // Имя исходной константы
-	Target string `json:"target"` // Имя целевой константы
+	Source string `json:"source"` // Идентификатор исходной константы
+	Target string `json:"target"` // Идентификатор целевой константы
+	Kind   string `json:"kind"`   // Вид зависимости (DependencyLocal, DependencyImport)
+}
+
+// ModuleScope обозначает область видимости верхнего уровня модуля
+const ModuleScope = ""
+
+// NewConstantID формирует идентификатор константы из пути к файлу относительно
+// корня проекта, области видимости и имени: "src/config.ts#API_URL"
+// или "src/config.ts#scope.name" для вложенных областей видимости
+func NewConstantID(relPath, scope, name string) string {
+	id := filepath.ToSlash(relPath) + "#"
+	if scope != ModuleScope {
+		id += scope + "."
+	}
+	return id + name
 }
 
 // DependencyGraph представляет граф зависимостей
@@ -28,3 +96,196 @@ type DependencyGraph struct {
 	Nodes []Constant   `json:"nodes"` // Узлы графа (константы)
 	Edges []Dependency `json:"edges"` // Ребра графа (зависимости)
 }
+
+// Виды импортов между модулями
+const (
+	ImportStatic     = "static"      // import ... from, export ... from или require()
+	ImportDynamic    = "dynamic"     // import()
+	ImportTypeOnly   = "type-only"   // Импорт только типов, удаляемый при компиляции
+	ImportSideEffect = "side-effect" // import './styles.css' без привязки имен
+)
+
+// ImportKinds перечисляет все виды импортов
+var ImportKinds = []string{ImportStatic, ImportDynamic, ImportTypeOnly, ImportSideEffect}
+
+// ModuleNode представляет файл проекта в графе модулей
+type ModuleNode struct {
+	ID          string `json:"id"`                    // Путь к файлу относительно корня проекта
+	Name        string `json:"name"`                  // Имя файла
+	Entry       bool   `json:"entry,omitempty"`       // Файл указан в entryPoints конфигурации проекта
+	Unreachable bool   `json:"unreachable,omitempty"` // Файл не импортируется из точек входа ни напрямую, ни транзитивно
+	Unanalyzed  bool   `json:"unanalyzed,omitempty"`  // Импортируемый файл, который не анализируется: стили, JSON, исключенные файлы
+}
+
+// ModuleEdge представляет одну инструкцию импорта или реэкспорта между файлами
+type ModuleEdge struct {
+	Source   string   `json:"source"`   // Импортирующий файл
+	Target   string   `json:"target"`   // Импортируемый файл
+	Kind     string   `json:"kind"`     // Вид импорта (ImportStatic, ImportDynamic, ImportTypeOnly, ImportSideEffect)
+	ReExport bool     `json:"reExport"` // Инструкция export ... from
+	Lazy     bool     `json:"lazy"`     // Динамический импорт внутри lazy(() => import(...)): отложенно загружаемый маршрут
+	Symbols  []string `json:"symbols"`  // Импортируемые имена: "default", "*" для пространства имен или имя экспорта
+	Line     int      `json:"line"`     // Строка инструкции
+}
+
+// DiagnosticUnresolvableDynamic - вызов import(), модуль которого вычисляется
+// во время выполнения (шаблонная строка с подстановками или выражение)
+const DiagnosticUnresolvableDynamic = "unresolvable-dynamic"
+
+// ModuleDiagnostic представляет импорт, который не удалось отразить в графе модулей
+type ModuleDiagnostic struct {
+	Kind       string `json:"kind"`       // Вид диагностики (DiagnosticUnresolvableDynamic)
+	Expression string `json:"expression"` // Исходный текст аргумента import()
+
+	// Положение аргумента в исходном файле
+	Location
+}
+
+// ModuleGraph представляет граф импортов между файлами проекта
+type ModuleGraph struct {
+	Nodes       []ModuleNode       `json:"nodes"`       // Файлы проекта
+	Edges       []ModuleEdge       `json:"edges"`       // Импорты между файлами
+	Diagnostics []ModuleDiagnostic `json:"diagnostics"` // Импорты, не попавшие в граф
+}
+
+// Cycle представляет цикл зависимостей: сильно связанную компоненту графа
+type Cycle struct {
+	Nodes     []string     `json:"nodes"`     // Идентификаторы узлов, входящих в цикл
+	Edges     []Dependency `json:"edges"`     // Ребра между узлами цикла
+	Locations []Location   `json:"locations"` // Места в коде, создающие ребра (по одному на ребро из Edges)
+}
+
+// CycleReport содержит циклы между константами и между модулями
+type CycleReport struct {
+	Constants []Cycle `json:"constants"` // Циклы в графе констант
+	Modules   []Cycle `json:"modules"`   // Циклы в графе импортов между файлами
+}
+
+// HasCycles проверяет, найден ли хотя бы один цикл
+func (r CycleReport) HasCycles() bool {
+	return len(r.Constants) > 0 || len(r.Modules) > 0
+}
+
+// Направления обхода графа при анализе влияния
+const (
+	ImpactUp   = "up"   // Константы, которые транзитивно зависят от узла
+	ImpactDown = "down" // Константы, от которых узел транзитивно зависит
+)
+
+// ImpactNode представляет константу, достижимую из исходного узла
+type ImpactNode struct {
+	Constant
+	Distance int `json:"distance"` // Число ребер от исходного узла
+}
+
+// ImpactGraph представляет подграф, достижимый из узла в выбранном направлении
+type ImpactGraph struct {
+	Root      string       `json:"root"`      // Идентификатор исходного узла
+	Direction string       `json:"direction"` // Направление обхода (ImpactUp, ImpactDown)
+	Depth     int          `json:"depth"`     // Ограничение глубины (0 - без ограничения)
+	Nodes     []ImpactNode `json:"nodes"`     // Достижимые узлы, включая исходный
+	Edges     []Dependency `json:"edges"`     // Ребра между достижимыми узлами
+}
+
+// Способы группировки констант в агрегированном графе
+const (
+	AggregateDirectory = "directory" // По директории, усеченной до заданной глубины
+	AggregatePackage   = "package"   // По ближайшему package.json
+	AggregateConstant  = "constant"  // Константа раскрытой группы
+)
+
+// AggregateNode представляет группу констант или константу раскрытой группы
+type AggregateNode struct {
+	ID            string `json:"id"`               // Путь директории или пакета относительно корня проекта ("." - корень) либо идентификатор константы
+	Label         string `json:"label"`            // Путь директории, имя пакета из package.json или имя константы
+	Kind          string `json:"kind"`             // AggregateDirectory, AggregatePackage или AggregateConstant
+	Parent        string `json:"parent,omitempty"` // Группа, в которую входит константа раскрытой группы
+	Constants     int    `json:"constants"`        // Число констант в группе
+	Files         int    `json:"files"`            // Число файлов с константами группы
+	InternalEdges int    `json:"internalEdges"`    // Число зависимостей между константами группы
+}
+
+// AggregateEdge представляет зависимости между группами
+type AggregateEdge struct {
+	Source string `json:"source"` // Группа или константа, которая зависит
+	Target string `json:"target"` // Группа или константа, от которой зависят
+	Weight int    `json:"weight"` // Число зависимостей между константами, объединенных в ребро
+}
+
+// AggregateGraph представляет граф, в котором константы объединены в группы
+type AggregateGraph struct {
+	By    string          `json:"by"`              // Способ группировки
+	Depth int             `json:"depth,omitempty"` // Глубина директорий для группировки по директориям
+	Nodes []AggregateNode `json:"nodes"`           // Группы и константы раскрытых групп
+	Edges []AggregateEdge `json:"edges"`           // Взвешенные зависимости между узлами
+}
+
+// GraphDelta описывает изменения графа после повторного анализа файлов
+type GraphDelta struct {
+	AddedNodes   []Constant   `json:"addedNodes"`   // Новые константы
+	ChangedNodes []Constant   `json:"changedNodes"` // Константы с измененным значением, типом или позицией
+	RemovedNodes []string     `json:"removedNodes"` // Идентификаторы удаленных констант
+	AddedEdges   []Dependency `json:"addedEdges"`   // Новые зависимости
+	RemovedEdges []Dependency `json:"removedEdges"` // Удаленные зависимости
+	AddedFiles   []string     `json:"addedFiles"`   // Добавленные файлы (пути относительно корня проекта)
+	RemovedFiles []string     `json:"removedFiles"` // Удаленные файлы (пути относительно корня проекта)
+}
+
+// Empty проверяет, что граф и дерево файлов не изменились
+func (d GraphDelta) Empty() bool {
+	return len(d.AddedNodes) == 0 && len(d.ChangedNodes) == 0 && len(d.RemovedNodes) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0 &&
+		len(d.AddedFiles) == 0 && len(d.RemovedFiles) == 0
+}
+
+// ConstantDegree содержит число констант, напрямую зависящих от константы
+type ConstantDegree struct {
+	ID         string `json:"id"`         // Идентификатор константы
+	Dependents int    `json:"dependents"` // Число входящих ребер
+}
+
+// GraphStats содержит сводные показатели графа зависимостей
+type GraphStats struct {
+	Files              int              `json:"files"`              // Число проанализированных файлов
+	Constants          int              `json:"constants"`          // Число констант
+	Dependencies       int              `json:"dependencies"`       // Число зависимостей
+	ConstantsByType    map[string]int   `json:"constantsByType"`    // Число констант каждого типа
+	SymbolsByKind      map[string]int   `json:"symbolsByKind"`      // Число символов каждого вида (SymbolConst, SymbolFunction, ...)
+	DependenciesByKind map[string]int   `json:"dependenciesByKind"` // Число зависимостей каждого вида
+	ConstantCycles     int              `json:"constantCycles"`     // Число циклов между константами
+	ModuleCycles       int              `json:"moduleCycles"`       // Число циклов между модулями
+	MostDependedOn     []ConstantDegree `json:"mostDependedOn"`     // Константы с наибольшим числом зависящих от них
+	UnreachableFiles   []string         `json:"unreachableFiles"`   // Файлы, не достижимые из точек входа (пусто без entryPoints)
+}
+
+// Виды зависимостей, которые проверяются правилами
+const (
+	ViolationModule   = "module"   // Импорт одного файла другим
+	ViolationConstant = "constant" // Зависимость константы от константы другого файла
+)
+
+// Violation описывает нарушение правила зависимостей
+type Violation struct {
+	Rule     string `json:"rule"`              // Имя правила
+	Severity string `json:"severity"`          // Важность: error или warning
+	Message  string `json:"message,omitempty"` // Пояснение из правила
+	Kind     string `json:"kind"`              // Вид зависимости: module или constant
+	Source   string `json:"source"`            // Зависимый файл или константа
+	Target   string `json:"target"`            // Файл или константа, от которых запрещено зависеть
+
+	// Место зависимости: строка модуля в импорте или имя зависимой константы
+	Location
+}
+
+// ViolationReport содержит результат проверки правил
+type ViolationReport struct {
+	Rules      int         `json:"rules"`      // Число проверенных правил
+	Errors     int         `json:"errors"`     // Число нарушений с важностью error
+	Warnings   int         `json:"warnings"`   // Число нарушений с важностью warning
+	Violations []Violation `json:"violations"` // Нарушения, упорядоченные по файлу и строке
+}
+
+// Failed сообщает, что найдено хотя бы одно нарушение с важностью error
+func (r ViolationReport) Failed() bool {
+	return r.Errors > 0
+}
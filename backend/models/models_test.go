@@ -68,6 +68,7 @@ func TestDependencyGraphStructure(t *testing.T) {
 	graph := DependencyGraph{
 		Nodes: []Constant{
 			{
+				ID:       "file1.js#CONST1",
 				Name:     "CONST1",
 				Value:    "value1",
 				Type:     "string",
@@ -75,6 +76,7 @@ func TestDependencyGraphStructure(t *testing.T) {
 				LineNum:  10,
 			},
 			{
+				ID:       "file1.js#CONST2",
 				Name:     "CONST2",
 				Value:    "CONST1 + 'value2'",
 				Type:     "string",
@@ -84,8 +86,9 @@ func TestDependencyGraphStructure(t *testing.T) {
 		},
 		Edges: []Dependency{
 			{
-				Source: "CONST2",
-				Target: "CONST1",
+				Source: "file1.js#CONST2",
+				Target: "file1.js#CONST1",
+				Kind:   DependencyLocal,
 			},
 		},
 	}
@@ -125,6 +128,10 @@ func TestDependencyGraphStructure(t *testing.T) {
 		t.Errorf("Ожидается имя узла '%s', получено: %s", graph.Nodes[0].Name, deserializedGraph.Nodes[0].Name)
 	}
 
+	if deserializedGraph.Nodes[0].ID != graph.Nodes[0].ID {
+		t.Errorf("Ожидается идентификатор узла '%s', получено: %s", graph.Nodes[0].ID, deserializedGraph.Nodes[0].ID)
+	}
+
 	// Проверяем содержимое ребер
 	if deserializedGraph.Edges[0].Source != graph.Edges[0].Source ||
 	   deserializedGraph.Edges[0].Target != graph.Edges[0].Target {
@@ -132,3 +139,24 @@ func TestDependencyGraphStructure(t *testing.T) {
 			graph.Edges[0], deserializedGraph.Edges[0])
 	}
 }
+
+func TestNewConstantID(t *testing.T) {
+	tests := []struct {
+		relPath  string
+		scope    string
+		name     string
+		expected string
+	}{
+		{"config.js", ModuleScope, "API_URL", "config.js#API_URL"},
+		{"src/api/config.ts", ModuleScope, "API_URL", "src/api/config.ts#API_URL"},
+		{"src/app.ts", "App", "render", "src/app.ts#App.render"},
+	}
+
+	for _, test := range tests {
+		id := NewConstantID(test.relPath, test.scope, test.name)
+		if id != test.expected {
+			t.Errorf("Для %s/%s/%s ожидается идентификатор %s, получено: %s",
+				test.relPath, test.scope, test.name, test.expected, id)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+// Package sarif формирует отчеты SARIF 2.1.0 о циклических зависимостях и нарушениях
+// правил, чтобы системы просмотра кода показывали их рядом с изменениями.
+package sarif
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/avor0n/dependency-graph-visualizer/config"
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// Версия формата и схема отчета
+const (
+	Version = "2.1.0"
+	Schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// ToolName - имя анализатора в отчете
+const ToolName = "dependency-graph-visualizer"
+
+// SourceRoot - базовый идентификатор, относительно которого заданы пути к файлам
+const SourceRoot = "%SRCROOT%"
+
+// ContentType - MIME-тип отчета
+const ContentType = "application/sarif+json"
+
+// Правила, которыми отмечаются циклические зависимости
+const (
+	RuleModuleCycle   = "module-cycle"
+	RuleConstantCycle = "constant-cycle"
+)
+
+// ColumnKind - единица измерения столбцов в отчете. Столбцы models.Location
+// считаются в кодовых точках Unicode, а не в UTF-16 по умолчанию SARIF.
+const ColumnKind = "unicodeCodePoints"
+
+// fingerprintKey - ключ отпечатка результата, не зависящего от номеров строк
+const fingerprintKey = "dependencyPath/v1"
+
+// Log - корневой объект отчета SARIF
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run - результаты одного запуска анализатора
+type Run struct {
+	Tool               Tool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]ArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	ColumnKind         string                      `json:"columnKind"`
+	Results            []Result                    `json:"results"`
+}
+
+// Tool описывает анализатор
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver описывает анализатор и его правила
+type Driver struct {
+	Name  string                `json:"name"`
+	Rules []ReportingDescriptor `json:"rules"`
+}
+
+// ReportingDescriptor описывает правило анализатора
+type ReportingDescriptor struct {
+	ID                   string        `json:"id"`
+	ShortDescription     Message       `json:"shortDescription"`
+	DefaultConfiguration Configuration `json:"defaultConfiguration"`
+}
+
+// Configuration задает параметры правила по умолчанию
+type Configuration struct {
+	Level string `json:"level"`
+}
+
+// Result - найденная проблема
+type Result struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             Message           `json:"message"`
+	Locations           []Location        `json:"locations"`
+	RelatedLocations    []Location        `json:"relatedLocations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+}
+
+// Message - текст сообщения
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Location - место в исходном коде
+type Location struct {
+	ID               int              `json:"id,omitempty"`
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+	Message          *Message         `json:"message,omitempty"`
+}
+
+// PhysicalLocation - фрагмент файла
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+// ArtifactLocation - ссылка на файл
+type ArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+// Region - фрагмент файла; колонки считаются в символах с 1, конец не включительно
+type Region struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// FromCycles формирует отчет о циклических зависимостях.
+// root - корень проекта, относительно которого заданы пути в отчете.
+func FromCycles(report models.CycleReport, root string) Log {
+	run := newRun(root, []ReportingDescriptor{
+		{ID: RuleModuleCycle, ShortDescription: Message{"Циклическая зависимость между модулями"}, DefaultConfiguration: Configuration{"error"}},
+		{ID: RuleConstantCycle, ShortDescription: Message{"Циклическая зависимость между константами"}, DefaultConfiguration: Configuration{"error"}},
+	})
+
+	sections := []struct {
+		rule   int
+		title  string
+		cycles []models.Cycle
+	}{
+		{0, "модулей", report.Modules},
+		{1, "констант", report.Constants},
+	}
+
+	for _, section := range sections {
+		for _, cycle := range section.cycles {
+			if len(cycle.Locations) == 0 {
+				continue
+			}
+
+			related := make([]Location, 0, len(cycle.Edges))
+			for i, edge := range cycle.Edges {
+				location := newLocation(cycle.Locations[i])
+				location.ID = i + 1
+				location.Message = &Message{edge.Source + " -> " + edge.Target}
+				related = append(related, location)
+			}
+
+			run.Results = append(run.Results, Result{
+				RuleID:    run.Tool.Driver.Rules[section.rule].ID,
+				RuleIndex: section.rule,
+				Level:     "error",
+				Message: Message{fmt.Sprintf("Цикл из %d %s: %s",
+					len(cycle.Nodes), section.title, strings.Join(cycle.Nodes, ", "))},
+				Locations:           []Location{newLocation(cycle.Locations[0])},
+				RelatedLocations:    related,
+				PartialFingerprints: fingerprint(run.Tool.Driver.Rules[section.rule].ID, strings.Join(cycle.Nodes, "\n")),
+			})
+		}
+	}
+
+	return newLog(run)
+}
+
+// FromViolations формирует отчет о нарушениях правил зависимостей.
+// rules - правила из конфигурации, по которым был построен отчет.
+func FromViolations(report models.ViolationReport, rules []config.Rule, root string) Log {
+	descriptors := make([]ReportingDescriptor, 0, len(rules))
+	index := make(map[string]int, len(rules))
+	for i, rule := range rules {
+		description := rule.Message
+		if description == "" {
+			description = "Запрещенная зависимость: правило " + rule.Name
+		}
+		descriptors = append(descriptors, ReportingDescriptor{
+			ID:                   rule.Name,
+			ShortDescription:     Message{description},
+			DefaultConfiguration: Configuration{level(rule.Severity)},
+		})
+		index[rule.Name] = i
+	}
+
+	run := newRun(root, descriptors)
+	for _, violation := range report.Violations {
+		text := violation.Source + " -> " + violation.Target
+		if violation.Message != "" {
+			text += ": " + violation.Message
+		}
+		run.Results = append(run.Results, Result{
+			RuleID:              violation.Rule,
+			RuleIndex:           index[violation.Rule],
+			Level:               level(violation.Severity),
+			Message:             Message{text},
+			Locations:           []Location{newLocation(violation.Location)},
+			PartialFingerprints: fingerprint(violation.Rule, violation.Source+"\n"+violation.Target),
+		})
+	}
+
+	return newLog(run)
+}
+
+// level возвращает уровень SARIF для важности правила: error, если важность не указана
+func level(severity string) string {
+	if severity == config.SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// newLog создает отчет из одного запуска
+func newLog(run Run) Log {
+	return Log{Schema: Schema, Version: Version, Runs: []Run{run}}
+}
+
+// newRun создает запуск анализатора с правилами и корнем проекта
+func newRun(root string, rules []ReportingDescriptor) Run {
+	run := Run{
+		Tool:       Tool{Driver: Driver{Name: ToolName, Rules: rules}},
+		ColumnKind: ColumnKind,
+		Results:    []Result{},
+	}
+	if root != "" {
+		run.OriginalURIBaseIDs = map[string]ArtifactLocation{
+			SourceRoot: {URI: rootURI(root)},
+		}
+	}
+	return run
+}
+
+// newLocation преобразует место в исходном коде в объект SARIF
+func newLocation(location models.Location) Location {
+	physical := PhysicalLocation{
+		ArtifactLocation: ArtifactLocation{
+			URI:       (&url.URL{Path: location.File}).EscapedPath(),
+			URIBaseID: SourceRoot,
+		},
+	}
+	if location.Line > 0 {
+		physical.Region = &Region{
+			StartLine:   location.Line,
+			StartColumn: location.Column,
+			EndLine:     location.EndLine,
+			EndColumn:   location.EndColumn,
+		}
+	}
+	return Location{PhysicalLocation: physical}
+}
+
+// rootURI возвращает URI директории проекта со схемой file и завершающим "/"
+func rootURI(root string) string {
+	if abs, err := filepath.Abs(root); err == nil {
+		root = abs
+	}
+	path := filepath.ToSlash(root)
+	if !strings.HasPrefix(path, "/") {
+		// Путь Windows: C:/project -> /C:/project
+		path = "/" + path
+	}
+	if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// fingerprint возвращает отпечаток результата, который не меняется при сдвиге строк,
+// чтобы системы просмотра кода не считали сдвинутую проблему новой
+func fingerprint(rule, key string) map[string]string {
+	sum := sha256.Sum256([]byte(rule + "\n" + key))
+	return map[string]string{fingerprintKey: hex.EncodeToString(sum[:])}
+}
@@ -0,0 +1,185 @@
+package sarif
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/avor0n/dependency-graph-visualizer/config"
+	"github.com/avor0n/dependency-graph-visualizer/models"
+	"github.com/avor0n/dependency-graph-visualizer/services"
+)
+
+func TestFromViolations(t *testing.T) {
+	rules := []config.Rule{
+		{Name: "ui-no-db", Severity: config.SeverityError, Message: "UI не обращается к БД"},
+		{Name: "shared-only", Severity: config.SeverityWarning},
+	}
+	report := models.ViolationReport{
+		Rules: 2, Errors: 1, Warnings: 1,
+		Violations: []models.Violation{
+			{Rule: "shared-only", Severity: config.SeverityWarning, Kind: models.ViolationModule,
+				Source: "src/shared/format.ts", Target: "src/features/auth.ts",
+				Location: models.Location{File: "src/shared/format.ts", Line: 1, Column: 22, EndLine: 1, EndColumn: 40}},
+			{Rule: "ui-no-db", Severity: config.SeverityError, Message: "UI не обращается к БД", Kind: models.ViolationConstant,
+				Source: "src/my ui/button.ts#LABEL", Target: "src/db/client.ts#DB_URL",
+				Location: models.Location{File: "src/my ui/button.ts", Line: 3, Column: 14, EndLine: 3, EndColumn: 19}},
+		},
+	}
+
+	log := FromViolations(report, rules, "/home/user/my project")
+
+	if log.Version != Version || len(log.Runs) != 1 {
+		t.Fatalf("Ожидается отчет версии %s с одним запуском, получено: %+v", Version, log)
+	}
+	run := log.Runs[0]
+
+	if base := run.OriginalURIBaseIDs[SourceRoot].URI; base != "file:///home/user/my%20project/" {
+		t.Errorf("Неверный URI корня проекта: %s", base)
+	}
+
+	if len(run.Tool.Driver.Rules) != 2 {
+		t.Fatalf("Ожидается 2 правила, получено: %+v", run.Tool.Driver.Rules)
+	}
+	if rule := run.Tool.Driver.Rules[1]; rule.ID != "shared-only" || rule.DefaultConfiguration.Level != "warning" {
+		t.Errorf("Неверное описание правила: %+v", rule)
+	}
+
+	if len(run.Results) != 2 {
+		t.Fatalf("Ожидается 2 результата, получено: %+v", run.Results)
+	}
+
+	warning := run.Results[0]
+	if warning.RuleID != "shared-only" || warning.RuleIndex != 1 || warning.Level != "warning" {
+		t.Errorf("Неверный результат: %+v", warning)
+	}
+
+	result := run.Results[1]
+	if result.RuleIndex != 0 || result.Level != "error" ||
+		result.Message.Text != "src/my ui/button.ts#LABEL -> src/db/client.ts#DB_URL: UI не обращается к БД" {
+		t.Errorf("Неверный результат: %+v", result)
+	}
+	physical := result.Locations[0].PhysicalLocation
+	if physical.ArtifactLocation.URI != "src/my%20ui/button.ts" || physical.ArtifactLocation.URIBaseID != SourceRoot {
+		t.Errorf("Неверная ссылка на файл: %+v", physical.ArtifactLocation)
+	}
+	if expected := (Region{StartLine: 3, StartColumn: 14, EndLine: 3, EndColumn: 19}); physical.Region == nil || *physical.Region != expected {
+		t.Errorf("Ожидается фрагмент %+v, получено: %+v", expected, physical.Region)
+	}
+
+	// Отпечаток не зависит от положения нарушения в файле
+	moved := report
+	moved.Violations = append([]models.Violation(nil), report.Violations...)
+	moved.Violations[1].Line = 10
+	movedLog := FromViolations(moved, rules, "")
+	if movedLog.Runs[0].Results[1].PartialFingerprints[fingerprintKey] != result.PartialFingerprints[fingerprintKey] {
+		t.Errorf("Отпечаток не должен меняться при сдвиге строк")
+	}
+	if movedLog.Runs[0].OriginalURIBaseIDs != nil {
+		t.Errorf("Без корня проекта базовый URI не задается")
+	}
+}
+
+func TestFromCycles(t *testing.T) {
+	report := models.CycleReport{
+		Modules: []models.Cycle{{
+			Nodes: []string{"a.ts", "b.ts"},
+			Edges: []models.Dependency{
+				{Source: "a.ts", Target: "b.ts", Kind: models.DependencyImport},
+				{Source: "b.ts", Target: "a.ts", Kind: models.DependencyImport},
+			},
+			Locations: []models.Location{
+				{File: "a.ts", Line: 1, Column: 19, EndLine: 1, EndColumn: 24},
+				{File: "b.ts", Line: 2, Column: 19, EndLine: 2, EndColumn: 24},
+			},
+		}},
+		Constants: []models.Cycle{},
+	}
+
+	log := FromCycles(report, "")
+	results := log.Runs[0].Results
+	if len(results) != 1 {
+		t.Fatalf("Ожидается 1 результат, получено: %+v", results)
+	}
+
+	result := results[0]
+	if result.RuleID != RuleModuleCycle || result.RuleIndex != 0 || result.Message.Text != "Цикл из 2 модулей: a.ts, b.ts" {
+		t.Errorf("Неверный результат: %+v", result)
+	}
+	if uri := result.Locations[0].PhysicalLocation.ArtifactLocation.URI; uri != "a.ts" {
+		t.Errorf("Основное место цикла должно указывать на первый импорт, получено: %s", uri)
+	}
+	if len(result.RelatedLocations) != 2 {
+		t.Fatalf("Ожидается место для каждого ребра цикла, получено: %+v", result.RelatedLocations)
+	}
+	related := result.RelatedLocations[1]
+	if related.ID != 2 || related.Message == nil || related.Message.Text != "b.ts -> a.ts" || related.PhysicalLocation.Region.StartLine != 2 {
+		t.Errorf("Неверное связанное место: %+v", related)
+	}
+
+	data, err := json.Marshal(log)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	for _, fragment := range []string{`"$schema":"` + Schema + `"`, `"version":"2.1.0"`, `"startColumn":19`} {
+		if !strings.Contains(string(data), fragment) {
+			t.Errorf("Отчет не содержит %s: %s", fragment, data)
+		}
+	}
+}
+
+func TestColumnKind(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "sarif-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		".depgraph.json":   `{ "rules": [{ "name": "ui-no-db", "from": ["src/ui/"], "deny": ["src/db/"] }] }`,
+		"src/db/client.ts": "export const DB_URL = 'db';",
+		// Эмодзи занимает одну кодовую точку и две единицы UTF-16
+		"src/ui/button.ts": "import { DB_URL } from '../db/client';\n/* 😀 */ export const LABEL = DB_URL;",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Не удалось создать директорию: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Не удалось записать файл %s: %v", name, err)
+		}
+	}
+
+	cfg, err := config.Load(tempDir)
+	if err != nil {
+		t.Fatalf("Не удалось загрузить конфигурацию: %v", err)
+	}
+	ds := services.NewDependencyService(services.NewFileService(tempDir, nil))
+	ds.BuildDependencyGraph()
+	report, err := ds.CheckRules(cfg.Rules)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	run := FromViolations(report, cfg.Rules, tempDir).Runs[0]
+	if run.ColumnKind != "unicodeCodePoints" {
+		t.Errorf("Ожидается columnKind unicodeCodePoints, получено: %q", run.ColumnKind)
+	}
+
+	// LABEL во второй строке начинается с 22-й кодовой точки (23-й единицы UTF-16)
+	expected := Region{StartLine: 2, StartColumn: 22, EndLine: 2, EndColumn: 27}
+	for _, result := range run.Results {
+		physical := result.Locations[0].PhysicalLocation
+		if physical.ArtifactLocation.URI != "src/ui/button.ts" || physical.Region == nil || physical.Region.StartLine != 2 {
+			continue
+		}
+		if *physical.Region != expected {
+			t.Errorf("Ожидается фрагмент %+v, получено: %+v", expected, *physical.Region)
+		}
+		return
+	}
+	t.Errorf("Нарушение во второй строке src/ui/button.ts не найдено: %+v", run.Results)
+}
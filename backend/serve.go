@@ -0,0 +1,228 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/avor0n/dependency-graph-visualizer/config"
+	"github.com/avor0n/dependency-graph-visualizer/handlers"
+	"github.com/avor0n/dependency-graph-visualizer/services"
+	"github.com/avor0n/dependency-graph-visualizer/web"
+)
+
+// Переменные окружения с настройками сервера. Флаги командной строки имеют приоритет.
+const (
+	envHost      = "DEPGRAPH_HOST"
+	envPort      = "DEPGRAPH_PORT"
+	envStaticDir = "DEPGRAPH_STATIC_DIR"
+)
+
+// runServe строит граф и запускает HTTP-сервер
+func runServe(args []string, stdout, stderr io.Writer) int {
+	flags, projectPath := newFlagSet("serve", stderr)
+	watch := flags.Bool("watch", false, "Отслеживать изменения файлов и обновлять граф зависимостей")
+	watchInterval := flags.Duration("watch-interval", services.DefaultWatchInterval, "Период опроса файловой системы в режиме -watch")
+	host := flags.String("host", os.Getenv(envHost), "Адрес для входящих соединений (по умолчанию все интерфейсы, $"+envHost+")")
+	port := flags.Int("port", config.DefaultPort, "Порт сервера, 0 - выбрать свободный ($"+envPort+", параметр port в "+config.FileName+")")
+	staticDir := flags.String("static", os.Getenv(envStaticDir), "Директория собранного фронтенда вместо встроенного ($"+envStaticDir+")")
+	if _, code, ok := parseFlags(flags, projectPath, args); !ok {
+		return code
+	}
+
+	// Переменная окружения применяется, только если флаг -port не указан явно,
+	// а параметр из конфигурации проекта - если не задано ни то, ни другое
+	portFromConfig := !flagSet(flags, "port") && os.Getenv(envPort) == ""
+	if !flagSet(flags, "port") {
+		if value := os.Getenv(envPort); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				fmt.Fprintf(stderr, "Ошибка: некорректное значение %s=%q\n", envPort, value)
+				return exitUsage
+			}
+			*port = parsed
+		}
+	}
+	if *port < 0 || *port > 65535 {
+		fmt.Fprintf(stderr, "Ошибка: порт должен быть в диапазоне 0-65535, получено %d\n", *port)
+		return exitUsage
+	}
+
+	frontend, source, err := resolveFrontend(*staticDir)
+	if err != nil {
+		fmt.Fprintln(stderr, "Ошибка:", err)
+		return exitUsage
+	}
+	if frontend == nil {
+		fmt.Fprintf(stderr, "Предупреждение: бинарник собран без фронтенда, укажите его директорию флагом -static или переменной %s\n", envStaticDir)
+	} else {
+		fmt.Fprintf(stderr, "Фронтенд: %s\n", source)
+	}
+
+	project, err := openProject(*projectPath, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, "Ошибка:", err)
+		return exitError
+	}
+	fileService, dependencyService := project.fileService, project.dependencyService
+
+	// Состояние файлов запоминается до построения графа, чтобы изменения,
+	// сделанные во время анализа, попали в первую же проверку
+	var watcher *services.Watcher
+	if *watch {
+		watcher = services.NewWatcher(fileService, *watchInterval)
+	}
+	dependencyService.BuildDependencyGraph()
+
+	if portFromConfig {
+		*port = project.config.Port
+	}
+
+	// Инициализируем обработчики с указателями на сервисы
+	handler := &handlers.Handler{
+		FileService:       fileService,
+		DependencyService: dependencyService,
+		ProjectPath:       fileService.ProjectPath,
+		Rules:             project.config.Rules,
+	}
+
+	// В режиме отслеживания повторно анализируем только измененные файлы
+	// и рассылаем изменения графа подключенным клиентам
+	if watcher != nil {
+		handler.Events = handlers.NewEventBroker()
+
+		watcher.Start(func(changes services.FileChanges) {
+			log.Printf("Изменения в проекте: добавлено %d, изменено %d, удалено %d файлов, изменено %d файлов конфигурации\n",
+				len(changes.Added), len(changes.Modified), len(changes.Removed), len(changes.Configs))
+			if delta := dependencyService.ApplyChanges(changes); !delta.Empty() {
+				handler.Events.Publish(delta)
+			}
+		})
+		defer watcher.Stop()
+	}
+
+	// Открываем порт заранее, чтобы вывести фактический адрес (в том числе для порта 0)
+	listener, err := net.Listen("tcp", net.JoinHostPort(*host, strconv.Itoa(*port)))
+	if err != nil {
+		fmt.Fprintln(stderr, "Ошибка сервера:", err)
+		return exitError
+	}
+
+	fmt.Fprintf(stdout, "Сервер запущен на %s\n", serverURL(listener.Addr()))
+	if err := http.Serve(listener, newServeMux(handler, frontend)); err != nil {
+		fmt.Fprintln(stderr, "Ошибка сервера:", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// flagSet сообщает, указан ли флаг name в командной строке
+func flagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// resolveFrontend выбирает источник фронтенда и возвращает его описание.
+// Явно указанная директория (для разработки фронтенда) должна существовать.
+// Без нее используется встроенный фронтенд, а если бинарник собран без него -
+// директория frontend/dist рядом с исполняемым файлом или рабочей директорией.
+// Если фронтенд не найден, возвращается nil.
+func resolveFrontend(dir string) (fs.FS, string, error) {
+	if dir != "" {
+		if !isDir(dir) {
+			return nil, "", fmt.Errorf("директория фронтенда %s не существует", dir)
+		}
+		return dirFrontend(dir)
+	}
+
+	if embedded, ok := web.Embedded(); ok {
+		return embedded, "встроенный", nil
+	}
+
+	var candidates []string
+	if executable, err := os.Executable(); err == nil {
+		base := filepath.Dir(executable)
+		candidates = append(candidates,
+			filepath.Join(base, "..", "frontend", "dist"),
+			filepath.Join(base, "frontend", "dist"))
+	}
+	candidates = append(candidates,
+		filepath.Join("..", "frontend", "dist"),
+		filepath.Join("frontend", "dist"))
+
+	for _, candidate := range candidates {
+		if isDir(candidate) {
+			return dirFrontend(candidate)
+		}
+	}
+	return nil, "", nil
+}
+
+// dirFrontend возвращает фронтенд из директории на диске
+func dirFrontend(dir string) (fs.FS, string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, "", err
+	}
+	return os.DirFS(abs), abs, nil
+}
+
+// isDir сообщает, является ли path существующей директорией
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// serverURL возвращает адрес, по которому доступен сервер. Для адреса
+// "все интерфейсы" подставляется localhost.
+func serverURL(addr net.Addr) string {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return "http://" + addr.String()
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+// newServeMux регистрирует API endpoints и раздачу фронтенда.
+// Если frontend равен nil, доступен только API.
+func newServeMux(handler *handlers.Handler, frontend fs.FS) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/project-info", handler.HandleProjectInfo)
+	mux.HandleFunc("/api/file-tree", handler.HandleFileTree)
+	mux.HandleFunc("/api/dependency-graph", handler.HandleDependencyGraph)
+	mux.HandleFunc("/api/file-dependencies", handler.HandleFileDependencies)
+	mux.HandleFunc("/api/module-graph", handler.HandleModuleGraph)
+	mux.HandleFunc("/api/cycles", handler.HandleCycles)
+	mux.HandleFunc("/api/impact", handler.HandleImpact)
+	mux.HandleFunc("/api/aggregate", handler.HandleAggregate)
+	mux.HandleFunc("/api/events", handler.HandleEvents)
+	mux.HandleFunc("/api/export", handler.HandleExport)
+	mux.HandleFunc("/api/violations", handler.HandleViolations)
+
+	if frontend == nil {
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "Фронтенд не найден: укажите директорию флагом -static", http.StatusNotFound)
+		})
+		return mux
+	}
+
+	mux.Handle("/", handlers.EnableCORS(web.NewHandler(frontend)))
+
+	return mux
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/avor0n/dependency-graph-visualizer/handlers"
+)
+
+// TestServerURL проверяет адрес, который выводится после запуска сервера
+func TestServerURL(t *testing.T) {
+	tests := []struct {
+		addr     net.Addr
+		expected string
+	}{
+		{&net.TCPAddr{IP: net.IPv4zero, Port: 8080}, "http://localhost:8080"},
+		{&net.TCPAddr{IP: net.IPv6unspecified, Port: 9000}, "http://localhost:9000"},
+		{&net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 3000}, "http://127.0.0.1:3000"},
+		{&net.TCPAddr{IP: net.ParseIP("::1"), Port: 3000}, "http://[::1]:3000"},
+	}
+
+	for _, test := range tests {
+		if url := serverURL(test.addr); url != test.expected {
+			t.Errorf("serverURL(%s): ожидается %s, получено %s", test.addr, test.expected, url)
+		}
+	}
+
+	// Для порта 0 выводится порт, выбранный системой
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Не удалось открыть порт: %v", err)
+	}
+	defer listener.Close()
+
+	url := serverURL(listener.Addr())
+	if strings.HasSuffix(url, ":0") || !strings.HasPrefix(url, "http://127.0.0.1:") {
+		t.Errorf("Ожидается фактический порт в адресе, получено %s", url)
+	}
+}
+
+// TestResolveFrontend проверяет выбор директории фронтенда
+func TestResolveFrontend(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "static-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := os.WriteFile(filepath.Join(tempDir, "index.html"), []byte("<html>app</html>"), 0644); err != nil {
+		t.Fatalf("Не удалось создать index.html: %v", err)
+	}
+
+	frontend, source, err := resolveFrontend(tempDir)
+	if err != nil || source != tempDir {
+		t.Fatalf("Ожидается %s, получено %q (%v)", tempDir, source, err)
+	}
+	if _, err := fs.Stat(frontend, "index.html"); err != nil {
+		t.Errorf("Ожидается index.html во фронтенде: %v", err)
+	}
+
+	if _, _, err := resolveFrontend(filepath.Join(tempDir, "missing")); err == nil {
+		t.Error("Ожидается ошибка для несуществующей директории")
+	}
+}
+
+// TestNewServeMuxFrontend проверяет раздачу фронтенда и его отсутствие
+func TestNewServeMuxFrontend(t *testing.T) {
+	frontend := fstest.MapFS{"index.html": {Data: []byte("<html>app</html>")}}
+
+	rr := httptest.NewRecorder()
+	newServeMux(&handlers.Handler{}, frontend).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "app") {
+		t.Errorf("Ожидается index.html, получено %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	newServeMux(&handlers.Handler{}, nil).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Без фронтенда ожидается статус %d, получен %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+// TestRunServeFlags проверяет обработку неверных настроек сервера
+func TestRunServeFlags(t *testing.T) {
+	dir := createTestProject(t)
+
+	if code, _, _ := runCLI("serve", dir, "-port", "70000"); code != exitUsage {
+		t.Errorf("Ожидается код %d для неверного порта, получен %d", exitUsage, code)
+	}
+	if code, _, _ := runCLI("serve", dir, "-static", filepath.Join(dir, "missing")); code != exitUsage {
+		t.Errorf("Ожидается код %d для несуществующей директории фронтенда, получен %d", exitUsage, code)
+	}
+
+	t.Setenv(envPort, "http")
+	if code, _, stderr := runCLI("serve", dir); code != exitUsage || !strings.Contains(stderr, envPort) {
+		t.Errorf("Ожидается код %d и ошибка в %s, получено %d: %s", exitUsage, envPort, code, stderr)
+	}
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// ErrAggregateNotFound возвращается, если раскрываемой группы нет в агрегированном графе
+var ErrAggregateNotFound = errors.New("aggregate not found")
+
+// Aggregate объединяет константы в группы по директории (models.AggregateDirectory,
+// директория усекается до depth уровней) или по пакету (models.AggregatePackage,
+// ближайший package.json). Ребра между группами взвешены числом зависимостей
+// между их константами. Группы из expand раскрываются обратно в константы.
+func (ds *DependencyService) Aggregate(by string, depth int, expand []string) (models.AggregateGraph, error) {
+	graph := models.AggregateGraph{By: by, Nodes: []models.AggregateNode{}, Edges: []models.AggregateEdge{}}
+
+	var groupOf func(relPath string) (id, label string)
+	switch by {
+	case models.AggregateDirectory:
+		if depth < 1 {
+			return graph, fmt.Errorf("depth must be positive")
+		}
+		graph.Depth = depth
+		groupOf = func(relPath string) (string, string) {
+			dir := directoryPrefix(relPath, depth)
+			return dir, dir
+		}
+	case models.AggregatePackage:
+		packages := newPackageIndex(ds.FileService.ProjectPath)
+		groupOf = packages.lookup
+	default:
+		return graph, fmt.Errorf("unknown aggregation %q", by)
+	}
+
+	ds.GraphMutex.RLock()
+	nodes := append([]models.Constant(nil), ds.Graph.Nodes...)
+	edges := append([]models.Dependency(nil), ds.Graph.Edges...)
+	ds.GraphMutex.RUnlock()
+
+	// Группа каждой константы
+	groups := make(map[string]*models.AggregateNode)
+	files := make(map[string]map[string]bool)
+	member := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		relPath := ds.relativePath(node.FilePath)
+		id, label := groupOf(relPath)
+		group := groups[id]
+		if group == nil {
+			group = &models.AggregateNode{ID: id, Label: label, Kind: by}
+			groups[id] = group
+			files[id] = make(map[string]bool)
+		}
+		group.Constants++
+		files[id][relPath] = true
+		member[node.ID] = id
+	}
+	for id, group := range groups {
+		group.Files = len(files[id])
+	}
+
+	expanded := make(map[string]bool, len(expand))
+	for _, id := range expand {
+		if groups[id] == nil {
+			return graph, fmt.Errorf("%w: %s", ErrAggregateNotFound, id)
+		}
+		expanded[id] = true
+	}
+
+	// Константы раскрытых групп становятся отдельными узлами
+	nodeOf := func(constantID string) string {
+		if group := member[constantID]; !expanded[group] {
+			return group
+		}
+		return constantID
+	}
+	for _, node := range nodes {
+		group := member[node.ID]
+		if !expanded[group] {
+			continue
+		}
+		graph.Nodes = append(graph.Nodes, models.AggregateNode{
+			ID:        node.ID,
+			Label:     node.Name,
+			Kind:      models.AggregateConstant,
+			Parent:    group,
+			Constants: 1,
+			Files:     1,
+		})
+	}
+
+	weights := make(map[[2]string]int)
+	for _, edge := range edges {
+		if _, ok := member[edge.Source]; !ok {
+			continue
+		}
+		if _, ok := member[edge.Target]; !ok {
+			continue
+		}
+		source, target := nodeOf(edge.Source), nodeOf(edge.Target)
+		if source == target {
+			if group := groups[source]; group != nil {
+				group.InternalEdges++
+			}
+			continue
+		}
+		weights[[2]string{source, target}]++
+	}
+
+	for id, group := range groups {
+		if !expanded[id] {
+			graph.Nodes = append(graph.Nodes, *group)
+		}
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].ID < graph.Nodes[j].ID
+	})
+
+	for key, weight := range weights {
+		graph.Edges = append(graph.Edges, models.AggregateEdge{Source: key[0], Target: key[1], Weight: weight})
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		return a.Target < b.Target
+	})
+
+	return graph, nil
+}
+
+// directoryPrefix возвращает директорию файла, усеченную до depth уровней;
+// для файлов в корне проекта - "."
+func directoryPrefix(relPath string, depth int) string {
+	dir := path.Dir(relPath)
+	if dir == "." {
+		return dir
+	}
+	segments := strings.Split(dir, "/")
+	if len(segments) > depth {
+		segments = segments[:depth]
+	}
+	return strings.Join(segments, "/")
+}
+
+// packageIndex находит ближайший package.json для файлов проекта
+// и запоминает результат для каждой просмотренной директории
+type packageIndex struct {
+	root     string
+	packages map[string][2]string // Директория -> идентификатор и имя пакета
+}
+
+// newPackageIndex создает индекс пакетов проекта root
+func newPackageIndex(root string) *packageIndex {
+	return &packageIndex{root: root, packages: make(map[string][2]string)}
+}
+
+// lookup возвращает директорию ближайшего пакета относительно корня проекта
+// и его имя из package.json. Файлы вне пакетов относятся к корню проекта ".".
+func (p *packageIndex) lookup(relPath string) (string, string) {
+	pkg := p.find(path.Dir(relPath))
+	return pkg[0], pkg[1]
+}
+
+// find ищет package.json в директории dir и ее родителях в пределах проекта
+func (p *packageIndex) find(dir string) [2]string {
+	if pkg, ok := p.packages[dir]; ok {
+		return pkg
+	}
+
+	var pkg [2]string
+	if name, ok := packageName(filepath.Join(p.root, filepath.FromSlash(dir))); ok {
+		pkg = [2]string{dir, name}
+	} else if parent := path.Dir(dir); dir != "." && parent != dir {
+		pkg = p.find(parent)
+	} else {
+		pkg = [2]string{".", "."}
+	}
+
+	p.packages[dir] = pkg
+	return pkg
+}
+
+// packageName возвращает имя пакета из package.json директории
+// или имя директории, если в package.json оно не задано
+func packageName(dir string) (string, bool) {
+	content, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return "", false
+	}
+
+	var pkg struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil || pkg.Name == "" {
+		return filepath.Base(dir), true
+	}
+	return pkg.Name, true
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+func TestAggregate(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "aggregate-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"packages/core/package.json":  `{"name": "@app/core"}`,
+		"packages/core/src/config.ts": "export const API_URL = '/api';\nexport const TIMEOUT = 5;",
+		"packages/ui/package.json":    `{"name": "@app/ui"}`,
+		"packages/ui/src/button.ts":   "import { API_URL, TIMEOUT } from '../../core/src/config';\nexport const LABEL = API_URL;\nexport const DELAY = TIMEOUT;\nexport const TEXT = LABEL;",
+		"scripts/build.ts":            "import { LABEL } from '../packages/ui/src/button';\nexport const BUILD = LABEL;",
+	})
+
+	ds := NewDependencyService(NewFileService(tempDir, nil))
+	ds.BuildDependencyGraph()
+
+	graph, err := ds.Aggregate(models.AggregateDirectory, 2, nil)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	expectedNodes := []models.AggregateNode{
+		{ID: "packages/core", Label: "packages/core", Kind: models.AggregateDirectory, Constants: 2, Files: 1},
+		{ID: "packages/ui", Label: "packages/ui", Kind: models.AggregateDirectory, Constants: 3, Files: 1, InternalEdges: 1},
+		{ID: "scripts", Label: "scripts", Kind: models.AggregateDirectory, Constants: 1, Files: 1},
+	}
+	expectedEdges := []models.AggregateEdge{
+		{Source: "packages/ui", Target: "packages/core", Weight: 2},
+		{Source: "scripts", Target: "packages/ui", Weight: 1},
+	}
+	if !reflect.DeepEqual(graph.Nodes, expectedNodes) || !reflect.DeepEqual(graph.Edges, expectedEdges) {
+		t.Errorf("Неверный граф по директориям:\n%+v\n%+v", graph.Nodes, graph.Edges)
+	}
+
+	// Меньшая глубина объединяет пакеты в одну группу
+	graph, _ = ds.Aggregate(models.AggregateDirectory, 1, nil)
+	if len(graph.Nodes) != 2 || graph.Nodes[0].ID != "packages" || graph.Nodes[0].InternalEdges != 3 {
+		t.Errorf("Неверный граф глубины 1: %+v", graph.Nodes)
+	}
+
+	// Группировка по ближайшему package.json; файлы вне пакетов относятся к корню
+	graph, err = ds.Aggregate(models.AggregatePackage, 0, nil)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	labels := make(map[string]string)
+	for _, node := range graph.Nodes {
+		labels[node.ID] = node.Label
+	}
+	if !reflect.DeepEqual(labels, map[string]string{".": ".", "packages/core": "@app/core", "packages/ui": "@app/ui"}) {
+		t.Errorf("Неверные пакеты: %v", labels)
+	}
+
+	// Раскрытие группы возвращает ее константы с ребрами к остальным группам
+	graph, err = ds.Aggregate(models.AggregateDirectory, 2, []string{"packages/ui"})
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	expectedEdges = []models.AggregateEdge{
+		{Source: "packages/ui/src/button.ts#DELAY", Target: "packages/core", Weight: 1},
+		{Source: "packages/ui/src/button.ts#LABEL", Target: "packages/core", Weight: 1},
+		{Source: "packages/ui/src/button.ts#TEXT", Target: "packages/ui/src/button.ts#LABEL", Weight: 1},
+		{Source: "scripts", Target: "packages/ui/src/button.ts#LABEL", Weight: 1},
+	}
+	if len(graph.Nodes) != 5 || !reflect.DeepEqual(graph.Edges, expectedEdges) {
+		t.Errorf("Неверный раскрытый граф:\n%+v\n%+v", graph.Nodes, graph.Edges)
+	}
+	for _, node := range graph.Nodes {
+		if node.Kind == models.AggregateConstant && node.Parent != "packages/ui" {
+			t.Errorf("Константа раскрытой группы должна ссылаться на группу: %+v", node)
+		}
+	}
+
+	if _, err := ds.Aggregate(models.AggregateDirectory, 2, []string{"missing"}); !errors.Is(err, ErrAggregateNotFound) {
+		t.Errorf("Ожидается ErrAggregateNotFound, получено: %v", err)
+	}
+	if _, err := ds.Aggregate(models.AggregateDirectory, 0, nil); err == nil {
+		t.Errorf("Ожидается ошибка для глубины 0")
+	}
+	if _, err := ds.Aggregate("layer", 1, nil); err == nil {
+		t.Errorf("Ожидается ошибка для неизвестного способа группировки")
+	}
+}
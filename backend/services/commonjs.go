@@ -0,0 +1,251 @@
+package services
+
+// Разбор модулей CommonJS: привязки require() становятся импортами,
+// а присваивания module.exports и exports.NAME - экспортами.
+// module.exports целиком соответствует экспорту по умолчанию.
+
+// parseRequireCall разбирает выражение require('./x') или require('./x').NAME,
+// начинающееся с индекса i и занимающее все выражение до его конца.
+// Возвращает лексему строки модуля, имя экспорта ("*" для всего модуля)
+// и индекс лексемы после выражения.
+func parseRequireCall(tokens []Token, i int) (Token, string, int, bool) {
+	if i+3 >= len(tokens) || !tokens[i].Is(TokenIdentifier, "require") || !tokens[i+1].Is(TokenPunctuator, "(") ||
+		tokens[i+2].Type != TokenString || !tokens[i+3].Is(TokenPunctuator, ")") {
+		return Token{}, "", 0, false
+	}
+
+	specifier, imported, end := tokens[i+2], "*", i+4
+	if end+1 < len(tokens) && tokens[end].Is(TokenPunctuator, ".") && tokens[end+1].Type == TokenIdentifier {
+		imported = tokens[end+1].Value
+		end += 2
+	}
+	if initializerEnd(tokens, i) != end {
+		// require('./x')(), require('./x').a.b и прочие выражения
+		return Token{}, "", 0, false
+	}
+	return specifier, imported, end, true
+}
+
+// isRequireInitializer проверяет, что инициализатор целиком является вызовом require()
+func isRequireInitializer(initializer []Token) bool {
+	_, _, end, ok := parseRequireCall(initializer, 0)
+	return ok && end == len(initializer)
+}
+
+// parseRequireDeclaration разбирает объявление с единственным декларатором,
+// инициализированным вызовом require(), начиная с const/let/var на индексе i:
+//
+//	const x = require('./x')           // x - весь module.exports
+//	const { a, b: c } = require('./x') // a и c - свойства module.exports
+//	const x = require('./x').a         // x - свойство a
+//
+// Возвращает импорт и индекс лексемы после инструкции.
+func parseRequireDeclaration(tokens []Token, i int) (moduleImport, int, bool) {
+	imp := moduleImport{Line: tokens[i].Line, Require: true}
+	j := i + 1
+	if j >= len(tokens) {
+		return imp, j, false
+	}
+
+	var bindings []importBinding
+	switch {
+	case tokens[j].Type == TokenIdentifier:
+		bindings = append(bindings, importBinding{Local: tokens[j].Value})
+		j++
+		if j < len(tokens) && tokens[j].Is(TokenPunctuator, ":") {
+			j = typeAnnotationEnd(tokens, j+1)
+		}
+	case tokens[j].Is(TokenPunctuator, "{"):
+		var ok bool
+		if bindings, j, ok = parseObjectPattern(tokens, j); !ok {
+			return imp, j, false
+		}
+	default:
+		return imp, j, false
+	}
+
+	if j >= len(tokens) || !tokens[j].Is(TokenPunctuator, "=") {
+		return imp, j, false
+	}
+	specifier, imported, end, ok := parseRequireCall(tokens, j+1)
+	if !ok || end < len(tokens) && tokens[end].Is(TokenPunctuator, ",") {
+		return imp, j, false
+	}
+
+	if tokens[i+1].Type == TokenIdentifier {
+		bindings[0].Imported = imported
+	} else if imported != "*" {
+		// Деструктуризация свойства модуля: const { a } = require('./x').b
+		return imp, j, false
+	}
+
+	imp.setSpecifier(specifier)
+	imp.Bindings = bindings
+	return imp, skipStatement(tokens, end), true
+}
+
+// parseObjectPattern разбирает шаблон деструктуризации { a, b: c, d = 1, ...rest },
+// начиная с "{" на индексе i. Каждое имя связывается со свойством объекта,
+// остаток ...rest - со всем объектом. Вложенные шаблоны пропускаются.
+// Возвращает привязки и индекс лексемы после "}".
+func parseObjectPattern(tokens []Token, i int) ([]importBinding, int, bool) {
+	var bindings []importBinding
+	j := i + 1
+
+	for j < len(tokens) && !tokens[j].Is(TokenPunctuator, "}") {
+		switch {
+		case tokens[j].Is(TokenPunctuator, "...") && j+1 < len(tokens) && tokens[j+1].Type == TokenIdentifier:
+			bindings = append(bindings, importBinding{Local: tokens[j+1].Value, Imported: "*"})
+			j += 2
+		case tokens[j].Type == TokenIdentifier || tokens[j].Type == TokenString:
+			key := tokens[j].Value
+			if tokens[j].Type == TokenString {
+				key = unquoteString(key)
+			}
+			local := key
+			j++
+			if j < len(tokens) && tokens[j].Is(TokenPunctuator, ":") {
+				j++
+				if j >= len(tokens) || tokens[j].Type != TokenIdentifier {
+					// Вложенный шаблон: { a: { b } }
+					local = ""
+				} else {
+					local = tokens[j].Value
+					j++
+				}
+			}
+			if local != "" {
+				bindings = append(bindings, importBinding{Local: local, Imported: key})
+			}
+			// Значение по умолчанию или остаток вложенного шаблона
+			if j < len(tokens) && !tokens[j].Is(TokenPunctuator, ",") && !tokens[j].Is(TokenPunctuator, "}") {
+				j = initializerEnd(tokens, j)
+			}
+		default:
+			return bindings, j, false
+		}
+
+		if j < len(tokens) && tokens[j].Is(TokenPunctuator, ",") {
+			j++
+		}
+	}
+
+	if j >= len(tokens) {
+		return bindings, j, false
+	}
+	return bindings, j + 1, true
+}
+
+// parseCommonJSExport разбирает присваивание module.exports = ..., module.exports.NAME = ...
+// или exports.NAME = ..., начинающееся с индекса i. Возвращает экспорты и индекс
+// начала присваиваемого значения, чтобы вызовы require() внутри него тоже были найдены.
+func parseCommonJSExport(tokens []Token, i int) ([]moduleExport, int, bool) {
+	line := tokens[i].Line
+	j := i
+	whole := false
+	if tokens[j].Is(TokenIdentifier, "module") {
+		if j+2 >= len(tokens) || !tokens[j+1].Is(TokenPunctuator, ".") || !tokens[j+2].Is(TokenIdentifier, "exports") {
+			return nil, i, false
+		}
+		j += 3
+		whole = j < len(tokens) && tokens[j].Is(TokenPunctuator, "=")
+	} else if !tokens[j].Is(TokenIdentifier, "exports") {
+		return nil, i, false
+	} else {
+		j++
+	}
+
+	if whole {
+		j++
+		if j >= len(tokens) {
+			return nil, j, false
+		}
+		if tokens[j].Is(TokenPunctuator, "{") {
+			return parseExportsObject(tokens, j, line), j, true
+		}
+		if specifier, imported, _, ok := parseRequireCall(tokens, j); ok && imported == "*" {
+			// module.exports = require('./x') реэкспортирует все имена модуля
+			return []moduleExport{reExport("*", specifier, imported, line)}, j, true
+		}
+		return []moduleExport{exportValue(tokens, j, "default", line)}, j, true
+	}
+
+	// exports.NAME = ... / module.exports.NAME = ...
+	if j+2 >= len(tokens) || !tokens[j].Is(TokenPunctuator, ".") || tokens[j+1].Type != TokenIdentifier ||
+		!tokens[j+2].Is(TokenPunctuator, "=") {
+		return nil, i, false
+	}
+	return []moduleExport{exportValue(tokens, j+3, tokens[j+1].Value, line)}, j + 3, true
+}
+
+// parseExportsObject разбирает литерал объекта module.exports = { A, B: C, d() {} },
+// начинающийся с "{" на индексе i. Свойство со значением-идентификатором экспортирует
+// локальное имя, прочие значения экспортируются как анонимные.
+func parseExportsObject(tokens []Token, i, line int) []moduleExport {
+	var exports []moduleExport
+	j := i + 1
+
+	for j < len(tokens) && !tokens[j].Is(TokenPunctuator, "}") {
+		token := tokens[j]
+		switch {
+		case token.Type == TokenIdentifier || token.Type == TokenString || token.Type == TokenNumber:
+			key := token.Value
+			if token.Type == TokenString {
+				key = unquoteString(key)
+			}
+			j++
+			switch {
+			case j < len(tokens) && (tokens[j].Is(TokenPunctuator, ",") || tokens[j].Is(TokenPunctuator, "}")):
+				// Сокращенная запись { A }
+				exports = append(exports, moduleExport{Exported: key, Local: key, Line: line})
+			case j < len(tokens) && tokens[j].Is(TokenPunctuator, ":"):
+				exports = append(exports, exportValue(tokens, j+1, key, line))
+				j = initializerEnd(tokens, j+1)
+			default:
+				// Метод { d() {} } или аксессор
+				exports = append(exports, moduleExport{Exported: key, Line: line})
+				j = initializerEnd(tokens, j)
+			}
+		default:
+			// Распространение ...other и вычисляемые ключи [key] не дают известных имен
+			j = initializerEnd(tokens, j+1)
+		}
+
+		if j < len(tokens) && tokens[j].Is(TokenPunctuator, ",") {
+			j++
+		} else if j < len(tokens) && !tokens[j].Is(TokenPunctuator, "}") {
+			break
+		}
+	}
+
+	return exports
+}
+
+// exportValue описывает экспорт exported значения, начинающегося с индекса i:
+// идентификатор экспортирует локальное имя, require() - имя другого модуля,
+// прочие выражения экспортируются как анонимные
+func exportValue(tokens []Token, i int, exported string, line int) moduleExport {
+	exp := moduleExport{Exported: exported, Line: line}
+	if i >= len(tokens) {
+		return exp
+	}
+	if specifier, imported, _, ok := parseRequireCall(tokens, i); ok {
+		return reExport(exported, specifier, imported, line)
+	}
+	if tokens[i].Type == TokenIdentifier && !reservedWords[tokens[i].Value] && initializerEnd(tokens, i) == i+1 {
+		exp.Local = tokens[i].Value
+	}
+	return exp
+}
+
+// reExport описывает реэкспорт имени imported модуля specifier под именем exported
+func reExport(exported string, specifier Token, imported string, line int) moduleExport {
+	return moduleExport{
+		Exported:       exported,
+		Specifier:      unquoteString(specifier.Value),
+		Imported:       imported,
+		Line:           line,
+		SpecifierStart: tokenStart(specifier),
+		SpecifierEnd:   tokenEnd(specifier),
+	}
+}
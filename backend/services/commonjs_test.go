@@ -0,0 +1,171 @@
+package services
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+func TestParseCommonJSImports(t *testing.T) {
+	src := `
+const config = require('./config');
+const { API_URL, TIMEOUT: timeout, RETRIES = 3, ...rest } = require("./settings");
+let helper = require('./helpers').helper;
+import legacy = require('./legacy');
+const handler = require('./handler')(options);
+`
+
+	tokens, err := Tokenize(src, false)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка лексера: %v", err)
+	}
+
+	module := parseModule(tokens)
+
+	expected := []struct {
+		specifier string
+		bindings  []importBinding
+	}{
+		{"./config", []importBinding{{Local: "config", Imported: "*"}}},
+		{"./settings", []importBinding{
+			{Local: "API_URL", Imported: "API_URL"},
+			{Local: "timeout", Imported: "TIMEOUT"},
+			{Local: "RETRIES", Imported: "RETRIES"},
+			{Local: "rest", Imported: "*"},
+		}},
+		{"./helpers", []importBinding{{Local: "helper", Imported: "helper"}}},
+		{"./legacy", []importBinding{{Local: "legacy", Imported: "*"}}},
+		// Результат вызова не связывается с экспортом, остается только ссылка на модуль
+		{"./handler", nil},
+	}
+
+	if len(module.Imports) != len(expected) {
+		t.Fatalf("Ожидается %d импортов, получено: %d (%+v)", len(expected), len(module.Imports), module.Imports)
+	}
+	for i, exp := range expected {
+		imp := module.Imports[i]
+		if imp.Specifier != exp.specifier || !imp.Require || imp.kind() != models.ImportStatic {
+			t.Errorf("Импорт %d: ожидается require(%q), получено: %+v", i, exp.specifier, imp)
+		}
+		if !reflect.DeepEqual(imp.Bindings, exp.bindings) {
+			t.Errorf("Импорт %q: ожидаются привязки %+v, получено: %+v", exp.specifier, exp.bindings, imp.Bindings)
+		}
+	}
+
+	// Привязки require() не являются константами файла
+	for _, decl := range extractDeclarations(src, tokens) {
+		if decl.Name != "handler" {
+			t.Errorf("Привязка require() записана как константа: %+v", decl)
+		}
+	}
+}
+
+func TestParseCommonJSExports(t *testing.T) {
+	src := `
+module.exports = { A, B: LOCAL_B, c: 1 + 2, d() {}, 'e-f': E, ...require('./spread'), nested: require('./nested').N };
+module.exports = CONFIG;
+module.exports.G = G_VALUE;
+exports.H = require('./other');
+exports.I = () => 1;
+module.exports = require('./all');
+`
+
+	tokens, err := Tokenize(src, false)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка лексера: %v", err)
+	}
+
+	module := parseModule(tokens)
+
+	expected := []moduleExport{
+		{Exported: "A", Local: "A"},
+		{Exported: "B", Local: "LOCAL_B"},
+		{Exported: "c"},
+		{Exported: "d"},
+		{Exported: "e-f", Local: "E"},
+		{Exported: "nested", Specifier: "./nested", Imported: "N"},
+		{Exported: "default", Local: "CONFIG"},
+		{Exported: "G", Local: "G_VALUE"},
+		{Exported: "H", Specifier: "./other", Imported: "*"},
+		{Exported: "I"},
+		{Exported: "*", Specifier: "./all", Imported: "*"},
+	}
+
+	if len(module.Exports) != len(expected) {
+		t.Fatalf("Ожидается %d экспортов, получено: %d (%+v)", len(expected), len(module.Exports), module.Exports)
+	}
+	for i, exp := range expected {
+		actual := module.Exports[i]
+		actual.Line = 0
+		actual.SpecifierStart, actual.SpecifierEnd = models.Position{}, models.Position{}
+		if actual != exp {
+			t.Errorf("Экспорт %d: ожидается %+v, получено: %+v", i, exp, actual)
+		}
+	}
+
+	// require() внутри значения экспорта без реэкспорта остается импортом, реэкспорты не дублируются
+	var specifiers []string
+	for _, imp := range module.Imports {
+		specifiers = append(specifiers, imp.Specifier)
+	}
+	if !reflect.DeepEqual(specifiers, []string{"./spread"}) {
+		t.Errorf("Ожидается единственный импорт ./spread, получено: %v", specifiers)
+	}
+}
+
+func TestCommonJSDependencies(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "commonjs-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"config.js":   "const API_URL = '/api';\nconst TIMEOUT = 5;\nmodule.exports = { API_URL, TIMEOUT };",
+		"defaults.js": "const DEFAULTS = { retries: 3 };\nmodule.exports = DEFAULTS;",
+		"flags.js":    "const BETA = true;\nexports.BETA = BETA;",
+		"api.js": `const { API_URL } = require('./config');
+const config = require('./config');
+const defaults = require('./defaults');
+const beta = require('./flags').BETA;
+const ENDPOINT = API_URL + '/users';
+const DELAY = config.TIMEOUT;
+const RETRIES = defaults.retries;
+const OPTIONS = { defaults, beta };`,
+	})
+
+	ds := NewDependencyService(NewFileService(tempDir, nil))
+	ds.BuildDependencyGraph()
+
+	var nodes []string
+	for _, node := range ds.Graph.Nodes {
+		nodes = append(nodes, node.ID)
+	}
+	sort.Strings(nodes)
+	expectedNodes := []string{
+		"api.js#DELAY", "api.js#ENDPOINT", "api.js#OPTIONS", "api.js#RETRIES",
+		"config.js#API_URL", "config.js#TIMEOUT", "defaults.js#DEFAULTS", "flags.js#BETA",
+	}
+	if !reflect.DeepEqual(nodes, expectedNodes) {
+		t.Errorf("Неверные константы:\nожидается %v\nполучено   %v", expectedNodes, nodes)
+	}
+
+	var edges []string
+	for _, edge := range ds.Graph.Edges {
+		edges = append(edges, edge.Source+" -> "+edge.Target)
+	}
+	sort.Strings(edges)
+	expectedEdges := []string{
+		"api.js#DELAY -> config.js#TIMEOUT",
+		"api.js#ENDPOINT -> config.js#API_URL",
+		"api.js#OPTIONS -> defaults.js#DEFAULTS",
+		"api.js#OPTIONS -> flags.js#BETA",
+		"api.js#RETRIES -> defaults.js#DEFAULTS",
+	}
+	if !reflect.DeepEqual(edges, expectedEdges) {
+		t.Errorf("Неверные зависимости:\nожидается %v\nполучено   %v", expectedEdges, edges)
+	}
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// FindCycles находит циклы в графе констант и в графе импортов между файлами
+func (ds *DependencyService) FindCycles() models.CycleReport {
+	ds.GraphMutex.RLock()
+	nodes := make([]string, 0, len(ds.Graph.Nodes))
+	names := make(map[string]models.Location, len(ds.Graph.Nodes))
+	for _, node := range ds.Graph.Nodes {
+		nodes = append(nodes, node.ID)
+		names[node.ID] = models.NewLocation(ds.relativePath(node.FilePath), node.NameStart, node.NameEnd)
+	}
+	edges := append([]models.Dependency(nil), ds.Graph.Edges...)
+	ds.GraphMutex.RUnlock()
+
+	moduleNodes, moduleEdges, imports := ds.moduleDependencies()
+
+	report := models.CycleReport{
+		Constants: findCycles(nodes, edges),
+		Modules:   findCycles(moduleNodes, moduleEdges),
+	}
+
+	// Зависимость константы указывает на имя зависимой константы,
+	// импорт модуля - на строку модуля в импортирующем файле
+	locateEdges(report.Constants, func(edge models.Dependency) models.Location { return names[edge.Source] })
+	locateEdges(report.Modules, func(edge models.Dependency) models.Location { return imports[edge] })
+
+	return report
+}
+
+// locateEdges заполняет места в коде для ребер циклов
+func locateEdges(cycles []models.Cycle, locate func(edge models.Dependency) models.Location) {
+	for i := range cycles {
+		cycles[i].Locations = make([]models.Location, len(cycles[i].Edges))
+		for j, edge := range cycles[i].Edges {
+			cycles[i].Locations[j] = locate(edge)
+		}
+	}
+}
+
+// moduleDependencies возвращает граф импортов между файлами проекта. Узлы
+// задаются путями относительно корня проекта. Импорты только типов не учитываются:
+// они удаляются при компиляции и не создают циклов во время выполнения.
+// Динамические импорты выполняются после загрузки модуля и тоже не учитываются.
+// Для каждого ребра возвращается место первого импорта, который его создает.
+func (ds *DependencyService) moduleDependencies() ([]string, []models.Dependency, map[models.Dependency]models.Location) {
+	files, links := ds.moduleLinks()
+
+	nodes := make([]string, 0, len(files))
+	for _, file := range files {
+		nodes = append(nodes, ds.relativePath(file))
+	}
+
+	var edges []models.Dependency
+	locations := make(map[models.Dependency]models.Location)
+	for _, link := range links {
+		if link.Kind == models.ImportTypeOnly || link.Kind == models.ImportDynamic {
+			continue
+		}
+		edge := models.Dependency{
+			Source: ds.relativePath(link.From),
+			Target: ds.relativePath(link.To),
+			Kind:   models.DependencyImport,
+		}
+		if _, seen := locations[edge]; !seen {
+			locations[edge] = models.NewLocation(edge.Source, link.Start, link.End)
+			edges = append(edges, edge)
+		}
+	}
+
+	return nodes, edges, locations
+}
+
+// relativePath возвращает путь к файлу относительно корня проекта
+func (ds *DependencyService) relativePath(filePath string) string {
+	relPath, err := filepath.Rel(ds.FileService.ProjectPath, filePath)
+	if err != nil {
+		return filepath.ToSlash(filePath)
+	}
+	return filepath.ToSlash(relPath)
+}
+
+// findCycles находит сильно связанные компоненты графа алгоритмом Тарьяна
+// и возвращает те из них, которые содержат цикл: из нескольких узлов или
+// из одного узла с петлей. Узлы и циклы отсортированы.
+func findCycles(nodes []string, edges []models.Dependency) []models.Cycle {
+	index := make(map[string]int, len(nodes))
+	for i, node := range nodes {
+		index[node] = i
+	}
+
+	adjacency := make([][]int, len(nodes))
+	selfLoop := make([]bool, len(nodes))
+	for _, edge := range edges {
+		source, ok := index[edge.Source]
+		if !ok {
+			continue
+		}
+		target, ok := index[edge.Target]
+		if !ok {
+			continue
+		}
+		if source == target {
+			selfLoop[source] = true
+		}
+		adjacency[source] = append(adjacency[source], target)
+	}
+
+	component := tarjan(adjacency)
+
+	// Группируем узлы по компонентам
+	members := make(map[int][]int)
+	for node, c := range component {
+		members[c] = append(members[c], node)
+	}
+
+	cyclic := make(map[int]*models.Cycle)
+	for c, nodesOfComponent := range members {
+		if len(nodesOfComponent) == 1 && !selfLoop[nodesOfComponent[0]] {
+			continue
+		}
+		cycle := &models.Cycle{Edges: []models.Dependency{}}
+		for _, node := range nodesOfComponent {
+			cycle.Nodes = append(cycle.Nodes, nodes[node])
+		}
+		sort.Strings(cycle.Nodes)
+		cyclic[c] = cycle
+	}
+
+	for _, edge := range edges {
+		source, ok := index[edge.Source]
+		if !ok {
+			continue
+		}
+		target, ok := index[edge.Target]
+		if !ok || component[source] != component[target] {
+			continue
+		}
+		if cycle := cyclic[component[source]]; cycle != nil {
+			cycle.Edges = append(cycle.Edges, edge)
+		}
+	}
+
+	cycles := make([]models.Cycle, 0, len(cyclic))
+	for _, cycle := range cyclic {
+		cycles = append(cycles, *cycle)
+	}
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i].Nodes[0] < cycles[j].Nodes[0]
+	})
+
+	return cycles
+}
+
+// tarjan возвращает номер сильно связанной компоненты для каждого узла.
+// Обход выполняется без рекурсии, чтобы длинные цепочки зависимостей
+// не переполняли стек.
+func tarjan(adjacency [][]int) []int {
+	count := len(adjacency)
+	order := make([]int, count)
+	low := make([]int, count)
+	component := make([]int, count)
+	onStack := make([]bool, count)
+	for i := range order {
+		order[i] = -1
+	}
+
+	type frame struct {
+		node int
+		next int // Индекс следующего исходящего ребра
+	}
+
+	var stack []int
+	counter, components := 0, 0
+
+	visit := func(node int) {
+		order[node], low[node] = counter, counter
+		counter++
+		stack = append(stack, node)
+		onStack[node] = true
+	}
+
+	for root := 0; root < count; root++ {
+		if order[root] != -1 {
+			continue
+		}
+
+		visit(root)
+		calls := []frame{{node: root}}
+
+		for len(calls) > 0 {
+			top := &calls[len(calls)-1]
+			if top.next < len(adjacency[top.node]) {
+				next := adjacency[top.node][top.next]
+				top.next++
+				if order[next] == -1 {
+					visit(next)
+					calls = append(calls, frame{node: next})
+				} else if onStack[next] && order[next] < low[top.node] {
+					low[top.node] = order[next]
+				}
+				continue
+			}
+
+			node := top.node
+			calls = calls[:len(calls)-1]
+			if len(calls) > 0 {
+				parent := calls[len(calls)-1].node
+				if low[node] < low[parent] {
+					low[parent] = low[node]
+				}
+			}
+
+			// Узел является корнем компоненты: снимаем ее со стека
+			if low[node] == order[node] {
+				for {
+					member := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[member] = false
+					component[member] = components
+					if member == node {
+						break
+					}
+				}
+				components++
+			}
+		}
+	}
+
+	return component
+}
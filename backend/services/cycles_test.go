@@ -0,0 +1,118 @@
+package services
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+func TestFindCyclesTarjan(t *testing.T) {
+	nodes := []string{"a", "b", "c", "d", "e", "f"}
+	edges := []models.Dependency{
+		{Source: "a", Target: "b"},
+		{Source: "b", Target: "c"},
+		{Source: "c", Target: "a"},
+		{Source: "c", Target: "d"},
+		{Source: "e", Target: "e"},
+		{Source: "d", Target: "f"},
+		{Source: "a", Target: "missing"},
+	}
+
+	cycles := findCycles(nodes, edges)
+
+	if len(cycles) != 2 {
+		t.Fatalf("Ожидается 2 цикла, получено: %d (%+v)", len(cycles), cycles)
+	}
+
+	if !equalStrings(cycles[0].Nodes, []string{"a", "b", "c"}) {
+		t.Errorf("Ожидается цикл a, b, c, получено: %v", cycles[0].Nodes)
+	}
+	if len(cycles[0].Edges) != 3 {
+		t.Errorf("Ожидается 3 ребра в цикле, получено: %d (%+v)", len(cycles[0].Edges), cycles[0].Edges)
+	}
+
+	if !equalStrings(cycles[1].Nodes, []string{"e"}) || len(cycles[1].Edges) != 1 {
+		t.Errorf("Ожидается петля e -> e, получено: %+v", cycles[1])
+	}
+}
+
+func TestFindCyclesLongChain(t *testing.T) {
+	// Длинная цепочка не должна переполнять стек
+	const size = 100000
+	nodes := make([]string, size)
+	edges := make([]models.Dependency, size)
+	for i := range nodes {
+		nodes[i] = "n" + strconv.Itoa(i)
+	}
+	for i := 0; i < size; i++ {
+		edges[i] = models.Dependency{Source: nodes[i], Target: nodes[(i+1)%size]}
+	}
+
+	cycles := findCycles(nodes, edges)
+	if len(cycles) != 1 || len(cycles[0].Nodes) != size {
+		t.Errorf("Ожидается один цикл из %d узлов, получено: %d циклов", size, len(cycles))
+	}
+}
+
+func TestDependencyServiceFindCycles(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "cycles-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"a.ts": `import { B } from './b';
+export const A = B + 1;
+export const LOCAL_1 = LOCAL_2;
+export const LOCAL_2 = LOCAL_1;`,
+		"b.ts": `import { A } from './a';
+import type { T } from './types';
+export const B = 2;
+export const AB = A;`,
+		"types.ts": `import { B } from './b';
+export type T = string;`,
+		"c.ts": `export * from './a';`,
+	})
+
+	fileService := NewFileService(tempDir, nil)
+	ds := NewDependencyService(fileService)
+	ds.BuildDependencyGraph()
+
+	report := ds.FindCycles()
+
+	// Модули a.ts и b.ts импортируют друг друга; импорт типов из types.ts не учитывается
+	if len(report.Modules) != 1 || !equalStrings(report.Modules[0].Nodes, []string{"a.ts", "b.ts"}) {
+		t.Errorf("Ожидается цикл между a.ts и b.ts, получено: %+v", report.Modules)
+	}
+
+	// Константы A и B не образуют цикла: B не зависит от A
+	if len(report.Constants) != 1 || !equalStrings(report.Constants[0].Nodes, []string{"a.ts#LOCAL_1", "a.ts#LOCAL_2"}) {
+		t.Errorf("Ожидается цикл между LOCAL_1 и LOCAL_2, получено: %+v", report.Constants)
+	}
+
+	if !report.HasCycles() {
+		t.Errorf("Ожидается, что отчет содержит циклы")
+	}
+
+	// Ребро между модулями указывает на строку модуля в импорте,
+	// ребро между константами - на имя зависимой константы
+	expectedLocations := map[models.Dependency]models.Location{
+		{Source: "a.ts", Target: "b.ts", Kind: models.DependencyImport}:                {File: "a.ts", Line: 1, Column: 19, EndLine: 1, EndColumn: 24},
+		{Source: "b.ts", Target: "a.ts", Kind: models.DependencyImport}:                {File: "b.ts", Line: 1, Column: 19, EndLine: 1, EndColumn: 24},
+		{Source: "a.ts#LOCAL_1", Target: "a.ts#LOCAL_2", Kind: models.DependencyLocal}: {File: "a.ts", Line: 3, Column: 14, EndLine: 3, EndColumn: 21},
+		{Source: "a.ts#LOCAL_2", Target: "a.ts#LOCAL_1", Kind: models.DependencyLocal}: {File: "a.ts", Line: 4, Column: 14, EndLine: 4, EndColumn: 21},
+	}
+	for _, cycle := range append(report.Modules, report.Constants...) {
+		if len(cycle.Locations) != len(cycle.Edges) {
+			t.Fatalf("Ожидается место для каждого ребра цикла, получено: %+v", cycle)
+		}
+		for i, edge := range cycle.Edges {
+			if expected := expectedLocations[edge]; cycle.Locations[i] != expected {
+				t.Errorf("Ребро %s -> %s: ожидается место %+v, получено %+v", edge.Source, edge.Target, expected, cycle.Locations[i])
+			}
+		}
+	}
+}
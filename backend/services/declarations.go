@@ -0,0 +1,703 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// declaration описывает объявление верхнего уровня
+type declaration struct {
+	Name       string          // Имя символа
+	Kind       string          // Вид объявления (models.SymbolConst, models.SymbolFunction, ...)
+	Type       string          // Тип из аннотации или выведенный из значения
+	Value      string          // Исходный текст значения
+	Line       int             // Номер строки объявления
+	NameStart  models.Position // Начало имени
+	NameEnd    models.Position // Конец имени
+	ValueStart models.Position // Начало значения
+	ValueEnd   models.Position // Конец значения
+	Refs       []string        // Идентификаторы, на которые ссылается объявление
+	Members    []memberRef     // Обращения к свойствам идентификаторов (ns.NAME)
+}
+
+// memberRef описывает обращение к свойству объекта: Object.Property
+type memberRef struct {
+	Object   string
+	Property string
+}
+
+// reservedWords содержит ключевые слова и литералы, которые не являются ссылками на константы
+var reservedWords = map[string]bool{
+	"await": true, "break": true, "case": true, "catch": true, "class": true,
+	"const": true, "continue": true, "debugger": true, "default": true, "delete": true,
+	"do": true, "else": true, "enum": true, "export": true, "extends": true,
+	"false": true, "finally": true, "for": true, "function": true, "if": true,
+	"import": true, "in": true, "instanceof": true, "new": true, "null": true,
+	"return": true, "super": true, "switch": true, "this": true, "throw": true,
+	"true": true, "try": true, "typeof": true, "var": true, "void": true,
+	"while": true, "with": true, "yield": true, "let": true, "static": true,
+	"undefined": true, "as": true, "satisfies": true, "of": true, "async": true,
+}
+
+// parseSourceFile читает файл и разбивает его на лексемы
+func parseSourceFile(filePath string) (string, []Token, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	src := string(content)
+	tokens, err := Tokenize(src, IsJSXFile(filePath))
+	if err != nil {
+		// Лексическая ошибка не фатальна: лексер восстанавливается и возвращает
+		// все прочитанные лексемы, поэтому только сообщаем о ней
+		return src, tokens, fmt.Errorf("tokenize %s: %w", filePath, err)
+	}
+
+	return src, tokens, nil
+}
+
+// extractDeclarations находит объявления верхнего уровня в потоке лексем:
+// переменные, функции, классы, перечисления, интерфейсы и псевдонимы типов
+func extractDeclarations(src string, tokens []Token) []declaration {
+	var declarations []declaration
+	declared := make(map[string]bool)
+
+	depth := 0
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+
+		if token.Type == TokenPunctuator {
+			switch token.Value {
+			case "{", "(", "[":
+				depth++
+			case "}", ")", "]":
+				if depth > 0 {
+					depth--
+				}
+			}
+			continue
+		}
+
+		if depth != 0 || token.Type != TokenIdentifier || !isStatementStart(tokens, i) {
+			continue
+		}
+
+		found, next := parseDeclaration(src, tokens, i)
+		for _, decl := range found {
+			// Перегрузки, слияние интерфейсов и повторные var объявляют одно имя
+			if !declared[decl.Name] {
+				declared[decl.Name] = true
+				declarations = append(declarations, decl)
+			}
+		}
+		if next > i {
+			i = next - 1
+		}
+	}
+
+	return declarations
+}
+
+// parseDeclaration разбирает объявление, начинающееся с ключевого слова на индексе i.
+// Возвращает найденные символы и индекс лексемы после объявления
+// (i, если объявление не распознано).
+func parseDeclaration(src string, tokens []Token, i int) ([]declaration, int) {
+	j := i
+	// async function, abstract class
+	if j+1 < len(tokens) && (tokens[j].Is(TokenIdentifier, "async") && tokens[j+1].Is(TokenIdentifier, "function") ||
+		tokens[j].Is(TokenIdentifier, "abstract") && tokens[j+1].Is(TokenIdentifier, "class")) {
+		j++
+	}
+	if j+1 >= len(tokens) {
+		return nil, i
+	}
+	next := tokens[j+1]
+
+	switch tokens[j].Value {
+	case "const":
+		if next.Is(TokenIdentifier, "enum") {
+			// const enum - перечисление TypeScript
+			return parseEnum(src, tokens, j+1)
+		}
+		return parseVariableDeclaration(src, tokens, j+1, models.SymbolConst)
+	case "let", "var":
+		if next.Type != TokenIdentifier && !next.Is(TokenPunctuator, "{") && !next.Is(TokenPunctuator, "[") {
+			return nil, i
+		}
+		return parseVariableDeclaration(src, tokens, j+1, tokens[j].Value)
+	case "function":
+		return parseFunction(src, tokens, i, j)
+	case "class":
+		return parseClass(src, tokens, i, j)
+	case "enum":
+		return parseEnum(src, tokens, j)
+	case "interface":
+		if next.Type == TokenIdentifier {
+			return parseInterface(src, tokens, j)
+		}
+	case "type":
+		if next.Type == TokenIdentifier && j+2 < len(tokens) &&
+			(tokens[j+2].Is(TokenPunctuator, "=") || tokens[j+2].Is(TokenPunctuator, "<")) {
+			return parseTypeAlias(src, tokens, j)
+		}
+	}
+	return nil, i
+}
+
+// isStatementStart проверяет, что лексема с индексом i начинает инструкцию
+func isStatementStart(tokens []Token, i int) bool {
+	if i == 0 || tokens[i].NewlineBefore {
+		return true
+	}
+
+	prev := tokens[i-1]
+	switch {
+	case prev.Is(TokenPunctuator, ";"), prev.Is(TokenPunctuator, "}"):
+		return true
+	case prev.Is(TokenIdentifier, "export"), prev.Is(TokenIdentifier, "declare"):
+		return isStatementStart(tokens, i-1)
+	case prev.Is(TokenIdentifier, "default") && i > 1 && tokens[i-2].Is(TokenIdentifier, "export"):
+		// export default function / export default class
+		return isStatementStart(tokens, i-2)
+	default:
+		return false
+	}
+}
+
+// parseVariableDeclaration разбирает список деклараторов после ключевого слова const, let или var.
+// Константа должна иметь инициализатор, let и var могут объявляться без него.
+// Возвращает найденные символы и индекс лексемы после объявления.
+func parseVariableDeclaration(src string, tokens []Token, i int, kind string) ([]declaration, int) {
+	var declarations []declaration
+
+	for i < len(tokens) {
+		nameToken := tokens[i]
+		if nameToken.Type != TokenIdentifier {
+			// Деструктуризация и прочие шаблоны не являются именованными символами
+			return declarations, skipStatement(tokens, i)
+		}
+		i++
+
+		// Аннотация типа TypeScript
+		declType := ""
+		if i < len(tokens) && tokens[i].Is(TokenPunctuator, ":") {
+			typeStart := i + 1
+			i = typeAnnotationEnd(tokens, typeStart)
+			if i > typeStart {
+				declType = src[tokens[typeStart].Start:tokens[i-1].End]
+			}
+		}
+
+		var initializer []Token
+		if i < len(tokens) && tokens[i].Is(TokenPunctuator, "=") {
+			initStart := i + 1
+			i = initializerEnd(tokens, initStart)
+			initializer = tokens[initStart:i]
+		}
+
+		switch {
+		case len(initializer) == 0 && kind == models.SymbolConst:
+			// Объявление без инициализатора (declare const X: T)
+		case len(initializer) > 0 && isRequireInitializer(initializer):
+			// const x = require('./x') - это импорт CommonJS
+		default:
+			decl := declaration{
+				Name:      nameToken.Value,
+				Kind:      kind,
+				Type:      declType,
+				Line:      nameToken.Line,
+				NameStart: tokenStart(nameToken),
+				NameEnd:   tokenEnd(nameToken),
+			}
+			decl.ValueStart, decl.ValueEnd = decl.NameEnd, decl.NameEnd
+			if len(initializer) > 0 {
+				first, last := initializer[0], initializer[len(initializer)-1]
+				decl.Value = src[first.Start:last.End]
+				decl.ValueStart, decl.ValueEnd = tokenStart(first), tokenEnd(last)
+				decl.Refs, decl.Members = collectReferences(initializer)
+
+				switch {
+				case isFunctionInitializer(initializer):
+					decl.Kind = models.SymbolFunction
+				case initializer[0].Is(TokenIdentifier, "class"):
+					decl.Kind = models.SymbolClass
+				}
+				if decl.Type == "" {
+					decl.Type = inferType(initializer)
+				}
+			}
+			if decl.Type == "" {
+				decl.Type = "unknown"
+			}
+			declarations = append(declarations, decl)
+		}
+
+		if i < len(tokens) && tokens[i].Is(TokenPunctuator, ",") {
+			i++
+			continue
+		}
+		if i < len(tokens) && tokens[i].Is(TokenPunctuator, ";") {
+			i++
+		}
+		return declarations, i
+	}
+
+	return declarations, i
+}
+
+// tokenStart возвращает позицию начала лексемы
+func tokenStart(token Token) models.Position {
+	return models.Position{Line: token.Line, Column: token.Column, Offset: token.Start}
+}
+
+// tokenEnd возвращает позицию сразу после конца лексемы с учетом
+// переводов строк внутри многострочных строк и шаблонов
+func tokenEnd(token Token) models.Position {
+	end := models.Position{
+		Line:   token.Line,
+		Column: token.Column + utf8.RuneCountInString(token.Value),
+		Offset: token.End,
+	}
+	if last := strings.LastIndexByte(token.Value, '\n'); last >= 0 {
+		end.Line += strings.Count(token.Value, "\n")
+		end.Column = utf8.RuneCountInString(token.Value[last+1:]) + 1
+	}
+	return end
+}
+
+// typeAnnotationEnd возвращает индекс лексемы, завершающей аннотацию типа
+func typeAnnotationEnd(tokens []Token, i int) int {
+	depth := 0
+	for ; i < len(tokens); i++ {
+		token := tokens[i]
+		if token.Type != TokenPunctuator {
+			continue
+		}
+		switch token.Value {
+		case "{", "(", "[", "<":
+			depth++
+		case "}", ")", "]", ">":
+			depth--
+		case ">>":
+			depth -= 2
+		case ">>>":
+			depth -= 3
+		case "=", ",", ";":
+			if depth <= 0 {
+				return i
+			}
+		}
+	}
+	return i
+}
+
+// initializerEnd возвращает индекс лексемы, завершающей выражение инициализатора.
+// Перевод строки завершает инструкцию только там, где сработала бы
+// автоматическая вставка точки с запятой: следующая лексема не может
+// продолжить выражение.
+func initializerEnd(tokens []Token, start int) int {
+	depth := 0
+	for i := start; i < len(tokens); i++ {
+		token := tokens[i]
+
+		if depth == 0 && i > start && token.NewlineBefore &&
+			!continuesExpression(tokens[i-1], token) {
+			return i
+		}
+
+		if token.Type != TokenPunctuator {
+			continue
+		}
+
+		switch token.Value {
+		case "{", "(", "[":
+			depth++
+		case "}", ")", "]":
+			if depth == 0 {
+				return i
+			}
+			depth--
+		case ";", ",":
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens)
+}
+
+// continuationKeywords содержит слова, после которых или перед которыми выражение продолжается
+var continuationKeywords = map[string]bool{
+	"instanceof": true, "in": true, "as": true, "satisfies": true,
+}
+
+// continuesExpression проверяет, продолжает ли лексема next выражение,
+// завершившееся лексемой prev на предыдущей строке
+func continuesExpression(prev, next Token) bool {
+	// Внутри JSX-разметки переводы строк не значимы
+	if next.JSX || next.Type == TokenJSXText {
+		return true
+	}
+
+	// Предыдущая лексема требует операнд: "A +\n B", "A =>\n B", "typeof\n A"
+	switch prev.Type {
+	case TokenPunctuator:
+		switch prev.Value {
+		case ")", "]", "}", "++", "--":
+		case ">", "/>":
+			if !prev.JSX {
+				return true
+			}
+		default:
+			return true
+		}
+	case TokenIdentifier:
+		if regexPrecedingKeywords[prev.Value] || continuationKeywords[prev.Value] {
+			return true
+		}
+	case TokenTemplate:
+		// Незакрытая часть шаблона "`...${" продолжается подстановкой
+		if prev.Value[len(prev.Value)-1] == '{' {
+			return true
+		}
+	}
+
+	// Следующая лексема не может начинать инструкцию: "A\n + B", "A\n .then()"
+	switch next.Type {
+	case TokenPunctuator:
+		switch next.Value {
+		case "++", "--", "!", "~", "{", ";", "}", "@", "#", "...":
+			return false
+		}
+		return true
+	case TokenTemplate:
+		// Тегированный шаблон: tag\n`...`
+		return true
+	case TokenIdentifier:
+		return continuationKeywords[next.Value]
+	default:
+		return false
+	}
+}
+
+// skipStatement пропускает лексемы до конца текущей инструкции
+func skipStatement(tokens []Token, i int) int {
+	end := initializerEnd(tokens, i)
+	for end < len(tokens) && tokens[end].Is(TokenPunctuator, ",") {
+		end = initializerEnd(tokens, end+1)
+	}
+	if end < len(tokens) && tokens[end].Is(TokenPunctuator, ";") {
+		end++
+	}
+	return end
+}
+
+// isFunctionInitializer проверяет, является ли инициализатор функцией
+func isFunctionInitializer(initializer []Token) bool {
+	first := initializer[0]
+	if first.Is(TokenIdentifier, "function") {
+		return true
+	}
+	if first.Is(TokenIdentifier, "async") && len(initializer) > 1 && initializer[1].Is(TokenIdentifier, "function") {
+		return true
+	}
+
+	// Стрелочная функция: "=>" на верхнем уровне выражения
+	depth := 0
+	for _, token := range initializer {
+		if token.Type != TokenPunctuator {
+			continue
+		}
+		switch token.Value {
+		case "{", "(", "[":
+			depth++
+		case "}", ")", "]":
+			depth--
+		case "=>":
+			if depth == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inferType определяет тип константы по первой лексеме инициализатора
+func inferType(initializer []Token) string {
+	first := initializer[0]
+	switch {
+	case isFunctionInitializer(initializer):
+		return models.SymbolFunction
+	case first.Is(TokenIdentifier, "class"):
+		return models.SymbolClass
+	case first.Type == TokenString || first.Type == TokenTemplate:
+		return "string"
+	case first.Type == TokenNumber:
+		return "number"
+	case first.Is(TokenPunctuator, "-") && len(initializer) > 1 && initializer[1].Type == TokenNumber:
+		return "number"
+	case first.Is(TokenIdentifier, "true") || first.Is(TokenIdentifier, "false"):
+		return "boolean"
+	case first.Is(TokenPunctuator, "{"):
+		return "object"
+	case first.Is(TokenPunctuator, "["):
+		return "array"
+	default:
+		return "unknown"
+	}
+}
+
+// collectReferences собирает уникальные идентификаторы, которые используются в выражении,
+// и обращения к их свойствам. Имена свойств после точки, ключи объектных литералов,
+// атрибуты JSX и локальные имена функций (параметры и переменные) ссылками не считаются.
+func collectReferences(tokens []Token) ([]string, []memberRef) {
+	var refs []string
+	var members []memberRef
+	seen := make(map[string]bool)
+	seenMembers := make(map[memberRef]bool)
+	locals := localNames(tokens)
+	var brackets []string
+	// Глубина скобок ключевого слова class, тело которого еще не открыто (-1 - нет)
+	classDepth := -1
+
+	for i, token := range tokens {
+		if token.Type == TokenPunctuator {
+			switch token.Value {
+			case "{", "(", "[":
+				if token.Value == "{" && classDepth == len(brackets) {
+					brackets = append(brackets, "class")
+					classDepth = -1
+					continue
+				}
+				brackets = append(brackets, token.Value)
+			case "}", ")", "]":
+				if len(brackets) > 0 {
+					brackets = brackets[:len(brackets)-1]
+				}
+			}
+			continue
+		}
+
+		if token.Is(TokenIdentifier, "class") {
+			classDepth = len(brackets)
+		}
+		if token.Type != TokenIdentifier || reservedWords[token.Value] || token.Value[0] == '#' || locals[token.Value] {
+			continue
+		}
+
+		var prev, next Token
+		if i > 0 {
+			prev = tokens[i-1]
+		}
+		if i+1 < len(tokens) {
+			next = tokens[i+1]
+		}
+
+		// Обращение к свойству: obj.name, obj?.name
+		if prev.Is(TokenPunctuator, ".") || prev.Is(TokenPunctuator, "?.") {
+			continue
+		}
+
+		// Член класса: поле name = value, метод name() {} и модификаторы get, set
+		if len(brackets) > 0 && brackets[len(brackets)-1] == "class" && isClassMember(prev, token, next) {
+			continue
+		}
+
+		// Ключ объектного литерала { name: value } или член класса и интерфейса { name?: T; }
+		if len(brackets) > 0 && brackets[len(brackets)-1] == "{" &&
+			(prev.Is(TokenPunctuator, "{") || prev.Is(TokenPunctuator, ",") || prev.Is(TokenPunctuator, ";")) &&
+			i+1 < len(tokens) && (tokens[i+1].Is(TokenPunctuator, ":") || tokens[i+1].Is(TokenPunctuator, "?")) {
+			continue
+		}
+
+		// В JSX-теге ссылкой является только имя тега
+		if token.JSX && !prev.Is(TokenPunctuator, "<") && !prev.Is(TokenPunctuator, "</") {
+			continue
+		}
+
+		if !seen[token.Value] {
+			seen[token.Value] = true
+			refs = append(refs, token.Value)
+		}
+
+		// Обращение к свойству: NAME.prop или <NS.Component>
+		if i+2 < len(tokens) && (tokens[i+1].Is(TokenPunctuator, ".") || tokens[i+1].Is(TokenPunctuator, "?.")) &&
+			tokens[i+2].Type == TokenIdentifier {
+			member := memberRef{Object: token.Value, Property: tokens[i+2].Value}
+			if !seenMembers[member] {
+				seenMembers[member] = true
+				members = append(members, member)
+			}
+		}
+	}
+
+	return refs, members
+}
+
+// isClassMember проверяет, что идентификатор token в теле класса является
+// именем поля или метода либо модификатором, а не ссылкой
+func isClassMember(prev, token, next Token) bool {
+	if methodModifiers[token.Value] && next.Type == TokenIdentifier {
+		return true
+	}
+	memberStart := token.NewlineBefore || prev.Is(TokenPunctuator, "{") || prev.Is(TokenPunctuator, "}") ||
+		prev.Is(TokenPunctuator, ";") || prev.Is(TokenPunctuator, "*") ||
+		prev.Type == TokenIdentifier && methodModifiers[prev.Value]
+	if !memberStart || next.Type != TokenPunctuator {
+		return false
+	}
+	switch next.Value {
+	case "(", "=", ":", "?", ";", "!", "<":
+		return true
+	}
+	return false
+}
+
+// methodModifiers содержит слова, которые могут стоять перед именем метода
+var methodModifiers = map[string]bool{
+	"async": true, "static": true, "get": true, "set": true, "public": true, "private": true,
+	"protected": true, "readonly": true, "override": true, "abstract": true,
+}
+
+// localNames собирает имена, объявленные внутри выражения или тела функции:
+// параметры функций и методов, переменные, вложенные функции и классы, параметр catch.
+// Одноименные символы верхнего уровня внутри таких тел затенены, поэтому имя
+// не считается ссылкой во всем выражении.
+func localNames(tokens []Token) map[string]bool {
+	locals := make(map[string]bool)
+
+	for i, token := range tokens {
+		if token.Is(TokenPunctuator, "(") && isParameterList(tokens, i) {
+			addBindingNames(tokens, i, locals)
+			continue
+		}
+		if token.Type != TokenIdentifier || i+1 >= len(tokens) {
+			continue
+		}
+
+		next := tokens[i+1]
+		switch token.Value {
+		case "const", "let", "var":
+			if next.Type == TokenIdentifier && !reservedWords[next.Value] {
+				locals[next.Value] = true
+			} else if next.Is(TokenPunctuator, "{") || next.Is(TokenPunctuator, "[") {
+				addBindingNames(tokens, i+1, locals)
+			}
+		case "function", "class":
+			if next.Is(TokenPunctuator, "*") && i+2 < len(tokens) {
+				next = tokens[i+2]
+			}
+			if next.Type == TokenIdentifier && !reservedWords[next.Value] && !next.Is(TokenIdentifier, "implements") {
+				locals[next.Value] = true
+			}
+		case "catch":
+			if next.Is(TokenPunctuator, "(") {
+				addBindingNames(tokens, i+1, locals)
+			}
+		default:
+			// Единственный параметр стрелочной функции: x => x * 2
+			if next.Is(TokenPunctuator, "=>") && !reservedWords[token.Value] {
+				locals[token.Value] = true
+			}
+		}
+	}
+
+	return locals
+}
+
+// isParameterList проверяет, открывает ли "(" на индексе i список параметров:
+// стрелочной функции, функции или метода объекта и класса
+func isParameterList(tokens []Token, i int) bool {
+	end := matchingBracket(tokens, i)
+	if end+1 >= len(tokens) {
+		return false
+	}
+	after := tokens[end+1]
+	if after.Is(TokenPunctuator, "=>") {
+		return true
+	}
+	if !after.Is(TokenPunctuator, "{") && !after.Is(TokenPunctuator, ":") {
+		return false
+	}
+
+	// Параметры объявления, с которого начинаются лексемы: function NAME(a) {
+	if i == 0 {
+		return true
+	}
+	prev := tokens[i-1]
+	switch {
+	case prev.Is(TokenIdentifier, "function"), prev.Is(TokenPunctuator, "*"), prev.Is(TokenPunctuator, ">"),
+		prev.Is(TokenPunctuator, ">>"), prev.Is(TokenPunctuator, ">>>"):
+		return true
+	case prev.Type != TokenIdentifier || reservedWords[prev.Value]:
+		// if (A) {, switch (A) {, cond ? (A) : B
+		return false
+	}
+
+	// Метод: { name(a) {}, static name(a): T {} }; вызов в тернарном операторе
+	// cond ? fn(A) : B методом не является
+	if i < 2 || prev.NewlineBefore {
+		return true
+	}
+	before := tokens[i-2]
+	return before.Is(TokenPunctuator, "{") || before.Is(TokenPunctuator, "}") ||
+		before.Is(TokenPunctuator, ";") || before.Is(TokenPunctuator, ",") ||
+		before.Is(TokenPunctuator, "*") || before.Is(TokenIdentifier, "function") ||
+		before.Type == TokenIdentifier && methodModifiers[before.Value]
+}
+
+// addBindingNames добавляет в locals имена из списка параметров или шаблона
+// деструктуризации, открытого скобкой на индексе i: (a, { b: c }, [d], e = X, ...f).
+// Значения по умолчанию и аннотации типов пропускаются.
+func addBindingNames(tokens []Token, i int, locals map[string]bool) {
+	end := matchingBracket(tokens, i)
+	brackets := []string{tokens[i].Value}
+
+	for k := i + 1; k < end; k++ {
+		token := tokens[k]
+		top := ""
+		if len(brackets) > 0 {
+			top = brackets[len(brackets)-1]
+		}
+
+		if token.Type == TokenPunctuator {
+			switch token.Value {
+			case "(", "[", "{":
+				brackets = append(brackets, token.Value)
+			case ")", "]", "}":
+				if len(brackets) > 0 {
+					brackets = brackets[:len(brackets)-1]
+				}
+			case "=":
+				// Значение по умолчанию
+				k = initializerEnd(tokens, k+1) - 1
+			case ":":
+				// Аннотация типа параметра; в шаблоне { a: b } двоеточие задает локальное имя
+				if top != "{" {
+					k = initializerEnd(tokens, k+1) - 1
+				}
+			}
+			continue
+		}
+
+		if token.Type != TokenIdentifier || reservedWords[token.Value] {
+			continue
+		}
+		prev, next := tokens[k-1], tokens[k+1]
+		bindingPrev := prev.Is(TokenPunctuator, "(") || prev.Is(TokenPunctuator, "[") ||
+			prev.Is(TokenPunctuator, "{") || prev.Is(TokenPunctuator, ",") ||
+			prev.Is(TokenPunctuator, "...") || prev.Is(TokenPunctuator, ":") && top == "{"
+		bindingNext := next.Is(TokenPunctuator, ",") || next.Is(TokenPunctuator, ")") ||
+			next.Is(TokenPunctuator, "]") || next.Is(TokenPunctuator, "}") ||
+			next.Is(TokenPunctuator, "=") || next.Is(TokenPunctuator, "?") ||
+			next.Is(TokenPunctuator, ":") && top != "{"
+		if bindingPrev && bindingNext {
+			locals[token.Value] = true
+		}
+	}
+}
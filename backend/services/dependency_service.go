@@ -5,44 +5,76 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
+	"github.com/avor0n/dependency-graph-visualizer/config"
 	"github.com/avor0n/dependency-graph-visualizer/models"
 )
 
 // DependencyService представляет сервис для работы с зависимостями
 type DependencyService struct {
-	FileService  *FileService
-	Graph        models.DependencyGraph
-	ConstantMap  map[string]bool
-	GraphMutex   sync.RWMutex
+	FileService *FileService
+	Resolver    *ModuleResolver
+	Workers     int // Число файлов, анализируемых одновременно
+	// EntryPoints - абсолютные пути к точкам входа приложения
+	EntryPoints []string
+	Graph       models.DependencyGraph
+	ConstantMap map[string]bool // Идентификаторы всех найденных констант
+	GraphMutex  sync.RWMutex
+
+	// Импорты и экспорты каждого файла
+	modules map[string]*moduleInfo
+	// Имена констант, объявленных в каждом файле
+	declared map[string]map[string]bool
+	// Не допускает одновременного повторного анализа
+	reanalyzeMutex sync.Mutex
 }
 
 // NewDependencyService создает новый экземпляр DependencyService
 func NewDependencyService(fileService *FileService) *DependencyService {
 	return &DependencyService{
 		FileService: fileService,
+		Resolver:    NewModuleResolver(fileService.ProjectPath),
+		Workers:     config.DefaultWorkers,
 		Graph: models.DependencyGraph{
 			Nodes: []models.Constant{},
 			Edges: []models.Dependency{},
 		},
 		ConstantMap: make(map[string]bool),
+		modules:     make(map[string]*moduleInfo),
+		declared:    make(map[string]map[string]bool),
 	}
 }
 
-// BuildDependencyGraph строит граф зависимостей для всего проекта
+// Configure применяет настройки анализа из конфигурации проекта
+func (ds *DependencyService) Configure(cfg *config.Config) {
+	ds.Workers = cfg.Workers
+	ds.Resolver.Aliases = cfg.Aliases
+	ds.Resolver.SetExtensions(cfg.Extensions)
+	ds.EntryPoints = nil
+	for _, entry := range cfg.EntryPoints {
+		ds.EntryPoints = append(ds.EntryPoints, filepath.Join(ds.FileService.ProjectPath, filepath.FromSlash(entry)))
+	}
+}
+
+// BuildDependencyGraph строит граф зависимостей для всего проекта.
+// Ход анализа выводится в stderr, чтобы не смешиваться с результатами в stdout.
 func (ds *DependencyService) BuildDependencyGraph() {
 	// Получаем список всех JS/TS файлов в проекте
 	files := ds.FileService.GetJSTSFiles()
-	fmt.Printf("Найдено %d JS/TS файлов\n", len(files))
+	fmt.Fprintf(os.Stderr, "Найдено %d JS/TS файлов\n", len(files))
 
 	// Используем WaitGroup для синхронизации горутин
 	var wg sync.WaitGroup
 
 	// Создаем пул из n горутин для параллельной обработки
 	// Ограничиваем количество одновременных горутин для экономии ресурсов
-	maxGoroutines := 10
+	maxGoroutines := ds.Workers
+	if maxGoroutines < 1 {
+		maxGoroutines = 1
+	}
 	guard := make(chan struct{}, maxGoroutines)
 
 	// Сначала находим все константы в проекте
@@ -60,7 +92,7 @@ func (ds *DependencyService) BuildDependencyGraph() {
 
 	wg.Wait() // Ждем завершения поиска констант
 
-	fmt.Printf("Найдено %d констант\n", len(ds.Graph.Nodes))
+	fmt.Fprintf(os.Stderr, "Найдено %d констант\n", len(ds.Graph.Nodes))
 
 	// Затем устанавливаем зависимости между константами
 	for _, file := range files {
@@ -77,211 +109,361 @@ func (ds *DependencyService) BuildDependencyGraph() {
 
 	wg.Wait() // Ждем завершения поиска зависимостей
 
-	fmt.Printf("Найдено %d зависимостей\n", len(ds.Graph.Edges))
+	fmt.Fprintf(os.Stderr, "Найдено %d зависимостей\n", len(ds.Graph.Edges))
 }
 
-// FindConstants находит константы в файле
+// FindConstants находит символы верхнего уровня (константы, функции, классы, типы),
+// импорты и экспорты в файле
 func (ds *DependencyService) FindConstants(filePath string) {
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		log.Printf("Error reading file %s: %v\n", filePath, err)
+	analysis, ok := ds.analyzeFile(filePath)
+	if !ok {
 		return
 	}
 
-	// Простой поиск объявлений констант
-	lines := strings.Split(string(content), "\n")
+	ds.GraphMutex.Lock()
+	ds.modules[filePath] = analysis.module
+	if ds.declared[filePath] == nil {
+		ds.declared[filePath] = make(map[string]bool)
+	}
+	ds.GraphMutex.Unlock()
 
-	// Отслеживаем уровень вложенности блоков кода
-	blockDepth := 0
-	inObjectDeclaration := false
-	currentObjectName := ""
+	for _, constant := range analysis.constants {
+		// Безопасно добавляем константу в граф
+		ds.GraphMutex.Lock()
+		ds.Graph.Nodes = append(ds.Graph.Nodes, constant)
+		ds.ConstantMap[constant.ID] = true
+		ds.declared[filePath][constant.Name] = true
+		ds.GraphMutex.Unlock()
 
-	for lineNum, line := range lines {
-		trimmedLine := strings.TrimSpace(line)
+		log.Printf("Found %s %s in file %s at line %d\n", constant.Kind, constant.Name, filePath, constant.LineNum)
+	}
+}
 
-		// Пропускаем пустые строки и комментарии
-		if trimmedLine == "" || strings.HasPrefix(trimmedLine, "//") {
-			continue
+// fileAnalysis содержит результат разбора одного файла
+type fileAnalysis struct {
+	module    *moduleInfo
+	constants []models.Constant
+}
+
+// analyzeFile разбирает файл и возвращает его импорты, экспорты и константы,
+// не изменяя состояние сервиса
+func (ds *DependencyService) analyzeFile(filePath string) (fileAnalysis, bool) {
+	src, tokens, err := parseSourceFile(filePath)
+	if err != nil {
+		log.Printf("Error parsing file %s: %v\n", filePath, err)
+		if tokens == nil {
+			return fileAnalysis{}, false
 		}
+	}
 
-		// Отслеживаем уровень вложенности
-		openBraces := strings.Count(line, "{")
-		closeBraces := strings.Count(line, "}")
-		blockDepth += openBraces - closeBraces
-
-		// Проверяем начало объявления объекта
-		if strings.Contains(trimmedLine, "={") || strings.Contains(trimmedLine, "= {") {
-			inObjectDeclaration = true
-			// Сохраняем имя объекта, если это константа
-			if strings.HasPrefix(trimmedLine, "const ") || strings.HasPrefix(trimmedLine, "export const ") {
-				parts := strings.Fields(trimmedLine)
-				if len(parts) >= 2 {
-					if strings.HasPrefix(trimmedLine, "export const") && len(parts) >= 3 {
-						currentObjectName = parts[2]
-					} else {
-						currentObjectName = parts[1]
-					}
-					currentObjectName = strings.Split(currentObjectName, "=")[0]
-					currentObjectName = strings.TrimSpace(currentObjectName)
-				}
-			}
+	analysis := fileAnalysis{module: parseModule(tokens)}
+	for _, decl := range extractDeclarations(src, tokens) {
+		analysis.constants = append(analysis.constants, models.Constant{
+			ID:         ds.constantID(filePath, decl.Name),
+			Name:       decl.Name,
+			Kind:       decl.Kind,
+			Value:      decl.Value,
+			Type:       decl.Type,
+			FilePath:   filePath,
+			LineNum:    decl.Line,
+			NameStart:  decl.NameStart,
+			NameEnd:    decl.NameEnd,
+			ValueStart: decl.ValueStart,
+			ValueEnd:   decl.ValueEnd,
+		})
+	}
+
+	return analysis, true
+}
+
+// FindDependencies находит зависимости констант файла от констант этого же файла
+// и от констант других модулей, импортированных через import
+func (ds *DependencyService) FindDependencies(filePath string) {
+	for _, dependency := range ds.dependenciesOf(filePath) {
+		ds.GraphMutex.Lock()
+		ds.Graph.Edges = append(ds.Graph.Edges, dependency)
+		ds.GraphMutex.Unlock()
+
+		log.Printf("Found dependency: %s -> %s\n", dependency.Source, dependency.Target)
+	}
+}
+
+// dependenciesOf возвращает зависимости констант файла, не изменяя граф.
+// Константы и модули проекта должны быть уже найдены.
+func (ds *DependencyService) dependenciesOf(filePath string) []models.Dependency {
+	src, tokens, err := parseSourceFile(filePath)
+	if err != nil {
+		log.Printf("Error parsing file %s: %v\n", filePath, err)
+		if tokens == nil {
+			return nil
 		}
+	}
 
-		// Проверяем конец объявления объекта
-		if inObjectDeclaration && closeBraces > 0 && blockDepth == 0 {
-			inObjectDeclaration = false
-			// Если это был объект-константа, добавляем его
-			if currentObjectName != "" {
-				// Создаем константу для объекта
-				constant := models.Constant{
-					Name:     currentObjectName,
-					Value:    "object",
-					Type:     "object",
-					FilePath: filePath,
-					LineNum:  lineNum + 1,
-				}
+	module := ds.moduleInfo(filePath)
+
+	var dependencies []models.Dependency
 
-				// Безопасно добавляем константу в граф
-				ds.GraphMutex.Lock()
-				ds.Graph.Nodes = append(ds.Graph.Nodes, constant)
-				ds.ConstantMap[currentObjectName] = true
-				ds.GraphMutex.Unlock()
+	// Для каждой константы из файла ищем ссылки на другие константы в ее инициализаторе
+	for _, decl := range extractDeclarations(src, tokens) {
+		if !ds.isDeclared(filePath, decl.Name) {
+			continue
+		}
 
-				log.Printf("Found constant %s in file %s at line %d\n", currentObjectName, filePath, lineNum+1)
-				currentObjectName = ""
+		sourceID := ds.constantID(filePath, decl.Name)
+		seen := make(map[string]bool)
+		addDependency := func(targetFile, target, kind string) {
+			targetID := ds.constantID(targetFile, target)
+			if seen[targetID] || targetID == sourceID {
+				return
 			}
+			seen[targetID] = true
+
+			dependencies = append(dependencies, models.Dependency{
+				Source: sourceID,
+				Target: targetID,
+				Kind:   kind,
+			})
 		}
 
-		// Если мы внутри объявления объекта, пропускаем строку
-		if inObjectDeclaration {
-			continue
+		for _, ref := range decl.Refs {
+			if ds.isDeclared(filePath, ref) {
+				addDependency(filePath, ref, models.DependencyLocal)
+				continue
+			}
+
+			// Ссылка на импортированное имя: import { A } from './a' или const { A } = require('./a')
+			imp, binding, ok := module.binding(ref)
+			if !ok {
+				continue
+			}
+			exported, ok := imp.importedName(binding)
+			if !ok {
+				continue
+			}
+			if targetFile, target, ok := ds.resolveImport(filePath, imp.Specifier, exported); ok {
+				addDependency(targetFile, target, models.DependencyImport)
+			}
 		}
 
-		// Если мы находимся на верхнем уровне файла
-		if blockDepth == 0 && (strings.HasPrefix(trimmedLine, "const ") || strings.HasPrefix(trimmedLine, "export const ")) &&
-			!strings.Contains(trimmedLine, "function") && !strings.Contains(trimmedLine, "=>") {
-			// Извлекаем имя константы
-			parts := strings.Fields(trimmedLine)
-			if len(parts) >= 2 {
-				constName := parts[1]
-				if strings.HasPrefix(trimmedLine, "export const") && len(parts) >= 3 {
-					constName = parts[2]
+		// Обращение к экспорту через пространство имен: import * as ns from './a'; ns.A.
+		// Для const ns = require('./a') свойство, не найденное среди экспортов,
+		// относится к значению module.exports = VALUE.
+		for _, member := range decl.Members {
+			imp, binding, ok := module.binding(member.Object)
+			if !ok || binding.Imported != "*" || ds.isDeclared(filePath, member.Object) {
+				continue
+			}
+			if targetFile, target, ok := ds.resolveImport(filePath, imp.Specifier, member.Property); ok {
+				addDependency(targetFile, target, models.DependencyImport)
+			} else if imp.Require {
+				if targetFile, target, ok := ds.resolveImport(filePath, imp.Specifier, "default"); ok {
+					addDependency(targetFile, target, models.DependencyImport)
 				}
+			}
+		}
+	}
 
-				// Удаляем все после знака =, : или запятой
-				constName = strings.Split(constName, "=")[0]
-				constName = strings.Split(constName, ":")[0]
-				constName = strings.Split(constName, ",")[0]
-				constName = strings.TrimSpace(constName)
-
-				// Поиск значения константы
-				value := ""
-				if strings.Contains(trimmedLine, "=") {
-					valuePart := strings.Split(trimmedLine, "=")[1]
-					value = strings.TrimSpace(valuePart)
-					// Удаляем возможную точку с запятой в конце
-					value = strings.TrimSuffix(value, ";")
-				}
+	return dependencies
+}
 
-				// Определяем тип константы
-				constType := "unknown"
-				if strings.Contains(trimmedLine, ":") {
-					typePart := strings.Split(strings.Split(trimmedLine, ":")[1], "=")[0]
-					constType = strings.TrimSpace(typePart)
-				} else if strings.HasPrefix(value, "\"") || strings.HasPrefix(value, "'") {
-					constType = "string"
-				} else if strings.HasPrefix(value, "{") {
-					constType = "object"
-					// Пропускаем, так как уже обработали объекты выше
-					continue
-				} else if strings.HasPrefix(value, "[") {
-					constType = "array"
-				} else if value == "true" || value == "false" {
-					constType = "boolean"
-				} else if _, err := fmt.Sscanf(value, "%f", new(float64)); err == nil {
-					constType = "number"
-				}
+// constantID возвращает идентификатор константы верхнего уровня файла
+func (ds *DependencyService) constantID(filePath, name string) string {
+	return models.NewConstantID(ds.relativePath(filePath), models.ModuleScope, name)
+}
 
-				// Создаем константу
-				constant := models.Constant{
-					Name:     constName,
-					Value:    value,
-					Type:     constType,
-					FilePath: filePath,
-					LineNum:  lineNum + 1,
-				}
+// isDeclared проверяет, объявлена ли константа name в файле filePath
+func (ds *DependencyService) isDeclared(filePath, name string) bool {
+	ds.GraphMutex.RLock()
+	defer ds.GraphMutex.RUnlock()
+	return ds.declared[filePath][name]
+}
 
-				// Безопасно добавляем константу в граф
-				ds.GraphMutex.Lock()
-				ds.Graph.Nodes = append(ds.Graph.Nodes, constant)
-				ds.ConstantMap[constName] = true
-				ds.GraphMutex.Unlock()
+// moduleInfo возвращает импорты и экспорты файла
+func (ds *DependencyService) moduleInfo(filePath string) *moduleInfo {
+	ds.GraphMutex.RLock()
+	defer ds.GraphMutex.RUnlock()
+	return ds.modules[filePath]
+}
 
-				log.Printf("Found constant %s in file %s at line %d\n", constName, filePath, lineNum+1)
+// resolveImport находит константу, которую файл fromFile импортирует
+// из модуля specifier под именем exported
+func (ds *DependencyService) resolveImport(fromFile, specifier, exported string) (string, string, bool) {
+	targetFile, ok := ds.Resolver.Resolve(fromFile, specifier)
+	if !ok {
+		return "", "", false
+	}
+	return ds.resolveExport(targetFile, exported, make(map[string]bool))
+}
+
+// resolveExport находит константу, экспортируемую файлом filePath под именем exported,
+// проходя по цепочкам реэкспортов
+func (ds *DependencyService) resolveExport(filePath, exported string, visited map[string]bool) (string, string, bool) {
+	key := filePath + "\x00" + exported
+	if visited[key] {
+		return "", "", false
+	}
+	visited[key] = true
+
+	module := ds.moduleInfo(filePath)
+	if module == nil {
+		return "", "", false
+	}
+
+	for _, exp := range module.Exports {
+		if exp.Exported != exported {
+			continue
+		}
+
+		if exp.Specifier == "" {
+			if exp.Local == "" {
+				return "", "", false
 			}
+			return ds.resolveBinding(filePath, exp.Local, visited)
 		}
+
+		// Реэкспорт пространства имен не указывает на конкретную константу
+		if exp.Imported == "*" {
+			return "", "", false
+		}
+		targetFile, ok := ds.Resolver.Resolve(filePath, exp.Specifier)
+		if !ok {
+			return "", "", false
+		}
+		return ds.resolveExport(targetFile, exp.Imported, visited)
 	}
-}
 
-// FindDependencies находит зависимости между константами
-func (ds *DependencyService) FindDependencies(filePath string) {
-	// Проверяем существование файла
-	_, err := os.Stat(filePath)
-	if err != nil {
-		log.Printf("Error accessing file %s: %v\n", filePath, err)
-		return
+	// export * from './other' не реэкспортирует default
+	if exported == "default" {
+		return "", "", false
+	}
+	for _, exp := range module.Exports {
+		if exp.Exported != "*" {
+			continue
+		}
+		targetFile, ok := ds.Resolver.Resolve(filePath, exp.Specifier)
+		if !ok {
+			continue
+		}
+		if file, name, ok := ds.resolveExport(targetFile, exported, visited); ok {
+			return file, name, true
+		}
 	}
 
-	// Получаем список всех констант
-	var constants []string
-	ds.GraphMutex.RLock()
-	for _, node := range ds.Graph.Nodes {
-		constants = append(constants, node.Name)
+	return "", "", false
+}
+
+// resolveBinding находит константу по локальному имени файла: объявленную
+// в самом файле или импортированную и затем реэкспортированную
+func (ds *DependencyService) resolveBinding(filePath, local string, visited map[string]bool) (string, string, bool) {
+	if ds.isDeclared(filePath, local) {
+		return filePath, local, true
 	}
-	ds.GraphMutex.RUnlock()
 
-	// Ищем зависимости между константами в этом файле
+	imp, binding, ok := ds.moduleInfo(filePath).binding(local)
+	if !ok {
+		return "", "", false
+	}
+	exported, ok := imp.importedName(binding)
+	if !ok {
+		return "", "", false
+	}
+	targetFile, ok := ds.Resolver.Resolve(filePath, imp.Specifier)
+	if !ok {
+		return "", "", false
+	}
+	return ds.resolveExport(targetFile, exported, visited)
+}
 
-	// Карта констант в этом файле
-	fileConstants := make(map[string]models.Constant)
+// moduleLink описывает ссылку одного файла проекта на другой: одну инструкцию
+// import, export ... from или вызов import()/require()
+type moduleLink struct {
+	From     string   // Импортирующий файл
+	To       string   // Файл, на который указывает спецификатор
+	Kind     string   // Вид импорта (models.ImportStatic, ...)
+	ReExport bool     // export ... from
+	Lazy     bool     // import() внутри lazy(() => import(...))
+	Symbols  []string // Импортируемые имена
+	Line     int      // Номер строки инструкции
+
+	// Положение строки модуля в импортирующем файле
+	Start, End models.Position
+}
 
-	// Заполняем карту констант для этого файла
+// moduleLinks возвращает отсортированный список проанализированных файлов
+// и разрешенные ссылки между ними
+func (ds *DependencyService) moduleLinks() ([]string, []moduleLink) {
 	ds.GraphMutex.RLock()
-	for _, node := range ds.Graph.Nodes {
-		if node.FilePath == filePath {
-			fileConstants[node.Name] = node
-		}
+	modules := make(map[string]*moduleInfo, len(ds.modules))
+	for file, module := range ds.modules {
+		modules[file] = module
 	}
 	ds.GraphMutex.RUnlock()
 
-	// Для каждой константы из файла ищем зависимости
-	for constName, constant := range fileConstants {
-		// Ищем использование других констант в значении этой константы
-		for otherName := range fileConstants {
-			// Пропускаем сравнение константы с самой собой
-			if constName == otherName {
-				continue
+	files := make([]string, 0, len(modules))
+	for file := range modules {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var links []moduleLink
+	for _, file := range files {
+		addLink := func(specifier string, link moduleLink) {
+			target, ok := ds.Resolver.Resolve(file, specifier)
+			if ok && modules[target] != nil {
+				link.From, link.To = file, target
+				links = append(links, link)
 			}
+		}
+		for _, imp := range modules[file].Imports {
+			addLink(imp.Specifier, imp.link())
+		}
 
-			// Проверяем, содержит ли значение константы имя другой константы
-			if strings.Contains(constant.Value, otherName) {
-				// Добавляем зависимость
-				dependency := models.Dependency{
-					Source: constName,
-					Target: otherName,
+		// Имена, реэкспортируемые одной инструкцией, объединяются в одну ссылку
+		exports := modules[file].Exports
+		for i := 0; i < len(exports); {
+			exp := exports[i]
+			if exp.Specifier == "" {
+				i++
+				continue
+			}
+			link := moduleLink{
+				Kind:     models.ImportTypeOnly,
+				ReExport: true,
+				Line:     exp.Line,
+				Start:    exp.SpecifierStart,
+				End:      exp.SpecifierEnd,
+			}
+			for ; i < len(exports) && exports[i].Specifier != "" && exports[i].SpecifierStart == exp.SpecifierStart; i++ {
+				link.Symbols = append(link.Symbols, exports[i].Imported)
+				if !exports[i].TypeOnly {
+					link.Kind = models.ImportStatic
 				}
-
-				ds.GraphMutex.Lock()
-				ds.Graph.Edges = append(ds.Graph.Edges, dependency)
-				ds.GraphMutex.Unlock()
-
-				log.Printf("Found dependency: %s -> %s in file %s\n", constName, otherName, filePath)
 			}
+			addLink(exp.Specifier, link)
 		}
 	}
+
+	return files, links
 }
 
-// GetFileDependencies возвращает зависимости для указанного файла
+// link возвращает ссылку на импортируемый модуль без файлов From и To
+func (imp moduleImport) link() moduleLink {
+	symbols := make([]string, 0, len(imp.Bindings))
+	for _, binding := range imp.Bindings {
+		symbols = append(symbols, binding.Imported)
+	}
+	return moduleLink{
+		Kind:    imp.kind(),
+		Lazy:    imp.Lazy,
+		Symbols: symbols,
+		Line:    imp.Line,
+		Start:   imp.SpecifierStart,
+		End:     imp.SpecifierEnd,
+	}
+}
+
+// GetFileDependencies возвращает константы указанного файла, их зависимости
+// и соседние константы из других файлов, связанные с ними ребрами
 func (ds *DependencyService) GetFileDependencies(filePath string) models.DependencyGraph {
 	ds.GraphMutex.RLock()
 	defer ds.GraphMutex.RUnlock()
@@ -302,22 +484,30 @@ func (ds *DependencyService) GetFileDependencies(filePath string) models.Depende
 		Edges: []models.Dependency{},
 	}
 
-	// Карта для отслеживания констант, которые относятся к этому файлу
+	// Идентификаторы констант выбранного файла
 	fileConstants := make(map[string]bool)
-
-	// Добавляем все константы из выбранного файла
 	for _, node := range ds.Graph.Nodes {
 		if node.FilePath == fileAbsPath {
-			subgraph.Nodes = append(subgraph.Nodes, node)
-			fileConstants[node.Name] = true
+			fileConstants[node.ID] = true
 		}
 	}
 
-	// Добавляем только зависимости между константами из этого файла
+	// Добавляем ребра, у которых хотя бы один конец находится в выбранном файле,
+	// и запоминаем константы из других файлов на другом конце ребра
+	neighbours := make(map[string]bool)
 	for _, edge := range ds.Graph.Edges {
-		// Добавляем ребро только если обе константы находятся в файле
-		if fileConstants[edge.Source] && fileConstants[edge.Target] {
-			subgraph.Edges = append(subgraph.Edges, edge)
+		if !fileConstants[edge.Source] && !fileConstants[edge.Target] {
+			continue
+		}
+		subgraph.Edges = append(subgraph.Edges, edge)
+		neighbours[edge.Source] = true
+		neighbours[edge.Target] = true
+	}
+
+	// Добавляем константы выбранного файла и соседние константы
+	for _, node := range ds.Graph.Nodes {
+		if fileConstants[node.ID] || neighbours[node.ID] {
+			subgraph.Nodes = append(subgraph.Nodes, node)
 		}
 	}
 
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
 )
 
 func TestNewDependencyService(t *testing.T) {
@@ -54,7 +56,7 @@ const BOOLEAN_CONST = true;
 // Константа с другой константой в значении
 const DEPENDENT_CONST = "prefix_" + SIMPLE_CONST;
 
-// Функция (не должна быть распознана как константа)
+// Функция (распознается как символ вида function, а не константа)
 const myFunction = () => {
     return "not a constant";
 };
@@ -89,15 +91,18 @@ export const EXPORTED_CONST = "exported value";
 	dependencyService.FindConstants(testFile)
 
 	// Проверяем результаты
-	expectedConstants := 8 // Количество констант в тестовом файле (без функции)
-	if len(dependencyService.Graph.Nodes) != expectedConstants {
-		t.Errorf("Ожидается %d констант, получено: %d", expectedConstants, len(dependencyService.Graph.Nodes))
+	expectedSymbols := 9 // Количество констант в тестовом файле и функция
+	if len(dependencyService.Graph.Nodes) != expectedSymbols {
+		t.Errorf("Ожидается %d символов, получено: %d", expectedSymbols, len(dependencyService.Graph.Nodes))
 	}
 
 	// Проверяем, что функция не была распознана как константа
 	for _, node := range dependencyService.Graph.Nodes {
-		if node.Name == "myFunction" {
-			t.Errorf("Функция myFunction была неправильно распознана как константа")
+		if node.Name == "myFunction" && node.Kind != models.SymbolFunction {
+			t.Errorf("Функция myFunction была неправильно распознана как %s", node.Kind)
+		}
+		if node.Name != "myFunction" && node.Kind != models.SymbolConst {
+			t.Errorf("Константа %s распознана как %s", node.Name, node.Kind)
 		}
 	}
 
@@ -187,7 +192,113 @@ const USER_API = USER_ENDPOINT + "/" + USER_ID;
 	for _, expected := range expectedEdges {
 		found := false
 		for _, edge := range dependencyService.Graph.Edges {
-			if edge.Source == expected.source && edge.Target == expected.target {
+			if edge.Source == "dependencies.js#"+expected.source && edge.Target == "dependencies.js#"+expected.target {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Ожидаемая зависимость не найдена: %s -> %s", expected.source, expected.target)
+		}
+	}
+}
+
+func TestFindConstantsMultiline(t *testing.T) {
+	// Создаем временную директорию и тестовый файл
+	tempDir, err := os.MkdirTemp("", "dep-service-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Инициализаторы, разбитые на несколько строк, без точек с запятой
+	testFile := filepath.Join(tempDir, "multiline.js")
+	testContent := `const A = 1
+const B = 2
+const SUM =
+  A + B;
+const CHAINED = [A, B]
+  .map(String)
+  .join(",")
+const LIST = [
+  A,
+  B,
+]
+const TOTAL = SUM
+  + CHAINED.length
+const NEXT = 3, AFTER_NEXT = NEXT
+`
+
+	if err := os.WriteFile(testFile, []byte(testContent), 0644); err != nil {
+		t.Fatalf("Не удалось создать тестовый файл: %v", err)
+	}
+
+	// Инициализируем сервисы
+	fileService := NewFileService(tempDir, nil)
+	dependencyService := NewDependencyService(fileService)
+
+	dependencyService.FindConstants(testFile)
+	dependencyService.FindDependencies(testFile)
+
+	expectedValues := map[string]string{
+		"A":          "1",
+		"B":          "2",
+		"SUM":        "A + B",
+		"CHAINED":    "[A, B]\n  .map(String)\n  .join(\",\")",
+		"LIST":       "[\n  A,\n  B,\n]",
+		"TOTAL":      "SUM\n  + CHAINED.length",
+		"NEXT":       "3",
+		"AFTER_NEXT": "NEXT",
+	}
+
+	if len(dependencyService.Graph.Nodes) != len(expectedValues) {
+		t.Errorf("Ожидается %d констант, получено: %d", len(expectedValues), len(dependencyService.Graph.Nodes))
+	}
+
+	for _, node := range dependencyService.Graph.Nodes {
+		expected, ok := expectedValues[node.Name]
+		if !ok {
+			t.Errorf("Неожиданная константа %s", node.Name)
+			continue
+		}
+		if node.Value != expected {
+			t.Errorf("Для %s ожидается значение %q, получено: %q", node.Name, expected, node.Value)
+		}
+		if testContent[node.ValueStart.Offset:node.ValueEnd.Offset] != node.Value {
+			t.Errorf("Для %s позиции инициализатора не совпадают со значением", node.Name)
+		}
+	}
+
+	// Проверяем позиции многострочного инициализатора
+	for _, node := range dependencyService.Graph.Nodes {
+		if node.Name != "LIST" {
+			continue
+		}
+		if node.ValueStart.Line != 8 || node.ValueStart.Column != 14 {
+			t.Errorf("Ожидается начало LIST в 8:14, получено: %d:%d", node.ValueStart.Line, node.ValueStart.Column)
+		}
+		if node.ValueEnd.Line != 11 || node.ValueEnd.Column != 2 {
+			t.Errorf("Ожидается конец LIST в 11:2, получено: %d:%d", node.ValueEnd.Line, node.ValueEnd.Column)
+		}
+	}
+
+	expectedEdges := []struct {
+		source string
+		target string
+	}{
+		{"SUM", "A"},
+		{"SUM", "B"},
+		{"CHAINED", "A"},
+		{"LIST", "B"},
+		{"TOTAL", "SUM"},
+		{"TOTAL", "CHAINED"},
+		{"AFTER_NEXT", "NEXT"},
+	}
+
+	for _, expected := range expectedEdges {
+		found := false
+		for _, edge := range dependencyService.Graph.Edges {
+			if edge.Source == "multiline.js#"+expected.source && edge.Target == "multiline.js#"+expected.target {
 				found = true
 				break
 			}
@@ -343,11 +454,116 @@ const USER_URL = API_URL + "/user";
 		return false
 	}
 
-	if !dependencyExists("USERS_ENDPOINT", "API_URL") {
+	if !dependencyExists("users.js#USERS_ENDPOINT", "users.js#API_URL") {
 		t.Errorf("Ожидаемая зависимость не найдена: USERS_ENDPOINT -> API_URL")
 	}
 
-	if !dependencyExists("USER_URL", "API_URL") {
+	if !dependencyExists("app.js#USER_URL", "app.js#API_URL") {
 		t.Errorf("Ожидаемая зависимость не найдена: USER_URL -> API_URL")
 	}
+
+	// Одноименные константы из разных файлов имеют разные идентификаторы
+	if len(dependencyService.ConstantMap) != expectedConstants {
+		t.Errorf("Ожидается %d уникальных идентификаторов, получено: %d", expectedConstants, len(dependencyService.ConstantMap))
+	}
+	for _, id := range []string{"config.js#API_URL", "users.js#API_URL", "app.js#API_URL"} {
+		if !dependencyService.ConstantMap[id] {
+			t.Errorf("Ожидается константа с идентификатором %s", id)
+		}
+	}
+}
+
+func TestCrossFileDependencies(t *testing.T) {
+	// Создаем временную директорию
+	tempDir, err := os.MkdirTemp("", "cross-file-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Модули с именованными, default, namespace импортами и реэкспортами
+	files := map[string]string{
+		"config.ts": `
+export const API_URL = "https://example.com/api";
+const TIMEOUT = 5000;
+export default TIMEOUT;
+`,
+		"index.ts": `
+export { API_URL as BASE_URL } from './config';
+export * from './limits';
+`,
+		"limits.ts": `
+export const MAX_ITEMS = 100;
+`,
+		"users.ts": `
+import TIMEOUT_MS, { API_URL } from './config';
+import { BASE_URL, MAX_ITEMS } from './index';
+import * as config from './config';
+const API_URL_COPY = "unrelated";
+export const USERS_URL = API_URL + "/users";
+export const USER_SETTINGS = { timeout: TIMEOUT_MS, limit: MAX_ITEMS, base: BASE_URL };
+export const NS_URL = config.API_URL;
+`,
+	}
+
+	for fileName, content := range files {
+		filePath := filepath.Join(tempDir, fileName)
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("Не удалось создать файл %s: %v", fileName, err)
+		}
+	}
+
+	fileService := NewFileService(tempDir, nil)
+	dependencyService := NewDependencyService(fileService)
+	dependencyService.BuildDependencyGraph()
+
+	configFile := filepath.Join(tempDir, "config.ts")
+	usersFile := filepath.Join(tempDir, "users.ts")
+
+	expectedEdges := []models.Dependency{
+		{Source: "users.ts#USERS_URL", Target: "config.ts#API_URL", Kind: models.DependencyImport},
+		{Source: "users.ts#USER_SETTINGS", Target: "config.ts#TIMEOUT", Kind: models.DependencyImport},
+		{Source: "users.ts#USER_SETTINGS", Target: "limits.ts#MAX_ITEMS", Kind: models.DependencyImport},
+		{Source: "users.ts#USER_SETTINGS", Target: "config.ts#API_URL", Kind: models.DependencyImport},
+		{Source: "users.ts#NS_URL", Target: "config.ts#API_URL", Kind: models.DependencyImport},
+	}
+
+	if len(dependencyService.Graph.Edges) != len(expectedEdges) {
+		t.Errorf("Ожидается %d зависимостей, получено: %d (%+v)",
+			len(expectedEdges), len(dependencyService.Graph.Edges), dependencyService.Graph.Edges)
+	}
+
+	for _, expected := range expectedEdges {
+		found := false
+		for _, edge := range dependencyService.Graph.Edges {
+			if edge == expected {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Ожидаемая зависимость не найдена: %s -> %s", expected.Source, expected.Target)
+		}
+	}
+
+	// Подграф файла конфигурации включает соседние константы из users.ts
+	configGraph := dependencyService.GetFileDependencies(configFile)
+	if len(configGraph.Edges) != 4 {
+		t.Errorf("Ожидается 4 ребра для config.ts, получено: %d", len(configGraph.Edges))
+	}
+
+	neighbours := map[string]bool{}
+	for _, node := range configGraph.Nodes {
+		if node.FilePath == usersFile {
+			neighbours[node.Name] = true
+		}
+	}
+	for _, name := range []string{"USERS_URL", "USER_SETTINGS", "NS_URL"} {
+		if !neighbours[name] {
+			t.Errorf("Ожидается соседняя константа %s из users.ts в подграфе config.ts", name)
+		}
+	}
+	if neighbours["API_URL_COPY"] {
+		t.Errorf("Константа API_URL_COPY не связана с config.ts и не должна попасть в подграф")
+	}
 }
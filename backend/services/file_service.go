@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/avor0n/dependency-graph-visualizer/config"
 	"github.com/avor0n/dependency-graph-visualizer/models"
 	"github.com/avor0n/dependency-graph-visualizer/utils"
 )
@@ -14,14 +15,28 @@ import (
 type FileService struct {
 	ProjectPath string
 	GitIgnore   *utils.GitIgnore
+
+	// Фильтры анализируемых файлов из конфигурации проекта
+	Extensions []string
+	Include    *utils.PatternSet // nil - все файлы
+	Exclude    *utils.PatternSet
 }
 
-// NewFileService создает новый экземпляр FileService
+// NewFileService создает новый экземпляр FileService с настройками по умолчанию
 func NewFileService(projectPath string, gitIgnore *utils.GitIgnore) *FileService {
-	return &FileService{
+	fs := &FileService{
 		ProjectPath: projectPath,
 		GitIgnore:   gitIgnore,
 	}
+	fs.Configure(config.Default())
+	return fs
+}
+
+// Configure применяет фильтры файлов из конфигурации проекта
+func (fs *FileService) Configure(cfg *config.Config) {
+	fs.Extensions = cfg.Extensions
+	fs.Include = cfg.IncludePatterns()
+	fs.Exclude = cfg.ExcludePatterns()
 }
 
 // ScanDirectory рекурсивно сканирует директорию и возвращает структуру
@@ -58,7 +73,7 @@ func (fs *FileService) ScanDirectory(relativePath string) models.FileNode {
 	for _, entry := range entries {
 		// Проверяем, соответствует ли файл правилам .gitignore
 		childRelPath := filepath.Join(relativePath, entry.Name())
-		if fs.GitIgnore != nil && fs.GitIgnore.IsIgnored(childRelPath) {
+		if fs.GitIgnore != nil && fs.GitIgnore.IsIgnored(childRelPath, entry.IsDir()) {
 			continue
 		}
 
@@ -69,7 +84,8 @@ func (fs *FileService) ScanDirectory(relativePath string) models.FileNode {
 	return node
 }
 
-// GetJSTSFiles получает список всех JS/TS файлов в проекте
+// GetJSTSFiles получает список анализируемых файлов проекта с учетом .gitignore,
+// расширений и шаблонов include/exclude из конфигурации
 func (fs *FileService) GetJSTSFiles() []string {
 	var files []string
 
@@ -85,23 +101,26 @@ func (fs *FileService) GetJSTSFiles() []string {
 		}
 
 		// Проверяем, соответствует ли файл правилам .gitignore
-		if fs.GitIgnore != nil && fs.GitIgnore.IsIgnored(relPath) {
+		if fs.GitIgnore != nil && fs.GitIgnore.IsIgnored(relPath, info.IsDir()) {
 			if info.IsDir() {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		// Игнорируем node_modules и другие служебные директории
-		if info.IsDir() && (info.Name() == "node_modules" || info.Name() == ".git" || strings.HasPrefix(info.Name(), ".")) {
+		// Директорию .git пропускаем всегда, остальные - по шаблонам exclude
+		if info.IsDir() && info.Name() == ".git" {
 			return filepath.SkipDir
 		}
+		if fs.Exclude.Match(relPath, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
 
-		// Добавляем только JS/TS файлы
-		if !info.IsDir() && (strings.HasSuffix(info.Name(), ".js") ||
-			strings.HasSuffix(info.Name(), ".jsx") ||
-			strings.HasSuffix(info.Name(), ".ts") ||
-			strings.HasSuffix(info.Name(), ".tsx")) {
+		if !info.IsDir() && fs.hasSourceExtension(info.Name()) &&
+			(fs.Include == nil || fs.Include.Match(relPath, false)) {
 			files = append(files, path)
 		}
 
@@ -114,3 +133,13 @@ func (fs *FileService) GetJSTSFiles() []string {
 
 	return files
 }
+
+// hasSourceExtension проверяет, входит ли расширение файла в список анализируемых
+func (fs *FileService) hasSourceExtension(name string) bool {
+	for _, ext := range fs.Extensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/avor0n/dependency-graph-visualizer/config"
 	"github.com/avor0n/dependency-graph-visualizer/utils"
 )
 
@@ -111,14 +112,14 @@ func TestGetJSTSFiles(t *testing.T) {
 
 	// Создаем тестовые файлы различных типов
 	testFiles := map[string]string{
-		filepath.Join(tempDir, "test.js"):             "JS file",
-		filepath.Join(tempDir, "test.jsx"):            "JSX file",
-		filepath.Join(tempDir, "test.ts"):             "TS file",
-		filepath.Join(tempDir, "test.tsx"):            "TSX file",
-		filepath.Join(tempDir, "test.txt"):            "Text file (должен быть проигнорирован)",
-		filepath.Join(subDir, "component.jsx"):        "Component JSX",
-		filepath.Join(subDir, "component.css"):        "CSS file (должен быть проигнорирован)",
-		filepath.Join(nodeModulesDir, "module.js"):    "JS in node_modules (должен быть проигнорирован)",
+		filepath.Join(tempDir, "test.js"):          "JS file",
+		filepath.Join(tempDir, "test.jsx"):         "JSX file",
+		filepath.Join(tempDir, "test.ts"):          "TS file",
+		filepath.Join(tempDir, "test.tsx"):         "TSX file",
+		filepath.Join(tempDir, "test.txt"):         "Text file (должен быть проигнорирован)",
+		filepath.Join(subDir, "component.jsx"):     "Component JSX",
+		filepath.Join(subDir, "component.css"):     "CSS file (должен быть проигнорирован)",
+		filepath.Join(nodeModulesDir, "module.js"): "JS in node_modules (должен быть проигнорирован)",
 	}
 
 	for filePath, content := range testFiles {
@@ -166,3 +167,90 @@ func TestGetJSTSFiles(t *testing.T) {
 		}
 	}
 }
+
+func TestGetJSTSFilesNestedGitIgnore(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "nested-gitignore-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		".gitignore":                  "*.gen.ts\n",
+		"src/index.ts":                "export const A = 1;",
+		"src/api.gen.ts":              "export const B = 2;",
+		"packages/app/.gitignore":     "/build/\n!keep.gen.ts\n",
+		"packages/app/build/out.js":   "export const C = 3;",
+		"packages/app/src/build/b.ts": "export const D = 4;",
+		"packages/app/keep.gen.ts":    "export const E = 5;",
+		"packages/app/other.gen.ts":   "export const F = 6;",
+		"packages/lib/build/out.js":   "export const G = 7;",
+	})
+
+	fileService := NewFileService(tempDir, utils.LoadGitIgnore(tempDir))
+
+	var files []string
+	for _, file := range fileService.GetJSTSFiles() {
+		rel, _ := filepath.Rel(tempDir, file)
+		files = append(files, filepath.ToSlash(rel))
+	}
+
+	expected := []string{
+		"packages/app/keep.gen.ts",
+		"packages/app/src/build/b.ts",
+		"packages/lib/build/out.js",
+		"src/index.ts",
+	}
+	if !equalStrings(files, expected) {
+		t.Errorf("Ожидаются файлы %v, получено: %v", expected, files)
+	}
+
+	// Дерево файлов учитывает те же правила
+	tree := fileService.ScanDirectory("packages/app")
+	for _, child := range tree.Children {
+		if child.Name == "build" || child.Name == "other.gen.ts" {
+			t.Errorf("Ожидается, что %s скрыт вложенным .gitignore", child.Path)
+		}
+	}
+}
+
+func TestGetJSTSFilesConfig(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "config-files-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"src/index.ts":            "",
+		"src/index.test.ts":       "",
+		"src/worker.mts":          "",
+		"src/legacy/old.js":       "",
+		"scripts/build.ts":        "",
+		"node_modules/lib/lib.ts": "",
+		".storybook/main.ts":      "",
+	})
+
+	cfg, err := config.Parse([]byte(`{
+  "include": ["src/"],
+  "exclude": ["*.test.ts", "src/legacy/"],
+  "extensions": [".ts", ".mts", ".js"]
+}`))
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	fileService := NewFileService(tempDir, nil)
+	fileService.Configure(cfg)
+
+	var files []string
+	for _, file := range fileService.GetJSTSFiles() {
+		rel, _ := filepath.Rel(tempDir, file)
+		files = append(files, filepath.ToSlash(rel))
+	}
+
+	expected := []string{"src/index.ts", "src/worker.mts"}
+	if !equalStrings(files, expected) {
+		t.Errorf("Ожидаются файлы %v, получено: %v", expected, files)
+	}
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// ErrNodeNotFound возвращается, если в графе нет константы с указанным идентификатором
+var ErrNodeNotFound = errors.New("node not found")
+
+// GetImpact обходит граф в ширину от константы nodeID и возвращает достижимый подграф
+// с расстоянием до каждого узла. Направление ImpactUp собирает константы, зависящие
+// от узла, ImpactDown - константы, от которых он зависит. Глубина 0 снимает ограничение.
+func (ds *DependencyService) GetImpact(nodeID, direction string, depth int) (models.ImpactGraph, error) {
+	if direction != models.ImpactUp && direction != models.ImpactDown {
+		return models.ImpactGraph{}, fmt.Errorf("unknown direction %q", direction)
+	}
+	if depth < 0 {
+		return models.ImpactGraph{}, fmt.Errorf("depth must not be negative")
+	}
+
+	ds.GraphMutex.RLock()
+	defer ds.GraphMutex.RUnlock()
+
+	constants := make(map[string]models.Constant, len(ds.Graph.Nodes))
+	for _, node := range ds.Graph.Nodes {
+		constants[node.ID] = node
+	}
+	if _, ok := constants[nodeID]; !ok {
+		return models.ImpactGraph{}, fmt.Errorf("%w: %s", ErrNodeNotFound, nodeID)
+	}
+
+	// Соседи узла в направлении обхода
+	neighbours := make(map[string][]string)
+	for _, edge := range ds.Graph.Edges {
+		if direction == models.ImpactUp {
+			neighbours[edge.Target] = append(neighbours[edge.Target], edge.Source)
+		} else {
+			neighbours[edge.Source] = append(neighbours[edge.Source], edge.Target)
+		}
+	}
+
+	distance := map[string]int{nodeID: 0}
+	order := []string{nodeID}
+	for i := 0; i < len(order); i++ {
+		current := order[i]
+		if depth > 0 && distance[current] >= depth {
+			continue
+		}
+		for _, next := range neighbours[current] {
+			if _, seen := distance[next]; seen {
+				continue
+			}
+			distance[next] = distance[current] + 1
+			order = append(order, next)
+		}
+	}
+
+	impact := models.ImpactGraph{
+		Root:      nodeID,
+		Direction: direction,
+		Depth:     depth,
+		Nodes:     make([]models.ImpactNode, 0, len(order)),
+		Edges:     []models.Dependency{},
+	}
+
+	// Узлы следуют в порядке обхода, то есть по возрастанию расстояния
+	for _, id := range order {
+		impact.Nodes = append(impact.Nodes, models.ImpactNode{
+			Constant: constants[id],
+			Distance: distance[id],
+		})
+	}
+
+	for _, edge := range ds.Graph.Edges {
+		_, sourceReached := distance[edge.Source]
+		_, targetReached := distance[edge.Target]
+		if sourceReached && targetReached {
+			impact.Edges = append(impact.Edges, edge)
+		}
+	}
+
+	return impact, nil
+}
+
+// FindNodes возвращает константы с идентификатором или именем query.
+// Точное совпадение идентификатора имеет приоритет над совпадением имени.
+func (ds *DependencyService) FindNodes(query string) []models.Constant {
+	ds.GraphMutex.RLock()
+	defer ds.GraphMutex.RUnlock()
+
+	var byName []models.Constant
+	for _, node := range ds.Graph.Nodes {
+		if node.ID == query {
+			return []models.Constant{node}
+		}
+		if node.Name == query {
+			byName = append(byName, node)
+		}
+	}
+
+	sort.Slice(byName, func(i, j int) bool { return byName[i].ID < byName[j].ID })
+	return byName
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// newImpactTestService создает сервис с графом:
+// D -> C -> B -> A, E -> A, F (без связей)
+func newImpactTestService() *DependencyService {
+	ds := NewDependencyService(&FileService{ProjectPath: "/project"})
+	for _, id := range []string{"x.js#A", "x.js#B", "x.js#C", "y.js#D", "y.js#E", "y.js#F"} {
+		ds.Graph.Nodes = append(ds.Graph.Nodes, models.Constant{ID: id})
+	}
+	ds.Graph.Edges = []models.Dependency{
+		{Source: "x.js#B", Target: "x.js#A", Kind: models.DependencyLocal},
+		{Source: "x.js#C", Target: "x.js#B", Kind: models.DependencyLocal},
+		{Source: "y.js#D", Target: "x.js#C", Kind: models.DependencyImport},
+		{Source: "y.js#E", Target: "x.js#A", Kind: models.DependencyImport},
+	}
+	return ds
+}
+
+func TestGetImpact(t *testing.T) {
+	ds := newImpactTestService()
+
+	tests := []struct {
+		node      string
+		direction string
+		depth     int
+		expected  map[string]int
+		edges     int
+	}{
+		{"x.js#A", models.ImpactUp, 0, map[string]int{"x.js#A": 0, "x.js#B": 1, "y.js#E": 1, "x.js#C": 2, "y.js#D": 3}, 4},
+		{"x.js#A", models.ImpactUp, 1, map[string]int{"x.js#A": 0, "x.js#B": 1, "y.js#E": 1}, 2},
+		{"y.js#D", models.ImpactDown, 0, map[string]int{"y.js#D": 0, "x.js#C": 1, "x.js#B": 2, "x.js#A": 3}, 3},
+		{"x.js#A", models.ImpactDown, 0, map[string]int{"x.js#A": 0}, 0},
+		{"y.js#F", models.ImpactUp, 0, map[string]int{"y.js#F": 0}, 0},
+	}
+
+	for _, test := range tests {
+		impact, err := ds.GetImpact(test.node, test.direction, test.depth)
+		if err != nil {
+			t.Fatalf("Неожиданная ошибка: %v", err)
+		}
+
+		if len(impact.Nodes) != len(test.expected) {
+			t.Errorf("%s %s (depth=%d): ожидается %d узлов, получено: %+v",
+				test.node, test.direction, test.depth, len(test.expected), impact.Nodes)
+			continue
+		}
+		for i, node := range impact.Nodes {
+			if distance, ok := test.expected[node.ID]; !ok || distance != node.Distance {
+				t.Errorf("%s %s: неожиданный узел %s на расстоянии %d", test.node, test.direction, node.ID, node.Distance)
+			}
+			if i > 0 && node.Distance < impact.Nodes[i-1].Distance {
+				t.Errorf("%s %s: узлы должны быть упорядочены по расстоянию", test.node, test.direction)
+			}
+		}
+		if len(impact.Edges) != test.edges {
+			t.Errorf("%s %s (depth=%d): ожидается %d ребер, получено: %d",
+				test.node, test.direction, test.depth, test.edges, len(impact.Edges))
+		}
+	}
+}
+
+func TestGetImpactErrors(t *testing.T) {
+	ds := newImpactTestService()
+
+	if _, err := ds.GetImpact("missing", models.ImpactUp, 0); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("Ожидается ErrNodeNotFound, получено: %v", err)
+	}
+	if _, err := ds.GetImpact("x.js#A", "sideways", 0); err == nil {
+		t.Errorf("Ожидается ошибка для неизвестного направления")
+	}
+	if _, err := ds.GetImpact("x.js#A", models.ImpactUp, -1); err == nil {
+		t.Errorf("Ожидается ошибка для отрицательной глубины")
+	}
+}
+
+func TestFindNodes(t *testing.T) {
+	ds := NewDependencyService(&FileService{ProjectPath: "/project"})
+	ds.Graph.Nodes = []models.Constant{
+		{ID: "y.js#LIMIT", Name: "LIMIT"},
+		{ID: "x.js#LIMIT", Name: "LIMIT"},
+		{ID: "x.js#A", Name: "A"},
+	}
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"x.js#A", []string{"x.js#A"}},
+		{"A", []string{"x.js#A"}},
+		{"LIMIT", []string{"x.js#LIMIT", "y.js#LIMIT"}},
+		{"y.js#LIMIT", []string{"y.js#LIMIT"}},
+		{"MISSING", nil},
+	}
+
+	for _, test := range tests {
+		var ids []string
+		for _, node := range ds.FindNodes(test.query) {
+			ids = append(ids, node.ID)
+		}
+		if !equalStrings(ids, test.expected) {
+			t.Errorf("FindNodes(%q): ожидается %v, получено %v", test.query, test.expected, ids)
+		}
+	}
+}
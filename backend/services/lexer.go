@@ -0,0 +1,657 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenType определяет тип лексемы JS/TS
+type TokenType int
+
+const (
+	TokenEOF        TokenType = iota // Конец файла
+	TokenIdentifier                  // Идентификатор или ключевое слово
+	TokenNumber                      // Числовой литерал
+	TokenString                      // Строковый литерал в кавычках
+	TokenTemplate                    // Часть шаблонной строки (`...`, `...${, }...${, }...`)
+	TokenRegex                       // Литерал регулярного выражения
+	TokenPunctuator                  // Оператор или знак пунктуации
+	TokenJSXText                     // Текст между JSX-тегами
+)
+
+// String возвращает имя типа лексемы для отладочного вывода
+func (t TokenType) String() string {
+	switch t {
+	case TokenEOF:
+		return "EOF"
+	case TokenIdentifier:
+		return "Identifier"
+	case TokenNumber:
+		return "Number"
+	case TokenString:
+		return "String"
+	case TokenTemplate:
+		return "Template"
+	case TokenRegex:
+		return "Regex"
+	case TokenPunctuator:
+		return "Punctuator"
+	case TokenJSXText:
+		return "JSXText"
+	default:
+		return "Unknown"
+	}
+}
+
+// Token представляет лексему исходного кода
+type Token struct {
+	Type          TokenType
+	Value         string // Исходный текст лексемы
+	Start         int    // Смещение начала лексемы в байтах
+	End           int    // Смещение конца лексемы в байтах (не включительно)
+	Line          int    // Номер строки (с 1)
+	Column        int    // Номер колонки в символах (с 1)
+	NewlineBefore bool   // Перед лексемой был перевод строки (в том числе внутри комментария)
+	JSX           bool   // Лексема прочитана внутри JSX-тега (имя тега, атрибуты)
+}
+
+// Is проверяет тип и значение лексемы
+func (t Token) Is(tokenType TokenType, value string) bool {
+	return t.Type == tokenType && t.Value == value
+}
+
+// lexFrame описывает контекст, в котором находится лексер
+type lexFrame int
+
+const (
+	frameBrace         lexFrame = iota // Обычный блок { ... }
+	frameTemplate                      // Подстановка ${ ... } внутри шаблонной строки
+	frameJSXExpr                       // Выражение { ... } внутри JSX
+	frameJSXTag                        // Открывающий JSX-тег <Name ...>
+	frameJSXClosingTag                 // Закрывающий JSX-тег </Name>
+	frameJSXChildren                   // Дочерние элементы JSX между тегами
+)
+
+// punctuators содержит многосимвольные операторы, отсортированные по убыванию длины
+var punctuators = []string{
+	">>>=",
+	"...", "===", "!==", "**=", "<<=", ">>=", ">>>", "&&=", "||=", "??=",
+	"=>", "==", "!=", "<=", ">=", "&&", "||", "??", "?.", "++", "--",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "**", "<<", ">>",
+}
+
+// regexPrecedingKeywords содержит ключевые слова, после которых начинается выражение
+var regexPrecedingKeywords = map[string]bool{
+	"return": true, "typeof": true, "instanceof": true, "in": true, "of": true,
+	"new": true, "delete": true, "void": true, "throw": true, "case": true,
+	"do": true, "else": true, "yield": true, "await": true, "default": true,
+}
+
+// IsJSXFile сообщает, может ли файл содержать JSX-разметку
+func IsJSXFile(filePath string) bool {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".jsx", ".tsx", ".js", ".mjs", ".cjs":
+		return true
+	default:
+		return false
+	}
+}
+
+// Lexer разбивает исходный код JS/TS/JSX/TSX на лексемы
+type Lexer struct {
+	src       string
+	pos       int
+	line      int
+	lineStart int
+	jsx       bool
+	newline   bool
+	stack     []lexFrame
+	tokens    []Token
+	err       error
+}
+
+// Tokenize разбивает исходный код на лексемы. Комментарии и пробелы пропускаются.
+// При лексической ошибке возвращается первая ошибка и все лексемы, которые
+// удалось прочитать с восстановлением после ошибки.
+func Tokenize(src string, jsx bool) ([]Token, error) {
+	l := &Lexer{
+		src:  src,
+		line: 1,
+		jsx:  jsx,
+	}
+
+	// Пропускаем BOM и hashbang в начале файла
+	if strings.HasPrefix(l.src, "\ufeff") {
+		l.pos = len("\ufeff")
+		l.lineStart = l.pos
+	}
+	if strings.HasPrefix(l.src[l.pos:], "#!") {
+		end := strings.IndexByte(l.src[l.pos:], '\n')
+		if end < 0 {
+			end = len(l.src) - l.pos
+		}
+		l.pos += end
+	}
+
+	for l.next() {
+	}
+
+	return l.tokens, l.err
+}
+
+// next читает очередную лексему, возвращает false в конце файла
+func (l *Lexer) next() bool {
+	switch l.frame() {
+	case frameJSXTag, frameJSXClosingTag:
+		return l.lexJSXTag()
+	case frameJSXChildren:
+		return l.lexJSXChildren()
+	default:
+		return l.lexCode()
+	}
+}
+
+// frame возвращает текущий контекст лексера
+func (l *Lexer) frame() lexFrame {
+	if len(l.stack) == 0 {
+		return frameBrace
+	}
+	return l.stack[len(l.stack)-1]
+}
+
+func (l *Lexer) push(frame lexFrame) {
+	l.stack = append(l.stack, frame)
+}
+
+func (l *Lexer) pop() lexFrame {
+	if len(l.stack) == 0 {
+		return frameBrace
+	}
+	frame := l.stack[len(l.stack)-1]
+	l.stack = l.stack[:len(l.stack)-1]
+	return frame
+}
+
+func (l *Lexer) replace(frame lexFrame) {
+	if len(l.stack) == 0 {
+		l.push(frame)
+		return
+	}
+	l.stack[len(l.stack)-1] = frame
+}
+
+// errorf запоминает первую лексическую ошибку
+func (l *Lexer) errorf(format string, args ...interface{}) {
+	if l.err == nil {
+		l.err = fmt.Errorf("%d:%d: %s", l.line, l.column(l.pos), fmt.Sprintf(format, args...))
+	}
+}
+
+// column вычисляет номер колонки для смещения на текущей строке
+func (l *Lexer) column(offset int) int {
+	return utf8.RuneCountInString(l.src[l.lineStart:offset]) + 1
+}
+
+// emit добавляет лексему от start до текущей позиции и обновляет счетчик строк
+func (l *Lexer) emit(tokenType TokenType, start int, inJSX bool) {
+	token := Token{
+		Type:          tokenType,
+		Value:         l.src[start:l.pos],
+		Start:         start,
+		End:           l.pos,
+		Line:          l.line,
+		Column:        l.column(start),
+		NewlineBefore: l.newline,
+		JSX:           inJSX,
+	}
+	l.tokens = append(l.tokens, token)
+	l.newline = false
+	l.countLines(start, l.pos)
+}
+
+// countLines учитывает переводы строк внутри прочитанного фрагмента
+func (l *Lexer) countLines(start, end int) {
+	for i := start; i < end; i++ {
+		if l.src[i] == '\n' {
+			l.line++
+			l.lineStart = i + 1
+		}
+	}
+}
+
+// skipSpace пропускает пробелы и, если разрешено, комментарии
+func (l *Lexer) skipSpace(comments bool) {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\n':
+			l.newline = true
+			l.line++
+			l.pos++
+			l.lineStart = l.pos
+		case c == ' ' || c == '\t' || c == '\r' || c == '\v' || c == '\f':
+			l.pos++
+		case comments && c == '/' && l.peek(1) == '/':
+			end := strings.IndexByte(l.src[l.pos:], '\n')
+			if end < 0 {
+				l.pos = len(l.src)
+			} else {
+				l.pos += end
+			}
+		case comments && c == '/' && l.peek(1) == '*':
+			start := l.pos
+			end := strings.Index(l.src[l.pos+2:], "*/")
+			if end < 0 {
+				l.errorf("unterminated block comment")
+				l.pos = len(l.src)
+			} else {
+				l.pos += end + 4
+			}
+			if strings.Contains(l.src[start:l.pos], "\n") {
+				l.newline = true
+			}
+			l.countLines(start, l.pos)
+		case c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			if r == '\u2028' || r == '\u2029' {
+				l.newline = true
+			} else if !unicode.IsSpace(r) && r != '\ufeff' {
+				return
+			}
+			l.pos += size
+		default:
+			return
+		}
+	}
+}
+
+// peek возвращает байт со смещением от текущей позиции или 0
+func (l *Lexer) peek(offset int) byte {
+	if l.pos+offset < len(l.src) {
+		return l.src[l.pos+offset]
+	}
+	return 0
+}
+
+// expressionAllowed определяет, может ли в текущей позиции начинаться выражение.
+// От этого зависит, считать ли "/" началом регулярного выражения, а "<" началом JSX.
+func (l *Lexer) expressionAllowed() bool {
+	if len(l.tokens) == 0 {
+		return true
+	}
+	last := l.tokens[len(l.tokens)-1]
+	switch last.Type {
+	case TokenPunctuator:
+		switch last.Value {
+		case ")", "]", "}", "++", "--":
+			return false
+		}
+		return true
+	case TokenIdentifier:
+		if !regexPrecedingKeywords[last.Value] {
+			return false
+		}
+		// Ключевое слово после точки является именем свойства
+		if len(l.tokens) > 1 {
+			before := l.tokens[len(l.tokens)-2]
+			if before.Is(TokenPunctuator, ".") || before.Is(TokenPunctuator, "?.") {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// lexCode читает лексему в обычном контексте JS/TS
+func (l *Lexer) lexCode() bool {
+	l.skipSpace(true)
+	if l.pos >= len(l.src) {
+		return false
+	}
+
+	start := l.pos
+	c := l.src[l.pos]
+
+	switch {
+	case c == '`':
+		l.pos++
+		l.lexTemplate(start)
+	case c == '"' || c == '\'':
+		l.lexString(c)
+		l.emit(TokenString, start, false)
+	case isDigit(c) || (c == '.' && isDigit(l.peek(1))):
+		l.lexNumber()
+		l.emit(TokenNumber, start, false)
+	case c == '#' && l.isIdentifierStartAt(l.pos+1):
+		l.pos++
+		l.lexIdentifier(false)
+		l.emit(TokenIdentifier, start, false)
+	case l.isIdentifierStartAt(l.pos):
+		l.lexIdentifier(false)
+		l.emit(TokenIdentifier, start, false)
+	case c == '/' && l.expressionAllowed() && l.lexRegex():
+		l.emit(TokenRegex, start, false)
+	case c == '<' && l.jsx && l.expressionAllowed() && l.looksLikeJSX():
+		l.pos++
+		l.push(frameJSXTag)
+		l.emit(TokenPunctuator, start, true)
+	case c == '{':
+		l.pos++
+		l.push(frameBrace)
+		l.emit(TokenPunctuator, start, false)
+	case c == '}':
+		switch l.pop() {
+		case frameTemplate:
+			l.pos++
+			l.lexTemplate(start)
+		default:
+			l.pos++
+			l.emit(TokenPunctuator, start, false)
+		}
+	default:
+		l.lexPunctuator()
+		l.emit(TokenPunctuator, start, false)
+	}
+
+	return true
+}
+
+// lexTemplate читает часть шаблонной строки до обратной кавычки или "${"
+func (l *Lexer) lexTemplate(start int) {
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case '\\':
+			l.pos += 2
+		case '`':
+			l.pos++
+			l.emit(TokenTemplate, start, false)
+			return
+		case '$':
+			if l.peek(1) == '{' {
+				l.pos += 2
+				l.push(frameTemplate)
+				l.emit(TokenTemplate, start, false)
+				return
+			}
+			l.pos++
+		default:
+			l.pos++
+		}
+	}
+
+	l.pos = len(l.src)
+	l.errorf("unterminated template literal")
+	l.emit(TokenTemplate, start, false)
+}
+
+// lexString читает строковый литерал, ограниченный кавычкой quote
+func (l *Lexer) lexString(quote byte) {
+	l.pos++
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case '\\':
+			l.pos += 2
+		case quote:
+			l.pos++
+			return
+		case '\n':
+			l.errorf("unterminated string literal")
+			return
+		default:
+			l.pos++
+		}
+	}
+
+	l.pos = len(l.src)
+	l.errorf("unterminated string literal")
+}
+
+// lexNumber читает числовой литерал (десятичный, шестнадцатеричный, BigInt и т.д.)
+func (l *Lexer) lexNumber() {
+	if l.src[l.pos] == '0' && strings.ContainsRune("xXoObB", rune(l.peek(1))) {
+		l.pos += 2
+		for l.pos < len(l.src) && (isHexDigit(l.src[l.pos]) || l.src[l.pos] == '_') {
+			l.pos++
+		}
+	} else {
+		l.skipDigits()
+		if l.peek(0) == '.' {
+			l.pos++
+			l.skipDigits()
+		}
+		if c := l.peek(0); c == 'e' || c == 'E' {
+			l.pos++
+			if c := l.peek(0); c == '+' || c == '-' {
+				l.pos++
+			}
+			l.skipDigits()
+		}
+	}
+
+	if l.peek(0) == 'n' {
+		l.pos++
+	}
+}
+
+func (l *Lexer) skipDigits() {
+	for l.pos < len(l.src) && (isDigit(l.src[l.pos]) || l.src[l.pos] == '_') {
+		l.pos++
+	}
+}
+
+// lexIdentifier читает идентификатор; в JSX допускается дефис (data-id, aria-label)
+func (l *Lexer) lexIdentifier(allowDash bool) {
+	for l.pos < len(l.src) {
+		if allowDash && l.src[l.pos] == '-' {
+			l.pos++
+			continue
+		}
+		if l.src[l.pos] == '\\' {
+			// Юникод-экранирование в идентификаторе: \uXXXX или \u{...}
+			l.pos++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		if !isIdentifierPart(r) {
+			return
+		}
+		l.pos += size
+	}
+}
+
+// lexRegex пытается прочитать литерал регулярного выражения.
+// Если литерал не закрыт до конца строки, считаем "/" оператором деления.
+func (l *Lexer) lexRegex() bool {
+	i := l.pos + 1
+	inClass := false
+	for i < len(l.src) {
+		c := l.src[i]
+		switch {
+		case c == '\n':
+			return false
+		case c == '\\':
+			i += 2
+			continue
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '/' && !inClass:
+			l.pos = i + 1
+			l.lexIdentifier(false) // флаги
+			return true
+		}
+		i++
+	}
+	return false
+}
+
+// lexPunctuator читает оператор, выбирая самое длинное совпадение
+func (l *Lexer) lexPunctuator() {
+	rest := l.src[l.pos:]
+	for _, p := range punctuators {
+		if strings.HasPrefix(rest, p) {
+			// "?." перед цифрой - это тернарный оператор с дробным числом
+			if p == "?." && len(rest) > 2 && isDigit(rest[2]) {
+				continue
+			}
+			l.pos += len(p)
+			return
+		}
+	}
+
+	_, size := utf8.DecodeRuneInString(rest)
+	l.pos += size
+}
+
+// looksLikeJSX проверяет, что "<" начинает JSX-элемент, а не обобщение TSX вида <T,>
+func (l *Lexer) looksLikeJSX() bool {
+	i := l.pos + 1
+	if i < len(l.src) && l.src[i] == '>' {
+		return true // Фрагмент <>
+	}
+	if !l.isIdentifierStartAt(i) {
+		return false
+	}
+
+	for i < len(l.src) {
+		r, size := utf8.DecodeRuneInString(l.src[i:])
+		if !isIdentifierPart(r) && r != '-' && r != '.' && r != ':' {
+			break
+		}
+		i += size
+	}
+	for i < len(l.src) && (l.src[i] == ' ' || l.src[i] == '\t') {
+		i++
+	}
+	if i < len(l.src) && l.src[i] == ',' {
+		return false
+	}
+	return !strings.HasPrefix(l.src[i:], "extends ")
+}
+
+// lexJSXTag читает лексему внутри открывающего или закрывающего JSX-тега
+func (l *Lexer) lexJSXTag() bool {
+	l.skipSpace(true)
+	if l.pos >= len(l.src) {
+		l.errorf("unterminated JSX tag")
+		return false
+	}
+
+	start := l.pos
+	c := l.src[l.pos]
+
+	switch {
+	case c == '>':
+		l.pos++
+		if l.frame() == frameJSXClosingTag {
+			l.pop()
+		} else {
+			l.replace(frameJSXChildren)
+		}
+	case c == '/' && l.peek(1) == '>':
+		l.pos += 2
+		l.pop()
+	case c == '{':
+		l.pos++
+		l.push(frameJSXExpr)
+		l.emit(TokenPunctuator, start, false)
+		return true
+	case c == '"' || c == '\'':
+		end := strings.IndexByte(l.src[l.pos+1:], c)
+		if end < 0 {
+			l.errorf("unterminated JSX attribute string")
+			l.pos = len(l.src)
+		} else {
+			l.pos += end + 2
+		}
+		l.emit(TokenString, start, true)
+		return true
+	case l.isIdentifierStartAt(l.pos):
+		l.lexIdentifier(true)
+		l.emit(TokenIdentifier, start, true)
+		return true
+	default:
+		_, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		l.pos += size
+		if !strings.ContainsRune("=:./", rune(c)) {
+			l.errorf("unexpected character %q in JSX tag", l.src[start:l.pos])
+		}
+	}
+
+	l.emit(TokenPunctuator, start, true)
+	return true
+}
+
+// lexJSXChildren читает текст или начало вложенного элемента между JSX-тегами
+func (l *Lexer) lexJSXChildren() bool {
+	if l.pos >= len(l.src) {
+		l.errorf("unterminated JSX element")
+		return false
+	}
+
+	start := l.pos
+	switch l.src[l.pos] {
+	case '{':
+		l.pos++
+		l.push(frameJSXExpr)
+		l.emit(TokenPunctuator, start, false)
+	case '<':
+		if l.peek(1) == '/' {
+			l.pos += 2
+			l.replace(frameJSXClosingTag)
+		} else {
+			l.pos++
+			l.push(frameJSXTag)
+		}
+		l.emit(TokenPunctuator, start, true)
+	default:
+		end := strings.IndexAny(l.src[l.pos:], "{<")
+		if end < 0 {
+			end = len(l.src) - l.pos
+		}
+		l.pos += end
+		if strings.TrimSpace(l.src[start:l.pos]) != "" {
+			l.emit(TokenJSXText, start, true)
+		} else {
+			l.countLines(start, l.pos)
+		}
+	}
+
+	return true
+}
+
+// isIdentifierStartAt проверяет, может ли с позиции начинаться идентификатор
+func (l *Lexer) isIdentifierStartAt(pos int) bool {
+	if pos >= len(l.src) {
+		return false
+	}
+	if l.src[pos] == '\\' {
+		return pos+1 < len(l.src) && l.src[pos+1] == 'u'
+	}
+	r, _ := utf8.DecodeRuneInString(l.src[pos:])
+	return isIdentifierStart(r)
+}
+
+func isIdentifierStart(r rune) bool {
+	return r == '$' || r == '_' || unicode.IsLetter(r) || unicode.Is(unicode.Nl, r)
+}
+
+func isIdentifierPart(r rune) bool {
+	return isIdentifierStart(r) || unicode.IsDigit(r) ||
+		unicode.In(r, unicode.Mn, unicode.Mc, unicode.Pc) ||
+		r == '\u200c' || r == '\u200d'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
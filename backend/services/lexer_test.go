@@ -0,0 +1,232 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// tokenValues возвращает значения лексем заданного типа
+func tokenValues(tokens []Token, tokenType TokenType) []string {
+	var values []string
+	for _, token := range tokens {
+		if token.Type == tokenType {
+			values = append(values, token.Value)
+		}
+	}
+	return values
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		jsx      bool
+		typ      TokenType
+		expected []string
+	}{
+		{
+			name:     "Фигурные скобки внутри строк",
+			src:      `const A = "{"; const B = '}}';`,
+			typ:      TokenString,
+			expected: []string{`"{"`, `'}}'`},
+		},
+		{
+			name:     "Экранированные кавычки",
+			src:      `const A = "say \"hi\"";`,
+			typ:      TokenString,
+			expected: []string{`"say \"hi\""`},
+		},
+		{
+			name:     "Шаблонная строка с вложенными подстановками",
+			src:      "const A = `a ${B + `x ${C} }`} b`;",
+			typ:      TokenTemplate,
+			expected: []string{"`a ${", "`x ${", "} }`", "} b`"},
+		},
+		{
+			name:     "Идентификаторы внутри подстановок",
+			src:      "const A = `${ {k: B}.k }`;",
+			typ:      TokenIdentifier,
+			expected: []string{"const", "A", "k", "B", "k"},
+		},
+		{
+			name:     "Регулярное выражение со скобками",
+			src:      `const RE = /[{}\/]+/g; const D = a / b / c;`,
+			typ:      TokenRegex,
+			expected: []string{`/[{}\/]+/g`},
+		},
+		{
+			name:     "Регулярное выражение после ключевого слова",
+			src:      `function f() { return /}/.test(x); }`,
+			typ:      TokenRegex,
+			expected: []string{`/}/`},
+		},
+		{
+			name:     "Комментарии пропускаются",
+			src:      "/* const X = { */ const A = 1; // const B = 2\nconst C = 3;",
+			typ:      TokenIdentifier,
+			expected: []string{"const", "A", "const", "C"},
+		},
+		{
+			name:     "Числа",
+			src:      `const A = 0xFF + 1_000 + .5e-3 + 10n;`,
+			typ:      TokenNumber,
+			expected: []string{"0xFF", "1_000", ".5e-3", "10n"},
+		},
+		{
+			name:     "Текст JSX с апострофом",
+			src:      `const el = <div className="a">don't {value}</div>;`,
+			jsx:      true,
+			typ:      TokenJSXText,
+			expected: []string{"don't "},
+		},
+		{
+			name:     "Вложенные JSX-элементы и фрагменты",
+			src:      `const el = <><Item id={ID} />{list.map(x => <li key={x}>{x}</li>)}</>; const B = 1;`,
+			jsx:      true,
+			typ:      TokenIdentifier,
+			expected: []string{"const", "el", "Item", "id", "ID", "list", "map", "x", "li", "key", "x", "x", "li", "const", "B"},
+		},
+		{
+			name:     "Обобщенная стрелочная функция TSX не считается JSX",
+			src:      `const f = <T,>(x: T) => x;`,
+			jsx:      true,
+			typ:      TokenPunctuator,
+			expected: []string{"=", "<", ",", ">", "(", ":", ")", "=>", ";"},
+		},
+		{
+			name:     "Сравнение в TypeScript не считается JSX",
+			src:      `const A = B < C;`,
+			typ:      TokenPunctuator,
+			expected: []string{"=", "<", ";"},
+		},
+	}
+
+	for _, test := range tests {
+		tokens, err := Tokenize(test.src, test.jsx)
+		if err != nil {
+			t.Errorf("%s: неожиданная ошибка: %v", test.name, err)
+		}
+
+		actual := tokenValues(tokens, test.typ)
+		if !equalStrings(actual, test.expected) {
+			t.Errorf("%s: ожидались лексемы %q, получено: %q", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestTokenizePositions(t *testing.T) {
+	src := "const A = 1;\n/* многострочный\nкомментарий */ const Б = `x\ny`;\nconst C = 2;"
+
+	tokens, err := Tokenize(src, false)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	positions := map[string][2]int{
+		"A": {1, 7},
+		"Б": {3, 22},
+		"C": {5, 7},
+	}
+
+	for i, token := range tokens {
+		expected, ok := positions[token.Value]
+		if !ok {
+			continue
+		}
+		if token.Line != expected[0] || token.Column != expected[1] {
+			t.Errorf("Для %s ожидается позиция %d:%d, получено: %d:%d",
+				token.Value, expected[0], expected[1], token.Line, token.Column)
+		}
+		// Перевод строки внутри комментария или шаблона тоже разделяет инструкции
+		if token.Value != "A" && !tokens[i-1].NewlineBefore {
+			t.Errorf("Для объявления %s ожидается признак перевода строки перед const", token.Value)
+		}
+	}
+}
+
+func TestTokenizeErrors(t *testing.T) {
+	sources := []string{
+		`const A = "unterminated`,
+		"const A = `unterminated",
+		`/* unterminated`,
+	}
+
+	for _, src := range sources {
+		tokens, err := Tokenize(src, false)
+		if err == nil {
+			t.Errorf("Для %q ожидается лексическая ошибка", src)
+		}
+		if len(tokens) == 0 && src[0] != '/' {
+			t.Errorf("Для %q ожидаются лексемы, прочитанные до ошибки", src)
+		}
+	}
+}
+
+func TestFindConstantsIgnoresFormatting(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "lexer-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Скобки в строках, шаблонах, регулярных выражениях и комментариях
+	// не должны нарушать определение верхнего уровня файла
+	testFile := filepath.Join(tempDir, "tricky.js")
+	testContent := "const OPEN = \"{\";\n" +
+		"const TEMPLATE = `${OPEN} { ${`}`}`;\n" +
+		"const RE = /[{]+/;\n" +
+		"/* { */\n" +
+		"const AFTER = OPEN + RE.source; const SAME_LINE = 1;\n" +
+		"function f() {\n" +
+		"  const INNER = 1;\n" +
+		"}\n"
+
+	if err := os.WriteFile(testFile, []byte(testContent), 0644); err != nil {
+		t.Fatalf("Не удалось создать тестовый файл: %v", err)
+	}
+
+	dependencyService := NewDependencyService(NewFileService(tempDir, nil))
+	dependencyService.FindConstants(testFile)
+	dependencyService.FindDependencies(testFile)
+
+	// Функция f - символ верхнего уровня, а ее локальная константа INNER - нет
+	expected := map[string]bool{"OPEN": true, "TEMPLATE": true, "RE": true, "AFTER": true, "SAME_LINE": true, "f": true}
+	if len(dependencyService.Graph.Nodes) != len(expected) {
+		t.Errorf("Ожидается %d символов, получено: %d", len(expected), len(dependencyService.Graph.Nodes))
+	}
+	for _, node := range dependencyService.Graph.Nodes {
+		if !expected[node.Name] {
+			t.Errorf("Неожиданная константа %s", node.Name)
+		}
+	}
+
+	edges := map[string]bool{}
+	for _, edge := range dependencyService.Graph.Edges {
+		edges[edge.Source+"->"+edge.Target] = true
+	}
+	for _, edge := range []string{
+		"tricky.js#TEMPLATE->tricky.js#OPEN",
+		"tricky.js#AFTER->tricky.js#OPEN",
+		"tricky.js#AFTER->tricky.js#RE",
+	} {
+		if !edges[edge] {
+			t.Errorf("Ожидаемая зависимость не найдена: %s", edge)
+		}
+	}
+	if len(edges) != 3 {
+		t.Errorf("Ожидается 3 зависимости, получено: %d", len(edges))
+	}
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// GetModuleGraph возвращает граф импортов между файлами: по узлу на каждый
+// проанализированный файл и по ребру на каждую инструкцию import, export ... from
+// или вызов import()/require(), разрешенную в файл проекта. Импорты файлов, которые
+// не анализируются ('./styles.css', require('./data.json')), ведут к узлам с признаком
+// Unanalyzed. Вызовы import() с вычисляемым модулем возвращаются как диагностики
+// models.DiagnosticUnresolvableDynamic.
+//
+// Граф строится по результатам последнего анализа, а не по текущему содержимому
+// проекта: он обновляется только при повторном анализе в режиме -watch.
+//
+// Если в конфигурации заданы точки входа, они отмечаются признаком Entry,
+// а файлы, которые не импортируются из них ни напрямую, ни транзитивно
+// (по ребрам любых видов), - признаком Unreachable.
+//
+// Непустой kinds оставляет только ребра указанных видов (models.ImportKinds)
+// и файлы, связанные этими ребрами: например, ImportDynamic дает границы
+// разделения кода.
+func (ds *DependencyService) GetModuleGraph(kinds []string) (models.ModuleGraph, error) {
+	graph := models.ModuleGraph{
+		Nodes:       []models.ModuleNode{},
+		Edges:       []models.ModuleEdge{},
+		Diagnostics: []models.ModuleDiagnostic{},
+	}
+
+	allowed := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		if !isImportKind(kind) {
+			return graph, fmt.Errorf("unknown import kind %q", kind)
+		}
+		allowed[kind] = true
+	}
+
+	// Файлы берутся из результатов анализа, чтобы не обходить дерево проекта
+	// при каждом запросе
+	files, links := ds.moduleLinks()
+	reachable := ds.reachableFiles(links)
+	unanalyzed, unanalyzedLinks := ds.unanalyzedLinks(files)
+
+	linked := make(map[string]bool)
+	for _, link := range append(links, unanalyzedLinks...) {
+		if len(allowed) > 0 && !allowed[link.Kind] {
+			continue
+		}
+		linked[link.From], linked[link.To] = true, true
+
+		symbols := link.Symbols
+		if symbols == nil {
+			symbols = []string{}
+		}
+		graph.Edges = append(graph.Edges, models.ModuleEdge{
+			Source:   ds.relativePath(link.From),
+			Target:   ds.relativePath(link.To),
+			Kind:     link.Kind,
+			ReExport: link.ReExport,
+			Lazy:     link.Lazy,
+			Symbols:  symbols,
+			Line:     link.Line,
+		})
+	}
+
+	for _, file := range files {
+		if len(allowed) > 0 && !linked[file] {
+			continue
+		}
+		graph.Nodes = append(graph.Nodes, models.ModuleNode{
+			ID:          ds.relativePath(file),
+			Name:        filepath.Base(file),
+			Entry:       ds.isEntryPoint(file),
+			Unreachable: reachable != nil && !reachable[file],
+		})
+	}
+	for _, file := range unanalyzed {
+		if len(allowed) > 0 && !linked[file] {
+			continue
+		}
+		graph.Nodes = append(graph.Nodes, models.ModuleNode{
+			ID:         ds.relativePath(file),
+			Name:       filepath.Base(file),
+			Unanalyzed: true,
+		})
+	}
+
+	// Диагностики относятся к динамическим импортам и не зависят от наличия ребер
+	if len(allowed) > 0 && !allowed[models.ImportDynamic] {
+		return graph, nil
+	}
+	for _, file := range files {
+		for _, expr := range ds.moduleInfo(file).dynamicExpressions() {
+			graph.Diagnostics = append(graph.Diagnostics, models.ModuleDiagnostic{
+				Kind:       models.DiagnosticUnresolvableDynamic,
+				Expression: expr.Expression,
+				Location:   models.NewLocation(ds.relativePath(file), expr.Start, expr.End),
+			})
+		}
+	}
+
+	return graph, nil
+}
+
+// unanalyzedLinks возвращает отсортированный список файлов проекта, которые
+// импортируются из files, но сами не анализируются, и ссылки на них
+func (ds *DependencyService) unanalyzedLinks(files []string) ([]string, []moduleLink) {
+	var targets []string
+	var links []moduleLink
+	seen := make(map[string]bool)
+
+	for _, file := range files {
+		for _, imp := range ds.moduleInfo(file).imports() {
+			target, ok := ds.Resolver.Resolve(file, imp.Specifier)
+			if !ok {
+				target, ok = ds.Resolver.ResolveAsset(file, imp.Specifier)
+			}
+			if !ok || ds.moduleInfo(target) != nil || !isWithin(ds.FileService.ProjectPath, target) {
+				continue
+			}
+
+			link := imp.link()
+			link.From, link.To = file, target
+			links = append(links, link)
+			if !seen[target] {
+				seen[target] = true
+				targets = append(targets, target)
+			}
+		}
+	}
+
+	sort.Strings(targets)
+	return targets, links
+}
+
+// reachableFiles возвращает файлы, достижимые из точек входа по связям links.
+// Без точек входа возвращает nil.
+func (ds *DependencyService) reachableFiles(links []moduleLink) map[string]bool {
+	if len(ds.EntryPoints) == 0 {
+		return nil
+	}
+
+	imports := make(map[string][]string)
+	for _, link := range links {
+		imports[link.From] = append(imports[link.From], link.To)
+	}
+
+	reachable := make(map[string]bool)
+	queue := append([]string{}, ds.EntryPoints...)
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
+		if reachable[file] {
+			continue
+		}
+		reachable[file] = true
+		queue = append(queue, imports[file]...)
+	}
+	return reachable
+}
+
+// isEntryPoint проверяет, указан ли файл в точках входа
+func (ds *DependencyService) isEntryPoint(file string) bool {
+	for _, entry := range ds.EntryPoints {
+		if entry == file {
+			return true
+		}
+	}
+	return false
+}
+
+// isImportKind проверяет, что kind - один из видов импорта models.ImportKinds
+func isImportKind(kind string) bool {
+	for _, known := range models.ImportKinds {
+		if kind == known {
+			return true
+		}
+	}
+	return false
+}
+
+// imports возвращает импорты модуля
+func (m *moduleInfo) imports() []moduleImport {
+	if m == nil {
+		return nil
+	}
+	return m.Imports
+}
+
+// dynamicExpressions возвращает вызовы import() модуля с вычисляемым аргументом
+func (m *moduleInfo) dynamicExpressions() []dynamicExpression {
+	if m == nil {
+		return nil
+	}
+	return m.Dynamic
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/avor0n/dependency-graph-visualizer/config"
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+func TestGetModuleGraph(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "module-graph-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"main.ts": `import './styles';
+import type { Props } from './types';
+import cfg, { API_URL } from './config';
+export { helper, type Helper } from './utils';
+const Page = lazy(() => import('./page'));
+const legacy = require('./legacy');
+import React from 'react';
+const admin = import(` + "`./admin`" + `);
+const locale = import(` + "`./locales/${lang}`" + `, { with: { type: 'json' } });`,
+		"styles.ts": `export const THEME = 'dark';`,
+		"types.ts":  `export type Props = {};`,
+		"config.ts": `export const API_URL = '/api'; export default API_URL;`,
+		"utils.ts":  `export const helper = 1;`,
+		"page.tsx":  `export const TITLE = 'Page';`,
+		"legacy.js": `module.exports = {};`,
+		"admin.ts":  `export const ROLE = 'admin';`,
+		"orphan.ts": `export const ALONE = true;`,
+	})
+
+	ds := NewDependencyService(NewFileService(tempDir, nil))
+	ds.BuildDependencyGraph()
+
+	graph, err := ds.GetModuleGraph(nil)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	if len(graph.Nodes) != 9 {
+		t.Errorf("Ожидается узел для каждого из 9 файлов, получено: %+v", graph.Nodes)
+	}
+	for _, node := range graph.Nodes {
+		if node.ID == "orphan.ts" && node.Name != "orphan.ts" {
+			t.Errorf("Неверный узел: %+v", node)
+		}
+	}
+
+	// Неразрешенный импорт 'react' не создает ребра
+	expected := []models.ModuleEdge{
+		{Source: "main.ts", Target: "styles.ts", Kind: models.ImportSideEffect, Symbols: []string{}, Line: 1},
+		{Source: "main.ts", Target: "types.ts", Kind: models.ImportTypeOnly, Symbols: []string{"Props"}, Line: 2},
+		{Source: "main.ts", Target: "config.ts", Kind: models.ImportStatic, Symbols: []string{"default", "API_URL"}, Line: 3},
+		{Source: "main.ts", Target: "page.tsx", Kind: models.ImportDynamic, Lazy: true, Symbols: []string{}, Line: 5},
+		{Source: "main.ts", Target: "legacy.js", Kind: models.ImportStatic, Symbols: []string{"*"}, Line: 6},
+		{Source: "main.ts", Target: "admin.ts", Kind: models.ImportDynamic, Symbols: []string{}, Line: 8},
+		{Source: "main.ts", Target: "utils.ts", Kind: models.ImportStatic, ReExport: true, Symbols: []string{"helper", "Helper"}, Line: 4},
+	}
+	if !reflect.DeepEqual(graph.Edges, expected) {
+		t.Errorf("Неверные ребра графа модулей:\nожидается %+v\nполучено   %+v", expected, graph.Edges)
+	}
+
+	// Шаблонная строка с подстановкой не разрешается в файл
+	diagnostics := []models.ModuleDiagnostic{{
+		Kind:       models.DiagnosticUnresolvableDynamic,
+		Expression: "`./locales/${lang}`",
+		Location:   models.Location{File: "main.ts", Line: 9, Column: 23, EndLine: 9, EndColumn: 42},
+	}}
+	if !reflect.DeepEqual(graph.Diagnostics, diagnostics) {
+		t.Errorf("Неверные диагностики: %+v", graph.Diagnostics)
+	}
+
+	// Фильтр по виду оставляет только границы разделения кода
+	graph, err = ds.GetModuleGraph([]string{models.ImportDynamic})
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if len(graph.Edges) != 2 || len(graph.Nodes) != 3 || len(graph.Diagnostics) != 1 {
+		t.Errorf("Неверный граф динамических импортов: %+v", graph)
+	}
+
+	graph, _ = ds.GetModuleGraph([]string{models.ImportTypeOnly})
+	if len(graph.Edges) != 1 || len(graph.Nodes) != 2 || len(graph.Diagnostics) != 0 {
+		t.Errorf("Неверный граф импортов типов: %+v", graph)
+	}
+
+	if _, err := ds.GetModuleGraph([]string{"lazy"}); err == nil {
+		t.Errorf("Ожидается ошибка для неизвестного вида импорта")
+	}
+
+	// Граф строится по результатам анализа: файл, появившийся после него, не виден до повторного анализа
+	writeTestFiles(t, tempDir, map[string]string{"late.ts": `export const LATE = 1;`})
+	graph, _ = ds.GetModuleGraph(nil)
+	if len(graph.Nodes) != 9 {
+		t.Errorf("Ожидается 9 проанализированных файлов, получено: %+v", graph.Nodes)
+	}
+}
+
+func TestModuleGraphEntryPoints(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "module-graph-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		".depgraph.json":     `{ "entryPoints": ["src/main.ts"] }`,
+		"src/main.ts":        "import { App } from './app';\nconst Page = () => import('./page');",
+		"src/app.ts":         "import type { Props } from './types';\nexport const App = 1;",
+		"src/types.ts":       "export type Props = {};",
+		"src/page.ts":        "export const PAGE = 1;",
+		"src/legacy.ts":      "import { App } from './app';",
+		"src/legacy.test.ts": "import './legacy';",
+	})
+
+	cfg, err := config.Load(tempDir)
+	if err != nil {
+		t.Fatalf("Не удалось загрузить конфигурацию: %v", err)
+	}
+	fileService := NewFileService(tempDir, nil)
+	fileService.Configure(cfg)
+	ds := NewDependencyService(fileService)
+	ds.Configure(cfg)
+	ds.BuildDependencyGraph()
+
+	graph, err := ds.GetModuleGraph(nil)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	var entries, unreachable []string
+	for _, node := range graph.Nodes {
+		if node.Entry {
+			entries = append(entries, node.ID)
+		}
+		if node.Unreachable {
+			unreachable = append(unreachable, node.ID)
+		}
+	}
+	// Файлы, импортированные динамически и только ради типов, тоже достижимы
+	expectedUnreachable := []string{"src/legacy.test.ts", "src/legacy.ts"}
+	if !reflect.DeepEqual(entries, []string{"src/main.ts"}) {
+		t.Errorf("Ожидается точка входа src/main.ts, получено: %v", entries)
+	}
+	if !reflect.DeepEqual(unreachable, expectedUnreachable) {
+		t.Errorf("Ожидаются недостижимые файлы %v, получено: %v", expectedUnreachable, unreachable)
+	}
+
+	if stats := ds.Stats(); !reflect.DeepEqual(stats.UnreachableFiles, expectedUnreachable) {
+		t.Errorf("Статистика: ожидаются недостижимые файлы %v, получено: %v", expectedUnreachable, stats.UnreachableFiles)
+	}
+}
+
+func TestModuleGraphUnanalyzedTargets(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "module-graph-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"tsconfig.json": `{ "compilerOptions": { "paths": { "@/*": ["src/*"] } } }`,
+		"src/main.ts": `import './styles.css';
+import '@/theme.css';
+import { App } from './app';
+const data = require('./data.json');
+import './missing.css';`,
+		"src/app.ts":      "export const App = 1;",
+		"src/styles.css":  "body {}",
+		"src/theme.css":   ":root {}",
+		"src/data.json":   "{}",
+		"src/unused.json": "{}",
+	})
+
+	ds := NewDependencyService(NewFileService(tempDir, nil))
+	ds.BuildDependencyGraph()
+
+	graph, err := ds.GetModuleGraph(nil)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	var unanalyzed []string
+	for _, node := range graph.Nodes {
+		if node.Unanalyzed {
+			unanalyzed = append(unanalyzed, node.ID)
+		}
+	}
+	expectedNodes := []string{"src/data.json", "src/styles.css", "src/theme.css"}
+	if !reflect.DeepEqual(unanalyzed, expectedNodes) {
+		t.Errorf("Ожидаются неанализируемые файлы %v, получено: %v", expectedNodes, unanalyzed)
+	}
+
+	expected := []models.ModuleEdge{
+		{Source: "src/main.ts", Target: "src/app.ts", Kind: models.ImportStatic, Symbols: []string{"App"}, Line: 3},
+		{Source: "src/main.ts", Target: "src/styles.css", Kind: models.ImportSideEffect, Symbols: []string{}, Line: 1},
+		{Source: "src/main.ts", Target: "src/theme.css", Kind: models.ImportSideEffect, Symbols: []string{}, Line: 2},
+		{Source: "src/main.ts", Target: "src/data.json", Kind: models.ImportStatic, Symbols: []string{"*"}, Line: 4},
+	}
+	if !reflect.DeepEqual(graph.Edges, expected) {
+		t.Errorf("Неверные ребра:\nожидается %+v\nполучено   %+v", expected, graph.Edges)
+	}
+
+	// Фильтр по виду применяется и к неанализируемым файлам
+	graph, _ = ds.GetModuleGraph([]string{models.ImportSideEffect})
+	if len(graph.Edges) != 2 || len(graph.Nodes) != 3 {
+		t.Errorf("Неверный граф импортов без имен: %+v", graph)
+	}
+}
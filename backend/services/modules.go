@@ -0,0 +1,574 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// importBinding описывает локальное имя, связанное импортом
+type importBinding struct {
+	Local    string // Локальное имя в импортирующем модуле
+	Imported string // Имя экспорта: "default", "*" для пространства имен или имя
+	TypeOnly bool   // Импорт только типа (import { type T })
+}
+
+// moduleImport описывает одну инструкцию import
+type moduleImport struct {
+	Specifier      string          // Строка модуля из "from"
+	Line           int             // Номер строки инструкции
+	SpecifierStart models.Position // Начало строки модуля в исходном тексте
+	SpecifierEnd   models.Position // Конец строки модуля (не включительно)
+	TypeOnly       bool            // import type ...
+	Dynamic        bool            // import('./x')
+	Require        bool            // require('./x')
+	Lazy           bool            // import() внутри lazy(() => import('./x'))
+	Bindings       []importBinding // Пусто для импорта ради побочных эффектов
+}
+
+// moduleExport описывает экспортируемое имя модуля
+type moduleExport struct {
+	Exported       string          // Внешнее имя: "default", имя или "*" для export * from
+	Local          string          // Локальное имя; пусто для анонимного export default
+	Specifier      string          // Модуль-источник для реэкспорта
+	Imported       string          // Имя в модуле-источнике для реэкспорта ("*" для пространства имен)
+	TypeOnly       bool            // Реэкспорт только типа (export type { T } from)
+	Line           int             // Номер строки инструкции
+	SpecifierStart models.Position // Начало строки модуля-источника в исходном тексте
+	SpecifierEnd   models.Position // Конец строки модуля-источника (не включительно)
+}
+
+// dynamicExpression описывает вызов import(), модуль которого вычисляется во время выполнения
+type dynamicExpression struct {
+	Expression string          // Текст аргумента
+	Start      models.Position // Начало аргумента в исходном тексте
+	End        models.Position // Конец аргумента (не включительно)
+}
+
+// moduleInfo содержит импорты и экспорты модуля
+type moduleInfo struct {
+	Imports []moduleImport
+	Exports []moduleExport
+	Dynamic []dynamicExpression // Вызовы import() без строкового аргумента
+}
+
+// kind возвращает вид импорта: импорт, все имена которого являются типами,
+// удаляется при компиляции, а импорт без имен нужен ради побочных эффектов
+func (imp moduleImport) kind() string {
+	switch {
+	case imp.Dynamic:
+		return models.ImportDynamic
+	case imp.TypeOnly:
+		return models.ImportTypeOnly
+	case imp.Require:
+		return models.ImportStatic
+	case len(imp.Bindings) == 0:
+		return models.ImportSideEffect
+	}
+	for _, binding := range imp.Bindings {
+		if !binding.TypeOnly {
+			return models.ImportStatic
+		}
+	}
+	return models.ImportTypeOnly
+}
+
+// importedName возвращает имя экспорта, значение которого получает привязка.
+// Пространство имен ES-модуля не является значением одного экспорта, а результат
+// require() - это module.exports, который разбирается как экспорт по умолчанию.
+func (imp moduleImport) importedName(b importBinding) (string, bool) {
+	if b.Imported != "*" {
+		return b.Imported, true
+	}
+	return "default", imp.Require
+}
+
+// binding ищет импорт по локальному имени
+func (m *moduleInfo) binding(local string) (moduleImport, importBinding, bool) {
+	if m == nil {
+		return moduleImport{}, importBinding{}, false
+	}
+	for _, imp := range m.Imports {
+		for _, b := range imp.Bindings {
+			if b.Local == local {
+				return imp, b, true
+			}
+		}
+	}
+	return moduleImport{}, importBinding{}, false
+}
+
+// parseModule находит инструкции import и export верхнего уровня в потоке лексем
+func parseModule(tokens []Token) *moduleInfo {
+	module := &moduleInfo{}
+	reExported := make(map[int]bool)
+
+	depth := 0
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+
+		if token.Type == TokenPunctuator {
+			switch token.Value {
+			case "{", "(", "[":
+				depth++
+			case "}", ")", "]":
+				if depth > 0 {
+					depth--
+				}
+			}
+			continue
+		}
+
+		if token.Type != TokenIdentifier {
+			continue
+		}
+
+		// Вызовы import('./x') и require('./x') встречаются на любой глубине.
+		// require(), уже учтенный как реэкспорт CommonJS, повторно не добавляется.
+		if imp, ok := parseImportCall(tokens, i); ok {
+			if !reExported[imp.SpecifierStart.Offset] {
+				module.Imports = append(module.Imports, imp)
+			}
+			if !tokens[i+3].Is(TokenPunctuator, ")") {
+				// Скобка вызова остается открытой до конца параметров импорта
+				depth++
+			}
+			i += 3
+			continue
+		}
+		if expr, ok := parseDynamicExpression(tokens, i); ok {
+			module.Dynamic = append(module.Dynamic, expr)
+		}
+
+		if depth != 0 || !isStatementStart(tokens, i) {
+			continue
+		}
+
+		switch token.Value {
+		case "import":
+			// import(...) и import.meta являются выражениями
+			if i+1 < len(tokens) && (tokens[i+1].Is(TokenPunctuator, "(") || tokens[i+1].Is(TokenPunctuator, ".")) {
+				continue
+			}
+			if imp, next, ok := parseImport(tokens, i); ok {
+				module.Imports = append(module.Imports, imp)
+				i = next - 1
+			}
+		case "export":
+			exports, next := parseExport(tokens, i)
+			module.Exports = append(module.Exports, exports...)
+			if next > i+1 {
+				i = next - 1
+			}
+		case "const", "let", "var":
+			// const x = require('./x')
+			if imp, next, ok := parseRequireDeclaration(tokens, i); ok {
+				module.Imports = append(module.Imports, imp)
+				i = next - 1
+			}
+		case "module", "exports":
+			// module.exports = ... / exports.NAME = ...
+			if exports, next, ok := parseCommonJSExport(tokens, i); ok {
+				for _, exp := range exports {
+					if exp.Specifier != "" {
+						reExported[exp.SpecifierStart.Offset] = true
+					}
+				}
+				module.Exports = append(module.Exports, exports...)
+				i = next - 1
+			}
+		}
+	}
+
+	return module
+}
+
+// parseImport разбирает инструкцию import, начинающуюся с индекса i
+func parseImport(tokens []Token, i int) (moduleImport, int, bool) {
+	imp := moduleImport{Line: tokens[i].Line}
+	j := i + 1
+
+	// import type { T } from / import type T from / import type * as ns from
+	if j+1 < len(tokens) && tokens[j].Is(TokenIdentifier, "type") &&
+		!tokens[j+1].Is(TokenIdentifier, "from") && !tokens[j+1].Is(TokenPunctuator, ",") &&
+		!tokens[j+1].Is(TokenPunctuator, "=") {
+		imp.TypeOnly = true
+		j++
+	}
+
+	// Импорт ради побочных эффектов: import './styles.css'
+	if j < len(tokens) && tokens[j].Type == TokenString {
+		imp.setSpecifier(tokens[j])
+		return imp, skipStatement(tokens, j+1), true
+	}
+
+	// Импорт по умолчанию
+	if j < len(tokens) && tokens[j].Type == TokenIdentifier && !tokens[j].Is(TokenIdentifier, "from") {
+		// import x = require('y') (TypeScript) импортирует module.exports целиком
+		if j+1 < len(tokens) && tokens[j+1].Is(TokenPunctuator, "=") {
+			specifier, imported, end, ok := parseRequireCall(tokens, j+2)
+			if !ok || imported != "*" {
+				return imp, skipStatement(tokens, j), false
+			}
+			imp.Require = true
+			imp.setSpecifier(specifier)
+			imp.Bindings = []importBinding{{Local: tokens[j].Value, Imported: "*", TypeOnly: imp.TypeOnly}}
+			return imp, skipStatement(tokens, end), true
+		}
+		imp.Bindings = append(imp.Bindings, importBinding{Local: tokens[j].Value, Imported: "default", TypeOnly: imp.TypeOnly})
+		j++
+		if j < len(tokens) && tokens[j].Is(TokenPunctuator, ",") {
+			j++
+		}
+	}
+
+	// Импорт пространства имен: * as ns
+	if j+2 < len(tokens) && tokens[j].Is(TokenPunctuator, "*") && tokens[j+1].Is(TokenIdentifier, "as") {
+		imp.Bindings = append(imp.Bindings, importBinding{Local: tokens[j+2].Value, Imported: "*", TypeOnly: imp.TypeOnly})
+		j += 3
+	}
+
+	// Именованные импорты: { a, b as c, type T }
+	if j < len(tokens) && tokens[j].Is(TokenPunctuator, "{") {
+		var specifiers []namedSpecifier
+		specifiers, j = parseNamedSpecifiers(tokens, j)
+		for _, s := range specifiers {
+			imp.Bindings = append(imp.Bindings, importBinding{
+				Local:    s.Alias,
+				Imported: s.Name,
+				TypeOnly: imp.TypeOnly || s.TypeOnly,
+			})
+		}
+	}
+
+	if j+1 < len(tokens) && tokens[j].Is(TokenIdentifier, "from") && tokens[j+1].Type == TokenString {
+		imp.setSpecifier(tokens[j+1])
+		return imp, skipStatement(tokens, j+2), true
+	}
+
+	return imp, skipStatement(tokens, j), false
+}
+
+// parseImportCall разбирает вызов import('./x') или require('./x') со строковым
+// аргументом, начинающийся с индекса i. Вызовы методов (obj.require) не учитываются.
+// Для import() подходит и шаблонная строка без подстановок: import(`./x`).
+func parseImportCall(tokens []Token, i int) (moduleImport, bool) {
+	token := tokens[i]
+	if token.Value != "import" && token.Value != "require" {
+		return moduleImport{}, false
+	}
+	if i > 0 && (tokens[i-1].Is(TokenPunctuator, ".") || tokens[i-1].Is(TokenPunctuator, "?.")) {
+		return moduleImport{}, false
+	}
+	if i+3 >= len(tokens) || !tokens[i+1].Is(TokenPunctuator, "(") {
+		return moduleImport{}, false
+	}
+	// import('./x', { with: { type: 'json' } }) - второй аргумент содержит параметры импорта
+	if !tokens[i+3].Is(TokenPunctuator, ")") && (token.Value != "import" || !tokens[i+3].Is(TokenPunctuator, ",")) {
+		return moduleImport{}, false
+	}
+	argument := tokens[i+2]
+	if argument.Type != TokenString && (token.Value != "import" || !isStaticTemplate(argument)) {
+		return moduleImport{}, false
+	}
+
+	imp := moduleImport{
+		Line:    token.Line,
+		Dynamic: token.Value == "import",
+		Require: token.Value == "require",
+	}
+	imp.Lazy = imp.Dynamic && isLazyLoaded(tokens, i)
+	imp.setSpecifier(argument)
+	return imp, true
+}
+
+// isStaticTemplate проверяет, что лексема - шаблонная строка без подстановок
+func isStaticTemplate(token Token) bool {
+	return token.Type == TokenTemplate && len(token.Value) >= 2 &&
+		token.Value[0] == '`' && token.Value[len(token.Value)-1] == '`'
+}
+
+// lazyLoaders содержит функции отложенной загрузки компонентов:
+// React.lazy, @loadable/component и defineAsyncComponent из Vue
+var lazyLoaders = map[string]bool{
+	"lazy": true, "loadable": true, "defineAsyncComponent": true,
+}
+
+// isLazyLoaded проверяет, что вызов import() на индексе i находится внутри вызова
+// функции отложенной загрузки: lazy(() => import('./Page')). Поиск идет наружу
+// через тела функций до ближайшей незакрытой круглой скобки.
+func isLazyLoaded(tokens []Token, i int) bool {
+	depth := 0
+	for j := i - 1; j > 0; j-- {
+		token := tokens[j]
+		if token.Type != TokenPunctuator {
+			continue
+		}
+		switch token.Value {
+		case ")", "]", "}":
+			depth++
+		case "[", "{":
+			if depth > 0 {
+				depth--
+			}
+		case "(":
+			if depth > 0 {
+				depth--
+				continue
+			}
+			callee := tokens[j-1]
+			return callee.Type == TokenIdentifier && lazyLoaders[callee.Value]
+		}
+	}
+	return false
+}
+
+// parseDynamicExpression разбирает вызов import(), начинающийся с индекса i,
+// аргумент которого не является строкой: import(`./pages/${name}`) или import(path)
+func parseDynamicExpression(tokens []Token, i int) (dynamicExpression, bool) {
+	if !tokens[i].Is(TokenIdentifier, "import") || i+2 >= len(tokens) || !tokens[i+1].Is(TokenPunctuator, "(") {
+		return dynamicExpression{}, false
+	}
+	if i > 0 && (tokens[i-1].Is(TokenPunctuator, ".") || tokens[i-1].Is(TokenPunctuator, "?.")) {
+		return dynamicExpression{}, false
+	}
+
+	// Аргумент заканчивается перед "," (второй аргумент - параметры импорта) или ")"
+	start := i + 2
+	end := initializerEnd(tokens, start)
+	if end == start || end >= len(tokens) || end == start+1 && tokens[start].Type == TokenString {
+		return dynamicExpression{}, false
+	}
+
+	var b strings.Builder
+	for j := start; j < end; j++ {
+		// Пробельные символы между лексемами сворачиваются в один пробел
+		if j > start && tokens[j].Start > tokens[j-1].End {
+			b.WriteByte(' ')
+		}
+		b.WriteString(tokens[j].Value)
+	}
+	return dynamicExpression{
+		Expression: b.String(),
+		Start:      tokenStart(tokens[start]),
+		End:        tokenEnd(tokens[end-1]),
+	}, true
+}
+
+// setSpecifier запоминает строку модуля и ее положение в исходном тексте
+func (imp *moduleImport) setSpecifier(token Token) {
+	imp.Specifier = unquoteString(token.Value)
+	imp.SpecifierStart = tokenStart(token)
+	imp.SpecifierEnd = tokenEnd(token)
+}
+
+// parseExport разбирает инструкцию export, начинающуюся с индекса i
+func parseExport(tokens []Token, i int) ([]moduleExport, int) {
+	line := tokens[i].Line
+	j := i + 1
+	if j >= len(tokens) {
+		return nil, j
+	}
+
+	// export type { T } - реэкспорт типов
+	typeOnly := false
+	if j+1 < len(tokens) && tokens[j].Is(TokenIdentifier, "type") && tokens[j+1].Is(TokenPunctuator, "{") {
+		typeOnly = true
+		j++
+	}
+
+	token := tokens[j]
+	switch {
+	case token.Is(TokenPunctuator, "*"):
+		// export * from 'x' / export * as ns from 'x'
+		exported := "*"
+		j++
+		if j+1 < len(tokens) && tokens[j].Is(TokenIdentifier, "as") {
+			exported = tokens[j+1].Value
+			j += 2
+		}
+		if j+1 < len(tokens) && tokens[j].Is(TokenIdentifier, "from") && tokens[j+1].Type == TokenString {
+			return []moduleExport{{
+				Exported:       exported,
+				Specifier:      unquoteString(tokens[j+1].Value),
+				Imported:       "*",
+				Line:           line,
+				SpecifierStart: tokenStart(tokens[j+1]),
+				SpecifierEnd:   tokenEnd(tokens[j+1]),
+			}}, skipStatement(tokens, j+2)
+		}
+		return nil, skipStatement(tokens, j)
+
+	case token.Is(TokenPunctuator, "{"):
+		// export { a, b as c } / export { a as b } from 'x'
+		specifiers, next := parseNamedSpecifiers(tokens, j)
+		specifier := ""
+		var specifierToken Token
+		if next+1 < len(tokens) && tokens[next].Is(TokenIdentifier, "from") && tokens[next+1].Type == TokenString {
+			specifierToken = tokens[next+1]
+			specifier = unquoteString(specifierToken.Value)
+			next += 2
+		}
+
+		var exports []moduleExport
+		for _, s := range specifiers {
+			exp := moduleExport{Exported: s.Alias, Local: s.Name, Line: line}
+			if specifier != "" {
+				exp.Local = ""
+				exp.Specifier = specifier
+				exp.Imported = s.Name
+				exp.TypeOnly = typeOnly || s.TypeOnly
+				exp.SpecifierStart = tokenStart(specifierToken)
+				exp.SpecifierEnd = tokenEnd(specifierToken)
+			}
+			exports = append(exports, exp)
+		}
+		return exports, skipStatement(tokens, next)
+
+	case token.Is(TokenIdentifier, "default"):
+		j++
+		exp := moduleExport{Exported: "default", Line: line}
+		if j < len(tokens) {
+			if name, ok := declarationName(tokens, j); ok {
+				// export default function name() {} / export default class Name {}
+				exp.Local = name
+			} else if tokens[j].Type == TokenIdentifier && !reservedWords[tokens[j].Value] &&
+				initializerEnd(tokens, j) == j+1 {
+				// export default NAME
+				exp.Local = tokens[j].Value
+			}
+		}
+		return []moduleExport{exp}, j
+
+	case token.Is(TokenIdentifier, "const") || token.Is(TokenIdentifier, "let") || token.Is(TokenIdentifier, "var"):
+		// export const A = 1, B = 2
+		var exports []moduleExport
+		j++
+		for j < len(tokens) && tokens[j].Type == TokenIdentifier {
+			name := tokens[j].Value
+			if name != "enum" {
+				exports = append(exports, moduleExport{Exported: name, Local: name, Line: line})
+			}
+			j++
+			if j < len(tokens) && tokens[j].Is(TokenPunctuator, ":") {
+				j = typeAnnotationEnd(tokens, j+1)
+			}
+			if j < len(tokens) && tokens[j].Is(TokenPunctuator, "=") {
+				j = initializerEnd(tokens, j+1)
+			}
+			if j < len(tokens) && tokens[j].Is(TokenPunctuator, ",") {
+				j++
+				continue
+			}
+			break
+		}
+		return exports, j
+
+	default:
+		// export function f / export class C / export enum E / export interface I ...
+		if name, ok := declarationName(tokens, j); ok {
+			return []moduleExport{{Exported: name, Local: name, Line: line}}, j
+		}
+		return nil, j
+	}
+}
+
+// declarationName возвращает имя объявления функции, класса или типа, начинающегося с индекса i
+func declarationName(tokens []Token, i int) (string, bool) {
+	for ; i < len(tokens); i++ {
+		token := tokens[i]
+		if token.Type != TokenIdentifier && !token.Is(TokenPunctuator, "*") {
+			return "", false
+		}
+		switch token.Value {
+		case "async", "declare", "abstract", "const", "*":
+			continue
+		case "function", "class", "enum", "interface", "type", "namespace", "module":
+			if i+1 < len(tokens) && tokens[i+1].Is(TokenPunctuator, "*") {
+				i++
+			}
+			if i+1 < len(tokens) && tokens[i+1].Type == TokenIdentifier && !tokens[i+1].Is(TokenIdentifier, "extends") {
+				return tokens[i+1].Value, true
+			}
+			return "", false
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// namedSpecifier описывает элемент списка { name as alias }
+type namedSpecifier struct {
+	Name     string
+	Alias    string
+	TypeOnly bool
+}
+
+// parseNamedSpecifiers разбирает список в фигурных скобках, начиная с "{" на индексе i.
+// Возвращает элементы списка и индекс лексемы после "}".
+func parseNamedSpecifiers(tokens []Token, i int) ([]namedSpecifier, int) {
+	var specifiers []namedSpecifier
+	j := i + 1
+
+	for j < len(tokens) && !tokens[j].Is(TokenPunctuator, "}") {
+		if tokens[j].Is(TokenPunctuator, ",") {
+			j++
+			continue
+		}
+
+		s := namedSpecifier{}
+		if tokens[j].Is(TokenIdentifier, "type") && j+1 < len(tokens) &&
+			(tokens[j+1].Type == TokenIdentifier || tokens[j+1].Type == TokenString) &&
+			!tokens[j+1].Is(TokenIdentifier, "as") {
+			s.TypeOnly = true
+			j++
+		}
+
+		switch tokens[j].Type {
+		case TokenIdentifier:
+			s.Name = tokens[j].Value
+		case TokenString:
+			// Произвольное имя экспорта: { "a-b" as ab }
+			s.Name = unquoteString(tokens[j].Value)
+		default:
+			// Неожиданная лексема: прекращаем разбор списка
+			return specifiers, skipStatement(tokens, j)
+		}
+		s.Alias = s.Name
+		j++
+
+		if j+1 < len(tokens) && tokens[j].Is(TokenIdentifier, "as") {
+			if tokens[j+1].Type == TokenString {
+				s.Alias = unquoteString(tokens[j+1].Value)
+			} else {
+				s.Alias = tokens[j+1].Value
+			}
+			j += 2
+		}
+
+		specifiers = append(specifiers, s)
+	}
+
+	return specifiers, j + 1
+}
+
+// unquoteString возвращает содержимое строкового литерала JS без кавычек
+func unquoteString(literal string) string {
+	if len(literal) < 2 {
+		return literal
+	}
+	body := literal[1 : len(literal)-1]
+	if !strings.Contains(body, "\\") {
+		return body
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		if body[i] == '\\' && i+1 < len(body) {
+			i++
+		}
+		b.WriteByte(body[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+func TestParseModuleImports(t *testing.T) {
+	src := `
+import './polyfills';
+import React, { useState as useLocalState, type FC } from 'react';
+import * as config from "./config";
+import type { Props } from './types';
+import Default, * as everything from '../lib';
+import { "kebab-name" as kebab } from './strings';
+const lazy = import('./lazy');
+const legacy = require('./legacy');
+loader.require('./ignored');
+function f() {
+  import('./nested');
+}
+`
+
+	tokens, err := Tokenize(src, true)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка лексера: %v", err)
+	}
+
+	module := parseModule(tokens)
+
+	expected := []struct {
+		specifier string
+		typeOnly  bool
+		kind      string
+		bindings  []importBinding
+	}{
+		{"./polyfills", false, models.ImportSideEffect, nil},
+		{"react", false, models.ImportStatic, []importBinding{
+			{Local: "React", Imported: "default"},
+			{Local: "useLocalState", Imported: "useState"},
+			{Local: "FC", Imported: "FC", TypeOnly: true},
+		}},
+		{"./config", false, models.ImportStatic, []importBinding{{Local: "config", Imported: "*"}}},
+		{"./types", true, models.ImportTypeOnly, []importBinding{{Local: "Props", Imported: "Props", TypeOnly: true}}},
+		{"../lib", false, models.ImportStatic, []importBinding{
+			{Local: "Default", Imported: "default"},
+			{Local: "everything", Imported: "*"},
+		}},
+		{"./strings", false, models.ImportStatic, []importBinding{{Local: "kebab", Imported: "kebab-name"}}},
+		// Вызовы import() и require() со строковым аргументом, в том числе вложенные
+		{"./lazy", false, models.ImportDynamic, nil},
+		{"./legacy", false, models.ImportStatic, []importBinding{{Local: "legacy", Imported: "*"}}},
+		{"./nested", false, models.ImportDynamic, nil},
+	}
+
+	if len(module.Imports) != len(expected) {
+		t.Fatalf("Ожидается %d импортов, получено: %d (%+v)", len(expected), len(module.Imports), module.Imports)
+	}
+
+	for i, exp := range expected {
+		imp := module.Imports[i]
+		if imp.Specifier != exp.specifier || imp.TypeOnly != exp.typeOnly {
+			t.Errorf("Импорт %d: ожидается %q (type=%v), получено: %q (type=%v)",
+				i, exp.specifier, exp.typeOnly, imp.Specifier, imp.TypeOnly)
+		}
+		if kind := imp.kind(); kind != exp.kind {
+			t.Errorf("Импорт %q: ожидается вид %s, получено: %s", exp.specifier, exp.kind, kind)
+		}
+		if len(imp.Bindings) != len(exp.bindings) {
+			t.Errorf("Импорт %q: ожидается %d привязок, получено: %d", exp.specifier, len(exp.bindings), len(imp.Bindings))
+			continue
+		}
+		for j, binding := range exp.bindings {
+			if imp.Bindings[j] != binding {
+				t.Errorf("Импорт %q: ожидается привязка %+v, получено: %+v", exp.specifier, binding, imp.Bindings[j])
+			}
+		}
+	}
+}
+
+func TestParseModuleExports(t *testing.T) {
+	src := `
+export const A = 1, B = A + 1;
+export default CONFIG;
+export function helper() {}
+export class Service {}
+export { C, D as E };
+export { F as G, default as H } from './other';
+export * from './all';
+export * as ns from './namespace';
+export type { T } from './types';
+export enum Color { Red }
+`
+
+	tokens, err := Tokenize(src, false)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка лексера: %v", err)
+	}
+
+	module := parseModule(tokens)
+
+	expected := []moduleExport{
+		{Exported: "A", Local: "A"},
+		{Exported: "B", Local: "B"},
+		{Exported: "default", Local: "CONFIG"},
+		{Exported: "helper", Local: "helper"},
+		{Exported: "Service", Local: "Service"},
+		{Exported: "C", Local: "C"},
+		{Exported: "E", Local: "D"},
+		{Exported: "G", Specifier: "./other", Imported: "F"},
+		{Exported: "H", Specifier: "./other", Imported: "default"},
+		{Exported: "*", Specifier: "./all", Imported: "*"},
+		{Exported: "ns", Specifier: "./namespace", Imported: "*"},
+		{Exported: "T", Specifier: "./types", Imported: "T", TypeOnly: true},
+		{Exported: "Color", Local: "Color"},
+	}
+
+	if len(module.Exports) != len(expected) {
+		t.Fatalf("Ожидается %d экспортов, получено: %d (%+v)", len(expected), len(module.Exports), module.Exports)
+	}
+
+	for i, exp := range expected {
+		actual := module.Exports[i]
+		actual.Line = 0
+		actual.SpecifierStart, actual.SpecifierEnd = models.Position{}, models.Position{}
+		if actual != exp {
+			t.Errorf("Экспорт %d: ожидается %+v, получено: %+v", i, exp, actual)
+		}
+	}
+}
+
+func TestParseModuleAnonymousDefault(t *testing.T) {
+	tokens, _ := Tokenize("export default { a: A };\nexport default A + B;", false)
+	module := parseModule(tokens)
+
+	for _, exp := range module.Exports {
+		if exp.Exported != "default" || exp.Local != "" {
+			t.Errorf("Ожидается анонимный экспорт по умолчанию, получено: %+v", exp)
+		}
+	}
+}
+
+func TestParseModuleDynamicImports(t *testing.T) {
+	src := "const Home = React.lazy(() => import('./Home'));\n" +
+		"const Admin = lazy(async () => { return import(`./Admin`); });\n" +
+		"const Chart = loadable(() => import('./Chart'), { fallback });\n" +
+		"load(() => import('./eager')).then(render);\n" +
+		"const data = import('./data.json', { with: { type: 'json' } });\n" +
+		"const page = import(`./pages/${name}.tsx`);\n" +
+		"const mod = import(base  +  name);\n"
+
+	tokens, err := Tokenize(src, false)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка лексера: %v", err)
+	}
+
+	module := parseModule(tokens)
+
+	expected := []struct {
+		specifier string
+		lazy      bool
+	}{
+		{"./Home", true},
+		{"./Admin", true},
+		{"./Chart", true},
+		{"./eager", false},
+		{"./data.json", false},
+	}
+	if len(module.Imports) != len(expected) {
+		t.Fatalf("Ожидается %d импортов, получено: %d (%+v)", len(expected), len(module.Imports), module.Imports)
+	}
+	for i, exp := range expected {
+		imp := module.Imports[i]
+		if imp.Specifier != exp.specifier || imp.Lazy != exp.lazy || imp.kind() != models.ImportDynamic {
+			t.Errorf("Импорт %d: ожидается %q (lazy=%v), получено: %+v", i, exp.specifier, exp.lazy, imp)
+		}
+	}
+
+	// Вычисляемые модули: текст аргумента и его положение
+	if len(module.Dynamic) != 2 {
+		t.Fatalf("Ожидается 2 вычисляемых импорта, получено: %+v", module.Dynamic)
+	}
+	if expr := module.Dynamic[0]; expr.Expression != "`./pages/${name}.tsx`" || expr.Start.Line != 6 || expr.Start.Column != 21 {
+		t.Errorf("Неверный вычисляемый импорт: %+v", expr)
+	}
+	if expr := module.Dynamic[1]; expr.Expression != "base + name" {
+		t.Errorf("Неверный вычисляемый импорт: %+v", expr)
+	}
+}
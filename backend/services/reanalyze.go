@@ -0,0 +1,231 @@
+package services
+
+import (
+	"log"
+	"sort"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// ReanalyzeFiles повторно анализирует измененные, добавленные и удаленные файлы
+// и обновляет граф на месте. Кроме самих файлов пересчитываются зависимости файлов,
+// которые импортируют их напрямую или через реэкспорты. Узлы и ребра графа
+// заменяются под одной блокировкой GraphMutex, поэтому читатели не видят
+// промежуточного состояния. Возвращает изменения графа и списка файлов.
+func (ds *DependencyService) ReanalyzeFiles(files []string) models.GraphDelta {
+	ds.reanalyzeMutex.Lock()
+	defer ds.reanalyzeMutex.Unlock()
+
+	changed := make(map[string]bool, len(files))
+	for _, file := range files {
+		changed[file] = true
+	}
+
+	// Разбираем файлы, которые еще существуют
+	analyses := make(map[string]fileAnalysis)
+	for file := range changed {
+		if !isFile(file) {
+			continue
+		}
+		if analysis, ok := ds.analyzeFile(file); ok {
+			analyses[file] = analysis
+		}
+	}
+
+	delta := models.GraphDelta{}
+
+	// Файлы, константы которых ссылались на константы измененных файлов
+	recompute := make(map[string]bool)
+	ds.GraphMutex.Lock()
+	for file := range changed {
+		_, known := ds.modules[file]
+		_, exists := analyses[file]
+		if exists && !known {
+			delta.AddedFiles = append(delta.AddedFiles, ds.relativePath(file))
+		} else if !exists && known {
+			delta.RemovedFiles = append(delta.RemovedFiles, ds.relativePath(file))
+		}
+	}
+	nodeFiles := make(map[string]string, len(ds.Graph.Nodes))
+	for _, node := range ds.Graph.Nodes {
+		nodeFiles[node.ID] = node.FilePath
+	}
+	for _, edge := range ds.Graph.Edges {
+		if changed[nodeFiles[edge.Target]] {
+			recompute[nodeFiles[edge.Source]] = true
+		}
+	}
+
+	// Обновляем импорты, экспорты и объявления, по которым разрешаются зависимости.
+	// Граф, который видят читатели, пока не меняется.
+	for file := range changed {
+		analysis, ok := analyses[file]
+		if !ok {
+			delete(ds.modules, file)
+			delete(ds.declared, file)
+			continue
+		}
+		ds.modules[file] = analysis.module
+		ds.declared[file] = make(map[string]bool)
+		for _, constant := range analysis.constants {
+			ds.declared[file][constant.Name] = true
+		}
+	}
+	ds.GraphMutex.Unlock()
+
+	for file := range ds.importersOf(changed) {
+		recompute[file] = true
+	}
+	for file := range analyses {
+		recompute[file] = true
+	}
+
+	var edges []models.Dependency
+	for file := range recompute {
+		if file == "" || !isFile(file) {
+			continue
+		}
+		edges = append(edges, ds.dependenciesOf(file)...)
+	}
+
+	ds.GraphMutex.Lock()
+	defer ds.GraphMutex.Unlock()
+
+	previous := make(map[string]models.Constant)
+	nodes := make([]models.Constant, 0, len(ds.Graph.Nodes))
+	for _, node := range ds.Graph.Nodes {
+		if changed[node.FilePath] {
+			previous[node.ID] = node
+			delete(ds.ConstantMap, node.ID)
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	for _, analysis := range analyses {
+		for _, constant := range analysis.constants {
+			nodes = append(nodes, constant)
+			ds.ConstantMap[constant.ID] = true
+
+			old, existed := previous[constant.ID]
+			switch {
+			case !existed:
+				delta.AddedNodes = append(delta.AddedNodes, constant)
+			case old != constant:
+				delta.ChangedNodes = append(delta.ChangedNodes, constant)
+			}
+			delete(previous, constant.ID)
+		}
+	}
+	for id := range previous {
+		delta.RemovedNodes = append(delta.RemovedNodes, id)
+	}
+	ds.Graph.Nodes = nodes
+
+	added := make(map[models.Dependency]bool, len(edges))
+	for _, edge := range edges {
+		added[edge] = true
+	}
+
+	kept := make([]models.Dependency, 0, len(ds.Graph.Edges)+len(edges))
+	for _, edge := range ds.Graph.Edges {
+		if recompute[nodeFiles[edge.Source]] || !ds.ConstantMap[edge.Target] || !ds.ConstantMap[edge.Source] {
+			if added[edge] {
+				// Ребро пересчитано без изменений
+				delete(added, edge)
+			} else {
+				delta.RemovedEdges = append(delta.RemovedEdges, edge)
+			}
+			continue
+		}
+		kept = append(kept, edge)
+	}
+	for _, edge := range edges {
+		if added[edge] {
+			delta.AddedEdges = append(delta.AddedEdges, edge)
+		}
+	}
+	ds.Graph.Edges = append(kept, edges...)
+
+	sortDelta(&delta)
+
+	log.Printf("Re-analyzed %d files, recomputed dependencies of %d files\n", len(changed), len(recompute))
+
+	return delta
+}
+
+// ApplyChanges обновляет граф по изменениям, найденным Watcher. Изменение файлов
+// конфигурации сбрасывает кэш ModuleResolver, и импорты всех файлов разрешаются заново.
+func (ds *DependencyService) ApplyChanges(changes FileChanges) models.GraphDelta {
+	files := changes.Files()
+	if len(changes.Configs) > 0 {
+		log.Printf("Module resolution settings changed: %v\n", changes.Configs)
+		ds.Resolver.Reset()
+		files = append(files, ds.analyzedFiles()...)
+	}
+	return ds.ReanalyzeFiles(files)
+}
+
+// analyzedFiles возвращает отсортированный список проанализированных файлов
+func (ds *DependencyService) analyzedFiles() []string {
+	ds.GraphMutex.RLock()
+	defer ds.GraphMutex.RUnlock()
+
+	files := make([]string, 0, len(ds.modules))
+	for file := range ds.modules {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// sortDelta упорядочивает изменения, чтобы результат не зависел от порядка обхода карт
+func sortDelta(delta *models.GraphDelta) {
+	sortConstants := func(constants []models.Constant) {
+		sort.Slice(constants, func(i, j int) bool { return constants[i].ID < constants[j].ID })
+	}
+	sortDependencies := func(dependencies []models.Dependency) {
+		sort.Slice(dependencies, func(i, j int) bool {
+			if dependencies[i].Source != dependencies[j].Source {
+				return dependencies[i].Source < dependencies[j].Source
+			}
+			return dependencies[i].Target < dependencies[j].Target
+		})
+	}
+
+	sortConstants(delta.AddedNodes)
+	sortConstants(delta.ChangedNodes)
+	sort.Strings(delta.RemovedNodes)
+	sortDependencies(delta.AddedEdges)
+	sortDependencies(delta.RemovedEdges)
+	sort.Strings(delta.AddedFiles)
+	sort.Strings(delta.RemovedFiles)
+}
+
+// importersOf возвращает файлы, которые импортируют указанные файлы напрямую
+// или через цепочку реэкспортов
+func (ds *DependencyService) importersOf(files map[string]bool) map[string]bool {
+	_, links := ds.moduleLinks()
+
+	// Файлы, реэкспортирующие указанные, тоже отдают их константы
+	targets := make(map[string]bool, len(files))
+	for file := range files {
+		targets[file] = true
+	}
+	for grown := true; grown; {
+		grown = false
+		for _, link := range links {
+			if link.ReExport && targets[link.To] && !targets[link.From] {
+				targets[link.From] = true
+				grown = true
+			}
+		}
+	}
+
+	importers := make(map[string]bool)
+	for _, link := range links {
+		if targets[link.To] && !files[link.From] {
+			importers[link.From] = true
+		}
+	}
+	return importers
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+// graphSummary возвращает отсортированные идентификаторы узлов и ребра графа
+func graphSummary(ds *DependencyService) ([]string, []string) {
+	ds.GraphMutex.RLock()
+	defer ds.GraphMutex.RUnlock()
+
+	var nodes, edges []string
+	for _, node := range ds.Graph.Nodes {
+		nodes = append(nodes, node.ID+"="+node.Value)
+	}
+	for _, edge := range ds.Graph.Edges {
+		edges = append(edges, edge.Source+" -> "+edge.Target+" ("+edge.Kind+")")
+	}
+	sort.Strings(nodes)
+	sort.Strings(edges)
+	return nodes, edges
+}
+
+func TestReanalyzeFiles(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "reanalyze-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"a.ts":     "export const A = 1;\nexport const OLD = 2;",
+		"index.ts": "export * from './a';",
+		"b.ts":     "import { A, NEW, OLD } from './index';\nexport const B = A + NEW;\nexport const B_OLD = OLD;",
+		"c.ts":     "import { D } from './d';\nexport const C = D;",
+		"e.ts":     "export const E = 5;",
+		"f.ts":     "import { E } from './e';\nexport const F = E;",
+	})
+
+	ds := NewDependencyService(NewFileService(tempDir, nil))
+	ds.BuildDependencyGraph()
+
+	// Изменяем a.ts, добавляем d.ts, удаляем e.ts
+	writeTestFiles(t, tempDir, map[string]string{
+		"a.ts": "export const A = 10;\nexport const NEW = A * 2;",
+		"d.ts": "export const D = 4;",
+	})
+	if err := os.Remove(filepath.Join(tempDir, "e.ts")); err != nil {
+		t.Fatalf("Не удалось удалить файл: %v", err)
+	}
+
+	delta := ds.ReanalyzeFiles([]string{
+		filepath.Join(tempDir, "a.ts"),
+		filepath.Join(tempDir, "d.ts"),
+		filepath.Join(tempDir, "e.ts"),
+	})
+
+	// Результат должен совпадать с полным анализом проекта
+	fresh := NewDependencyService(NewFileService(tempDir, nil))
+	fresh.BuildDependencyGraph()
+
+	nodes, edges := graphSummary(ds)
+	expectedNodes, expectedEdges := graphSummary(fresh)
+
+	if !equalStrings(nodes, expectedNodes) {
+		t.Errorf("Узлы после повторного анализа:\n%v\nожидается:\n%v", nodes, expectedNodes)
+	}
+	if !equalStrings(edges, expectedEdges) {
+		t.Errorf("Ребра после повторного анализа:\n%v\nожидается:\n%v", edges, expectedEdges)
+	}
+
+	for _, edge := range []string{
+		"b.ts#B -> a.ts#NEW (import)",
+		"c.ts#C -> d.ts#D (import)",
+		"a.ts#NEW -> a.ts#A (local)",
+	} {
+		found := false
+		for _, e := range edges {
+			found = found || e == edge
+		}
+		if !found {
+			t.Errorf("Ожидается ребро %s, получено: %v", edge, edges)
+		}
+	}
+
+	if !equalStrings(delta.AddedFiles, []string{"d.ts"}) || !equalStrings(delta.RemovedFiles, []string{"e.ts"}) {
+		t.Errorf("Ожидается добавление d.ts и удаление e.ts, получено: %v, %v", delta.AddedFiles, delta.RemovedFiles)
+	}
+	if !equalStrings(delta.RemovedNodes, []string{"a.ts#OLD", "e.ts#E"}) {
+		t.Errorf("Ожидается удаление a.ts#OLD и e.ts#E, получено: %v", delta.RemovedNodes)
+	}
+	if len(delta.ChangedNodes) != 1 || delta.ChangedNodes[0].ID != "a.ts#A" || delta.ChangedNodes[0].Value != "10" {
+		t.Errorf("Ожидается изменение a.ts#A, получено: %+v", delta.ChangedNodes)
+	}
+
+	var addedNodes, addedEdges, removedEdges []string
+	for _, node := range delta.AddedNodes {
+		addedNodes = append(addedNodes, node.ID)
+	}
+	for _, edge := range delta.AddedEdges {
+		addedEdges = append(addedEdges, edge.Source+" -> "+edge.Target)
+	}
+	for _, edge := range delta.RemovedEdges {
+		removedEdges = append(removedEdges, edge.Source+" -> "+edge.Target)
+	}
+	if !equalStrings(addedNodes, []string{"a.ts#NEW", "d.ts#D"}) {
+		t.Errorf("Ожидается добавление a.ts#NEW и d.ts#D, получено: %v", addedNodes)
+	}
+	if !equalStrings(addedEdges, []string{"a.ts#NEW -> a.ts#A", "b.ts#B -> a.ts#NEW", "c.ts#C -> d.ts#D"}) {
+		t.Errorf("Неожиданные добавленные ребра: %v", addedEdges)
+	}
+	// Ребро b.ts#B -> a.ts#A пересчитано, но не изменилось
+	if !equalStrings(removedEdges, []string{"b.ts#B_OLD -> a.ts#OLD", "f.ts#F -> e.ts#E"}) {
+		t.Errorf("Неожиданные удаленные ребра: %v", removedEdges)
+	}
+
+	// Повторный анализ без изменений не меняет граф
+	if delta := ds.ReanalyzeFiles([]string{filepath.Join(tempDir, "a.ts")}); !delta.Empty() {
+		t.Errorf("Ожидается пустой набор изменений, получено: %+v", delta)
+	}
+
+	if ds.ConstantMap["e.ts#E"] || ds.ConstantMap["a.ts#OLD"] {
+		t.Errorf("Удаленные константы должны быть исключены из ConstantMap")
+	}
+}
+
+func TestApplyConfigChanges(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "reanalyze-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"tsconfig.json":     `{ "compilerOptions": { "paths": { "@/*": ["src/*"] } } }`,
+		"src/config.ts":     "export const API_URL = '/api';",
+		"lib/config.ts":     "export const API_URL = '/lib';",
+		"src/app/client.ts": "import { API_URL } from '@/config';\nexport const ENDPOINT = API_URL;",
+	})
+
+	ds := NewDependencyService(NewFileService(tempDir, nil))
+	watcher := NewWatcher(ds.FileService, time.Hour)
+	ds.BuildDependencyGraph()
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"tsconfig.json": `{ "compilerOptions": { "paths": { "@/*": ["lib/*", "src/*"] } } }`,
+	})
+
+	changes := watcher.Poll()
+	expected := []string{filepath.Join(tempDir, "tsconfig.json")}
+	if !equalStrings(changes.Configs, expected) || len(changes.Files()) != 0 {
+		t.Fatalf("Ожидается изменение только %v, получено: %+v", expected, changes)
+	}
+
+	delta := ds.ApplyChanges(changes)
+	_, edges := graphSummary(ds)
+	if len(edges) != 1 || edges[0] != "src/app/client.ts#ENDPOINT -> lib/config.ts#API_URL (import)" {
+		t.Errorf("Импорт не разрешен по новому tsconfig.json, ребра: %v", edges)
+	}
+	if len(delta.AddedEdges) != 1 || len(delta.RemovedEdges) != 1 || len(delta.ChangedNodes) != 0 {
+		t.Errorf("Ожидается замена одного ребра, получено: %+v", delta)
+	}
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// resolveExtensions содержит расширения, которые подставляются к спецификатору без расширения,
+// в том порядке, в котором их перебирает tsc
+var resolveExtensions = []string{".ts", ".tsx", ".d.ts", ".js", ".jsx"}
+
+// ModuleResolver сопоставляет спецификаторы импортов с файлами проекта.
+// Нерелятивные спецификаторы разрешаются через псевдонимы из конфигурации
+// проекта, затем через compilerOptions.paths и baseUrl ближайшего
+// tsconfig.json или jsconfig.json.
+type ModuleResolver struct {
+	ProjectPath string
+	// Aliases - псевдонимы в формате compilerOptions.paths относительно корня проекта
+	Aliases map[string][]string
+
+	extensions []string // Расширения в порядке перебора
+	mutex      sync.Mutex
+	configs    map[string]*TSConfig // Ближайшая конфигурация для директории (nil, если нет)
+}
+
+// NewModuleResolver создает новый экземпляр ModuleResolver
+func NewModuleResolver(projectPath string) *ModuleResolver {
+	return &ModuleResolver{
+		ProjectPath: projectPath,
+		extensions:  resolveExtensions,
+		configs:     make(map[string]*TSConfig),
+	}
+}
+
+// SetExtensions ограничивает разрешение импортов файлами с расширениями
+// из конфигурации проекта. Расширения, известные tsc, перебираются в его
+// порядке (.d.ts - вместе с .ts), остальные - в порядке конфигурации.
+func (r *ModuleResolver) SetExtensions(extensions []string) {
+	configured := make(map[string]bool, len(extensions))
+	for _, ext := range extensions {
+		configured[ext] = true
+	}
+
+	order := make([]string, 0, len(extensions)+1)
+	for _, ext := range resolveExtensions {
+		if configured[ext] || ext == ".d.ts" && configured[".ts"] {
+			order = append(order, ext)
+			configured[ext] = false
+		}
+	}
+	for _, ext := range extensions {
+		if configured[ext] {
+			order = append(order, ext)
+			configured[ext] = false
+		}
+	}
+	r.extensions = order
+}
+
+// Resolve возвращает абсолютный путь к файлу, на который ссылается спецификатор
+// specifier из файла fromFile. Пакеты из node_modules не разрешаются.
+func (r *ModuleResolver) Resolve(fromFile, specifier string) (string, bool) {
+	return r.resolve(fromFile, specifier, func(base string) (string, bool) {
+		return probeModulePath(base, r.extensions)
+	})
+}
+
+// ResolveAsset возвращает абсолютный путь к существующему файлу с любым
+// расширением, на который указывает спецификатор без подстановки расширений:
+// './styles.css', '@/data.json'
+func (r *ModuleResolver) ResolveAsset(fromFile, specifier string) (string, bool) {
+	return r.resolve(fromFile, specifier, func(base string) (string, bool) {
+		return base, isFile(base)
+	})
+}
+
+// resolve разрешает спецификатор в путь и подбирает по нему файл функцией probe
+func (r *ModuleResolver) resolve(fromFile, specifier string, probe func(base string) (string, bool)) (string, bool) {
+	if isRelativeSpecifier(specifier) {
+		base := filepath.Clean(specifier)
+		if !filepath.IsAbs(specifier) {
+			base = filepath.Join(filepath.Dir(fromFile), filepath.FromSlash(specifier))
+		}
+		return probe(base)
+	}
+
+	if len(r.Aliases) > 0 {
+		aliases := TSConfig{Paths: r.Aliases, PathsDir: r.ProjectPath}
+		if resolved, ok := aliases.resolveNonRelative(specifier, probe); ok {
+			return resolved, true
+		}
+	}
+
+	if config := r.ConfigFor(fromFile); config != nil {
+		return config.resolveNonRelative(specifier, probe)
+	}
+
+	return "", false
+}
+
+// Reset сбрасывает найденные конфигурации TypeScript, чтобы после изменения
+// tsconfig.json или jsconfig.json они были прочитаны заново
+func (r *ModuleResolver) Reset() {
+	r.mutex.Lock()
+	r.configs = make(map[string]*TSConfig)
+	r.mutex.Unlock()
+}
+
+// ConfigFor возвращает ближайшую к файлу конфигурацию TypeScript в пределах проекта
+func (r *ModuleResolver) ConfigFor(filePath string) *TSConfig {
+	return r.configForDir(filepath.Dir(filePath))
+}
+
+func (r *ModuleResolver) configForDir(dir string) *TSConfig {
+	r.mutex.Lock()
+	config, ok := r.configs[dir]
+	r.mutex.Unlock()
+	if ok {
+		return config
+	}
+
+	for _, name := range tsConfigNames {
+		path := filepath.Join(dir, name)
+		if !isFile(path) {
+			continue
+		}
+		loaded, err := LoadTSConfig(path)
+		if err != nil {
+			log.Printf("Error loading %s: %v\n", path, err)
+			continue
+		}
+		config = loaded
+		break
+	}
+
+	// Поднимаемся к родительской директории, но не выше корня проекта
+	if config == nil && dir != r.ProjectPath && isWithin(r.ProjectPath, dir) {
+		config = r.configForDir(filepath.Dir(dir))
+	}
+
+	r.mutex.Lock()
+	r.configs[dir] = config
+	r.mutex.Unlock()
+
+	return config
+}
+
+// isWithin проверяет, находится ли путь path внутри директории root
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// isRelativeSpecifier проверяет, указывает ли спецификатор на файл по относительному пути
+func isRelativeSpecifier(specifier string) bool {
+	return specifier == "." || specifier == ".." ||
+		strings.HasPrefix(specifier, "./") || strings.HasPrefix(specifier, "../") ||
+		strings.HasPrefix(specifier, "/")
+}
+
+// probeModulePath подбирает файл так же, как tsc: по точному имени, с подстановкой
+// расширений extensions, через поля types/typings/main в package.json или index-файл директории
+func probeModulePath(base string, extensions []string) (string, bool) {
+	// В TypeScript импорт "./x.js" указывает на исходный файл "./x.ts"
+	if ext := filepath.Ext(base); ext == ".js" || ext == ".jsx" {
+		trimmed := strings.TrimSuffix(base, ext)
+		for _, tsExt := range []string{".ts", ".tsx", ".d.ts"} {
+			if isFile(trimmed+tsExt) && hasSourceExtension(trimmed+tsExt, extensions) {
+				return trimmed + tsExt, true
+			}
+		}
+	}
+
+	if isFile(base) && hasSourceExtension(base, extensions) {
+		return base, true
+	}
+
+	if resolved, ok := probeExtensions(base, extensions); ok {
+		return resolved, true
+	}
+
+	if info, err := os.Stat(base); err != nil || !info.IsDir() {
+		return "", false
+	}
+
+	if entry, ok := packageEntry(base); ok {
+		entryPath := filepath.Join(base, filepath.FromSlash(entry))
+		if isFile(entryPath) && hasSourceExtension(entryPath, extensions) {
+			return entryPath, true
+		}
+		if resolved, ok := probeExtensions(entryPath, extensions); ok {
+			return resolved, true
+		}
+		if resolved, ok := probeExtensions(filepath.Join(entryPath, "index"), extensions); ok {
+			return resolved, true
+		}
+	}
+
+	return probeExtensions(filepath.Join(base, "index"), extensions)
+}
+
+// probeExtensions подставляет к пути расширения extensions
+func probeExtensions(base string, extensions []string) (string, bool) {
+	for _, ext := range extensions {
+		if isFile(base + ext) {
+			return base + ext, true
+		}
+	}
+	return "", false
+}
+
+// packageEntry возвращает точку входа пакета из package.json директории
+func packageEntry(dir string) (string, bool) {
+	content, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return "", false
+	}
+
+	var pkg struct {
+		Types   string `json:"types"`
+		Typings string `json:"typings"`
+		Main    string `json:"main"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return "", false
+	}
+
+	for _, entry := range []string{pkg.Types, pkg.Typings, pkg.Main} {
+		if entry != "" {
+			return entry, true
+		}
+	}
+	return "", false
+}
+
+// hasSourceExtension проверяет, анализируется ли файл с расширением из extensions.
+// Расширения сравниваются с окончанием имени, как в FileService.
+func hasSourceExtension(path string, extensions []string) bool {
+	for _, ext := range extensions {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/avor0n/dependency-graph-visualizer/config"
+)
+
+func TestModuleResolverResolve(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "resolver-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := []string{
+		"src/app.ts",
+		"src/config.ts",
+		"src/view.tsx",
+		"src/legacy.js",
+		"src/utils/index.ts",
+		"src/styles.css",
+	}
+	for _, file := range files {
+		path := filepath.Join(tempDir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Не удалось создать директорию: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(""), 0644); err != nil {
+			t.Fatalf("Не удалось создать файл %s: %v", file, err)
+		}
+	}
+
+	resolver := NewModuleResolver(tempDir)
+	from := filepath.Join(tempDir, "src", "app.ts")
+
+	tests := []struct {
+		specifier string
+		expected  string
+	}{
+		{"./config", "src/config.ts"},
+		{"./config.js", "src/config.ts"},
+		{"./view", "src/view.tsx"},
+		{"./legacy", "src/legacy.js"},
+		{"./legacy.js", "src/legacy.js"},
+		{"./utils", "src/utils/index.ts"},
+		{"../src/config", "src/config.ts"},
+		{"./styles.css", ""},
+		{"./missing", ""},
+		{"react", ""},
+	}
+
+	for _, test := range tests {
+		resolved, ok := resolver.Resolve(from, test.specifier)
+		if test.expected == "" {
+			if ok {
+				t.Errorf("Для %q не ожидается разрешение, получено: %s", test.specifier, resolved)
+			}
+			continue
+		}
+
+		expected := filepath.Join(tempDir, filepath.FromSlash(test.expected))
+		if !ok || resolved != expected {
+			t.Errorf("Для %q ожидается %s, получено: %s (ok=%v)", test.specifier, expected, resolved, ok)
+		}
+	}
+}
+
+func TestModuleResolverConfiguredExtensions(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "resolver-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		".depgraph.json": `{ "extensions": [".mjs"] }`,
+		"a.mjs":          "import { B } from './b.mjs';\nimport { C } from './c';\nexport const A = B + C;",
+		"b.mjs":          "export const B = 1;",
+		"c.mjs":          "export const C = 2;",
+		// Файл с неанализируемым расширением не подставляется
+		"c.ts": "export const C = 3;",
+	})
+
+	cfg, err := config.Load(tempDir)
+	if err != nil {
+		t.Fatalf("Не удалось загрузить конфигурацию: %v", err)
+	}
+	fileService := NewFileService(tempDir, nil)
+	fileService.Configure(cfg)
+	ds := NewDependencyService(fileService)
+	ds.Configure(cfg)
+	ds.BuildDependencyGraph()
+
+	_, edges := graphSummary(ds)
+	expected := []string{
+		"a.mjs#A -> b.mjs#B (import)",
+		"a.mjs#A -> c.mjs#C (import)",
+	}
+	if !reflect.DeepEqual(edges, expected) {
+		t.Errorf("Ожидаются ребра %v, получено: %v", expected, edges)
+	}
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/avor0n/dependency-graph-visualizer/config"
+	"github.com/avor0n/dependency-graph-visualizer/models"
+	"github.com/avor0n/dependency-graph-visualizer/utils"
+)
+
+// compiledRule - правило с разобранными шаблонами путей
+type compiledRule struct {
+	config.Rule
+	from  *utils.PatternSet
+	deny  *utils.PatternSet
+	allow *utils.PatternSet
+}
+
+// compileRules компилирует шаблоны правил
+func compileRules(rules []config.Rule) ([]compiledRule, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		from, err := utils.NewPatternSet(rule.From)
+		if err != nil {
+			return nil, fmt.Errorf("правило %s: %w", rule.Name, err)
+		}
+		deny, err := utils.NewPatternSet(rule.Deny)
+		if err != nil {
+			return nil, fmt.Errorf("правило %s: %w", rule.Name, err)
+		}
+		allow, err := utils.NewPatternSet(rule.Allow)
+		if err != nil {
+			return nil, fmt.Errorf("правило %s: %w", rule.Name, err)
+		}
+		compiled = append(compiled, compiledRule{Rule: rule, from: from, deny: deny, allow: allow})
+	}
+	return compiled, nil
+}
+
+// forbids проверяет, запрещает ли правило зависимость файла source от файла target
+// (пути относительно корня проекта). Зависимости внутри файла не проверяются.
+func (r compiledRule) forbids(source, target string) bool {
+	if source == target || !r.from.Match(source, false) {
+		return false
+	}
+	if r.allow.Match(target, false) {
+		return false
+	}
+	// Без deny правило разрешает только зависимости из allow
+	return r.deny.Empty() || r.deny.Match(target, false)
+}
+
+// CheckRules проверяет импорты между файлами и зависимости констант
+// из разных файлов на соответствие правилам
+func (ds *DependencyService) CheckRules(rules []config.Rule) (models.ViolationReport, error) {
+	report := models.ViolationReport{Rules: len(rules), Violations: []models.Violation{}}
+
+	compiled, err := compileRules(rules)
+	if err != nil || len(compiled) == 0 {
+		return report, err
+	}
+
+	add := func(rule compiledRule, violation models.Violation) {
+		violation.Rule = rule.Name
+		violation.Severity = rule.Severity
+		violation.Message = rule.Message
+		if violation.Severity == config.SeverityWarning {
+			report.Warnings++
+		} else {
+			report.Errors++
+		}
+		report.Violations = append(report.Violations, violation)
+	}
+
+	_, links := ds.moduleLinks()
+	for _, link := range links {
+		from, to := ds.relativePath(link.From), ds.relativePath(link.To)
+		for _, rule := range compiled {
+			if rule.forbids(from, to) {
+				add(rule, models.Violation{
+					Kind:     models.ViolationModule,
+					Source:   from,
+					Target:   to,
+					Location: models.NewLocation(from, link.Start, link.End),
+				})
+			}
+		}
+	}
+
+	ds.GraphMutex.RLock()
+	constants := make(map[string]models.Constant, len(ds.Graph.Nodes))
+	for _, node := range ds.Graph.Nodes {
+		constants[node.ID] = node
+	}
+	edges := append([]models.Dependency{}, ds.Graph.Edges...)
+	ds.GraphMutex.RUnlock()
+
+	for _, edge := range edges {
+		if edge.Kind != models.DependencyImport {
+			continue
+		}
+		source, ok := constants[edge.Source]
+		target, found := constants[edge.Target]
+		if !ok || !found {
+			continue
+		}
+
+		from, to := ds.relativePath(source.FilePath), ds.relativePath(target.FilePath)
+		for _, rule := range compiled {
+			if rule.forbids(from, to) {
+				add(rule, models.Violation{
+					Kind:     models.ViolationConstant,
+					Source:   edge.Source,
+					Target:   edge.Target,
+					Location: models.NewLocation(from, source.NameStart, source.NameEnd),
+				})
+			}
+		}
+	}
+
+	sort.Slice(report.Violations, func(i, j int) bool {
+		a, b := report.Violations[i], report.Violations[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		if a.Kind != b.Kind {
+			return a.Kind == models.ViolationModule
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Target < b.Target
+	})
+
+	return report, nil
+}
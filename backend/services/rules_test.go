@@ -0,0 +1,77 @@
+package services
+
+import (
+	"os"
+	"testing"
+
+	"github.com/avor0n/dependency-graph-visualizer/config"
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+func TestCheckRules(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "rules-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"src/db/client.ts": `export const DB_URL = 'postgres://';
+export const POOL = 5;`,
+		"src/db/types.ts": `export const TABLE = 'users';`,
+		"src/ui/button.ts": `import { DB_URL } from '../db/client';
+import { TABLE } from '../db/types';
+export const LABEL = DB_URL + TABLE;`,
+		"src/features/auth.ts": `export const AUTH = 'auth';`,
+		"src/shared/format.ts": `import { AUTH } from '../features/auth';
+import { LABEL } from '../ui/button';
+export const FORMAT = AUTH;
+export const LOCAL = FORMAT;`,
+	})
+
+	ds := NewDependencyService(NewFileService(tempDir, nil))
+	ds.BuildDependencyGraph()
+
+	rules := []config.Rule{
+		// Запрет с исключением для типов
+		{Name: "ui-no-db", From: []string{"src/ui/**"}, Deny: []string{"src/db/**"}, Allow: []string{"src/db/types.ts"},
+			Severity: config.SeverityError, Message: "UI не обращается к БД"},
+		// Разрешены только зависимости из allow
+		{Name: "shared-only", From: []string{"shared/"}, Allow: []string{"src/ui/"}, Severity: config.SeverityWarning},
+	}
+
+	report, err := ds.CheckRules(rules)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	expected := []models.Violation{
+		{Rule: "shared-only", Severity: config.SeverityWarning, Kind: models.ViolationModule,
+			Source: "src/shared/format.ts", Target: "src/features/auth.ts", Location: models.Location{File: "src/shared/format.ts", Line: 1, Column: 22, EndLine: 1, EndColumn: 40}},
+		{Rule: "shared-only", Severity: config.SeverityWarning, Kind: models.ViolationConstant,
+			Source: "src/shared/format.ts#FORMAT", Target: "src/features/auth.ts#AUTH", Location: models.Location{File: "src/shared/format.ts", Line: 3, Column: 14, EndLine: 3, EndColumn: 20}},
+		{Rule: "ui-no-db", Severity: config.SeverityError, Message: "UI не обращается к БД", Kind: models.ViolationModule,
+			Source: "src/ui/button.ts", Target: "src/db/client.ts", Location: models.Location{File: "src/ui/button.ts", Line: 1, Column: 24, EndLine: 1, EndColumn: 38}},
+		{Rule: "ui-no-db", Severity: config.SeverityError, Message: "UI не обращается к БД", Kind: models.ViolationConstant,
+			Source: "src/ui/button.ts#LABEL", Target: "src/db/client.ts#DB_URL", Location: models.Location{File: "src/ui/button.ts", Line: 3, Column: 14, EndLine: 3, EndColumn: 19}},
+	}
+
+	if len(report.Violations) != len(expected) {
+		t.Fatalf("Ожидается %d нарушений, получено: %+v", len(expected), report.Violations)
+	}
+	for i, violation := range report.Violations {
+		if violation != expected[i] {
+			t.Errorf("Нарушение %d: ожидается %+v, получено %+v", i, expected[i], violation)
+		}
+	}
+
+	if report.Rules != 2 || report.Errors != 2 || report.Warnings != 2 || !report.Failed() {
+		t.Errorf("Неверные итоги отчета: %+v", report)
+	}
+
+	// Без правил нарушений нет
+	report, err = ds.CheckRules(nil)
+	if err != nil || report.Failed() || len(report.Violations) != 0 {
+		t.Errorf("Без правил ожидается пустой отчет, получено: %+v (%v)", report, err)
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"sort"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+// statsTopConstants ограничивает список самых используемых констант в статистике
+const statsTopConstants = 10
+
+// Stats возвращает сводные показатели графа зависимостей
+func (ds *DependencyService) Stats() models.GraphStats {
+	cycles := ds.FindCycles()
+	_, links := ds.moduleLinks()
+	reachable := ds.reachableFiles(links)
+
+	ds.GraphMutex.RLock()
+	defer ds.GraphMutex.RUnlock()
+
+	stats := models.GraphStats{
+		Files:              len(ds.modules),
+		Constants:          len(ds.Graph.Nodes),
+		Dependencies:       len(ds.Graph.Edges),
+		ConstantsByType:    make(map[string]int),
+		SymbolsByKind:      make(map[string]int),
+		DependenciesByKind: make(map[string]int),
+		ConstantCycles:     len(cycles.Constants),
+		ModuleCycles:       len(cycles.Modules),
+		MostDependedOn:     []models.ConstantDegree{},
+		UnreachableFiles:   []string{},
+	}
+
+	if reachable != nil {
+		for file := range ds.modules {
+			if !reachable[file] {
+				stats.UnreachableFiles = append(stats.UnreachableFiles, ds.relativePath(file))
+			}
+		}
+		sort.Strings(stats.UnreachableFiles)
+	}
+
+	for _, node := range ds.Graph.Nodes {
+		stats.ConstantsByType[node.Type]++
+		stats.SymbolsByKind[node.Kind]++
+	}
+
+	dependents := make(map[string]int)
+	for _, edge := range ds.Graph.Edges {
+		stats.DependenciesByKind[edge.Kind]++
+		dependents[edge.Target]++
+	}
+
+	for id, count := range dependents {
+		stats.MostDependedOn = append(stats.MostDependedOn, models.ConstantDegree{ID: id, Dependents: count})
+	}
+	sort.Slice(stats.MostDependedOn, func(i, j int) bool {
+		a, b := stats.MostDependedOn[i], stats.MostDependedOn[j]
+		if a.Dependents != b.Dependents {
+			return a.Dependents > b.Dependents
+		}
+		return a.ID < b.ID
+	})
+	if len(stats.MostDependedOn) > statsTopConstants {
+		stats.MostDependedOn = stats.MostDependedOn[:statsTopConstants]
+	}
+
+	return stats
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"os"
+	"testing"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+func TestStats(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "stats-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"config.ts": `export const API_URL = 'https://example.com';
+export const TIMEOUT = 1000;
+export const HEALTH_URL = API_URL + '/health';`,
+		"api.ts": `import { API_URL, TIMEOUT } from './config';
+export const ENDPOINT = API_URL + '/v1';
+export const RETRY = TIMEOUT * 2;`,
+		"empty.ts": `// без констант`,
+	})
+
+	ds := NewDependencyService(NewFileService(tempDir, nil))
+	ds.BuildDependencyGraph()
+
+	stats := ds.Stats()
+
+	if stats.Files != 3 {
+		t.Errorf("Ожидается 3 файла, получено: %d", stats.Files)
+	}
+	if stats.Constants != len(ds.Graph.Nodes) || stats.Dependencies != len(ds.Graph.Edges) {
+		t.Errorf("Количество констант и зависимостей не совпадает с графом: %+v", stats)
+	}
+	if stats.DependenciesByKind[models.DependencyImport] != 2 {
+		t.Errorf("Ожидается 2 зависимости через импорт, получено: %v", stats.DependenciesByKind)
+	}
+	if len(stats.UnreachableFiles) != 0 {
+		t.Errorf("Без точек входа недостижимых файлов нет, получено: %v", stats.UnreachableFiles)
+	}
+	if stats.ModuleCycles != 0 || stats.ConstantCycles != 0 {
+		t.Errorf("Циклы не ожидаются: %+v", stats)
+	}
+
+	// API_URL используется дважды (ENDPOINT и HEALTH_URL), TIMEOUT - один раз
+	if len(stats.MostDependedOn) < 2 {
+		t.Fatalf("Ожидается не менее 2 используемых констант, получено: %+v", stats.MostDependedOn)
+	}
+	top := stats.MostDependedOn[0]
+	if top.ID != "config.ts#API_URL" || top.Dependents != 2 {
+		t.Errorf("Ожидается config.ts#API_URL с 2 зависимыми, получено: %+v", top)
+	}
+	if stats.MostDependedOn[1].ID != "config.ts#TIMEOUT" {
+		t.Errorf("Ожидается config.ts#TIMEOUT второй, получено: %+v", stats.MostDependedOn[1])
+	}
+}
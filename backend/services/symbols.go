@@ -0,0 +1,290 @@
+package services
+
+import "github.com/avor0n/dependency-graph-visualizer/models"
+
+// Разбор объявлений функций, классов, перечислений, интерфейсов и псевдонимов
+// типов верхнего уровня. Значением функции и класса считается заголовок
+// объявления без тела, ссылки собираются из всего объявления.
+
+// newDeclaration создает символ kind с именем name и значением tokens[from:to]
+func newDeclaration(src string, tokens []Token, kind string, name Token, from, to int) declaration {
+	decl := declaration{
+		Name:      name.Value,
+		Kind:      kind,
+		Type:      kind,
+		Line:      name.Line,
+		NameStart: tokenStart(name),
+		NameEnd:   tokenEnd(name),
+	}
+	decl.ValueStart, decl.ValueEnd = decl.NameEnd, decl.NameEnd
+	if to > from {
+		first, last := tokens[from], tokens[to-1]
+		decl.Value = src[first.Start:last.End]
+		decl.ValueStart, decl.ValueEnd = tokenStart(first), tokenEnd(last)
+	}
+	return decl
+}
+
+// parseFunction разбирает объявление function NAME() {}, ключевое слово function
+// находится на индексе j, объявление (с async) начинается с индекса start.
+// Перегрузки и объявления без тела (declare function) пропускаются.
+func parseFunction(src string, tokens []Token, start, j int) ([]declaration, int) {
+	k := j + 1
+	if k < len(tokens) && tokens[k].Is(TokenPunctuator, "*") {
+		k++
+	}
+	if k >= len(tokens) || tokens[k].Type != TokenIdentifier {
+		// Анонимная функция: export default function () {}
+		return nil, start
+	}
+
+	body := bodyStart(tokens, k+1, true)
+	if body < 0 {
+		return nil, start
+	}
+	end := matchingBracket(tokens, body) + 1
+
+	decl := newDeclaration(src, tokens, models.SymbolFunction, tokens[k], start, body)
+	decl.Refs, decl.Members = collectReferences(tokens[k+1 : end])
+	return []declaration{decl}, end
+}
+
+// parseClass разбирает объявление class NAME extends Base {}, ключевое слово class
+// находится на индексе j, объявление (с abstract) начинается с индекса start
+func parseClass(src string, tokens []Token, start, j int) ([]declaration, int) {
+	k := j + 1
+	if k >= len(tokens) || tokens[k].Type != TokenIdentifier ||
+		tokens[k].Is(TokenIdentifier, "extends") || tokens[k].Is(TokenIdentifier, "implements") {
+		return nil, start
+	}
+
+	body := bodyStart(tokens, k+1, false)
+	if body < 0 {
+		return nil, start
+	}
+	end := matchingBracket(tokens, body) + 1
+
+	decl := newDeclaration(src, tokens, models.SymbolClass, tokens[k], start, body)
+	decl.Refs, decl.Members = collectReferences(tokens[j:end])
+	return []declaration{decl}, end
+}
+
+// parseEnum разбирает перечисление enum NAME { A, B = VALUE }, начинающееся
+// с ключевого слова enum на индексе j. Ссылками считаются только значения членов.
+func parseEnum(src string, tokens []Token, j int) ([]declaration, int) {
+	if j+2 >= len(tokens) || tokens[j+1].Type != TokenIdentifier || !tokens[j+2].Is(TokenPunctuator, "{") {
+		return nil, j
+	}
+	end := matchingBracket(tokens, j+2) + 1
+
+	var values []Token
+	for k := j + 3; k < end-1; k++ {
+		if tokens[k].Is(TokenPunctuator, "=") {
+			valueEnd := initializerEnd(tokens, k+1)
+			values = append(values, tokens[k+1:valueEnd]...)
+			k = valueEnd - 1
+		}
+	}
+
+	decl := newDeclaration(src, tokens, models.SymbolEnum, tokens[j+1], j+2, end)
+	decl.Refs, decl.Members = collectReferences(values)
+	return []declaration{decl}, end
+}
+
+// parseInterface разбирает объявление interface NAME<T> extends Base {},
+// начинающееся с ключевого слова interface на индексе j
+func parseInterface(src string, tokens []Token, j int) ([]declaration, int) {
+	body := bodyStart(tokens, j+2, false)
+	if body < 0 {
+		return nil, j
+	}
+	end := matchingBracket(tokens, body) + 1
+
+	decl := newDeclaration(src, tokens, models.SymbolInterface, tokens[j+1], j+2, end)
+	decl.Refs, decl.Members = collectReferences(tokens[j+2 : end])
+	return []declaration{decl}, end
+}
+
+// parseTypeAlias разбирает псевдоним типа type NAME<T> = ..., начинающийся
+// с ключевого слова type на индексе j
+func parseTypeAlias(src string, tokens []Token, j int) ([]declaration, int) {
+	k := j + 2
+	if tokens[k].Is(TokenPunctuator, "<") {
+		k = matchingAngle(tokens, k) + 1
+	}
+	if k >= len(tokens) || !tokens[k].Is(TokenPunctuator, "=") {
+		return nil, j
+	}
+	end := typeAliasEnd(tokens, k+1)
+
+	decl := newDeclaration(src, tokens, models.SymbolType, tokens[j+1], k+1, end)
+	decl.Refs, decl.Members = collectReferences(tokens[j+2 : end])
+	if end < len(tokens) && tokens[end].Is(TokenPunctuator, ";") {
+		end++
+	}
+	return []declaration{decl}, end
+}
+
+// bodyStart возвращает индекс "{", открывающей тело функции, класса или интерфейса,
+// начиная поиск с индекса i после имени. Параметры и аргументы пропускаются целиком,
+// а угловые скобки считаются только там, где начинается тип: сразу после имени,
+// в аннотации после ":" и после extends/implements. Поэтому операторы сравнения
+// и сдвига в значениях по умолчанию не мешают найти тело. Фигурные скобки объектных
+// типов в аннотациях пропускаются. Возвращает -1, если объявление закончилось без тела:
+// на ";" или, при stopAtNewline, на новой инструкции.
+func bodyStart(tokens []Token, i int, stopAtNewline bool) int {
+	start := i
+	angles := 0
+	inType := false
+	for ; i < len(tokens); i++ {
+		token := tokens[i]
+		if token.Type != TokenPunctuator {
+			if stopAtNewline && angles == 0 && token.NewlineBefore && !typeContinues(tokens[i-1], token) {
+				return -1
+			}
+			if token.Is(TokenIdentifier, "extends") || token.Is(TokenIdentifier, "implements") {
+				inType = true
+			}
+			continue
+		}
+
+		switch token.Value {
+		case "(", "[":
+			i = matchingBracket(tokens, i)
+		case "<":
+			if i == start || inType || angles > 0 {
+				angles++
+			}
+		case ">", ">>", ">>>":
+			angles -= len(token.Value)
+			if angles < 0 {
+				angles = 0
+			}
+		case ":":
+			if angles == 0 {
+				inType = true
+			}
+		case "{":
+			if angles == 0 && !typeContinues(tokens[i-1], token) {
+				return i
+			}
+			// Объектный тип в аннотации: (): { a: string } {
+			i = matchingBracket(tokens, i)
+		case ";":
+			if angles == 0 {
+				return -1
+			}
+		}
+	}
+	return -1
+}
+
+// matchingBracket возвращает индекс скобки, закрывающей скобку на индексе i
+func matchingBracket(tokens []Token, i int) int {
+	depth := 0
+	for ; i < len(tokens); i++ {
+		if tokens[i].Type != TokenPunctuator {
+			continue
+		}
+		switch tokens[i].Value {
+		case "{", "(", "[":
+			depth++
+		case "}", ")", "]":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+// matchingAngle возвращает индекс ">", закрывающей список параметров типа на индексе i
+func matchingAngle(tokens []Token, i int) int {
+	depth := 0
+	for ; i < len(tokens); i++ {
+		if tokens[i].Type != TokenPunctuator {
+			continue
+		}
+		switch tokens[i].Value {
+		case "<":
+			depth++
+		case ">":
+			depth--
+		case ">>":
+			depth -= 2
+		case ">>>":
+			depth -= 3
+		}
+		if depth <= 0 {
+			return i
+		}
+	}
+	return len(tokens) - 1
+}
+
+// typeAliasEnd возвращает индекс лексемы, завершающей тип в псевдониме типа
+func typeAliasEnd(tokens []Token, i int) int {
+	depth := 0
+	for k := i; k < len(tokens); k++ {
+		token := tokens[k]
+		if depth == 0 && k > i && token.NewlineBefore && !typeContinues(tokens[k-1], token) {
+			return k
+		}
+		if token.Type != TokenPunctuator {
+			continue
+		}
+		switch token.Value {
+		case "{", "(", "[", "<":
+			depth++
+		case "}", ")", "]", ">":
+			if depth == 0 {
+				return k
+			}
+			depth--
+		case ">>":
+			depth -= 2
+		case ">>>":
+			depth -= 3
+		case ";":
+			if depth == 0 {
+				return k
+			}
+		}
+	}
+	return len(tokens)
+}
+
+// typeContinuationWords содержит слова, после которых тип продолжается
+var typeContinuationWords = map[string]bool{
+	"keyof": true, "typeof": true, "extends": true, "implements": true, "infer": true,
+	"is": true, "as": true, "readonly": true, "unique": true, "asserts": true,
+}
+
+// typeContinues проверяет, продолжает ли лексема next тип или заголовок объявления,
+// завершившийся лексемой prev: "A |\n B", "extends\n Base", "A\n & B"
+func typeContinues(prev, next Token) bool {
+	switch prev.Type {
+	case TokenPunctuator:
+		switch prev.Value {
+		case ")", "]", "}", ">", ">>", ">>>":
+		default:
+			return true
+		}
+	case TokenIdentifier:
+		if typeContinuationWords[prev.Value] {
+			return true
+		}
+	}
+
+	switch {
+	case next.Type == TokenPunctuator:
+		switch next.Value {
+		case "|", "&", "?", ":", ".", "[", "=>", "<":
+			return true
+		}
+	case next.Type == TokenIdentifier:
+		return typeContinuationWords[next.Value]
+	}
+	return false
+}
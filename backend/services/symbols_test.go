@@ -0,0 +1,210 @@
+package services
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/avor0n/dependency-graph-visualizer/models"
+)
+
+func TestExtractSymbols(t *testing.T) {
+	src := `const API_URL = '/api';
+let counter = 0;
+var legacy;
+export async function fetchUsers(limit = PAGE_SIZE): Promise<User[]> {
+  const url = API_URL + '/users';
+  return request(url, { limit });
+}
+function format(value: string): string;
+function format(value) {
+  return value;
+}
+export default class UserService extends BaseService {
+  timeout = TIMEOUT;
+  load(id) {
+    return fetchUsers(id);
+  }
+}
+export enum Role { Admin = ROLES.admin, Guest }
+const enum Flag { On, Off }
+export interface User extends Entity {
+  role: Role;
+  name?: string;
+}
+type UserMap<K extends string = string> = Record<K, User>
+type Handler = (event: AppEvent) => void;
+const render = (API_URL) => API_URL;
+const Component = class {};
+`
+
+	tokens, err := Tokenize(src, false)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка лексера: %v", err)
+	}
+
+	expected := []struct {
+		name  string
+		kind  string
+		value string
+		refs  []string
+	}{
+		{"API_URL", models.SymbolConst, "'/api'", nil},
+		{"counter", models.SymbolLet, "0", nil},
+		{"legacy", models.SymbolVar, "", nil},
+		{"fetchUsers", models.SymbolFunction, "async function fetchUsers(limit = PAGE_SIZE): Promise<User[]>",
+			[]string{"PAGE_SIZE", "Promise", "User", "API_URL", "request"}},
+		// Перегрузка без тела пропускается, символ создается один раз
+		{"format", models.SymbolFunction, "function format(value)", nil},
+		{"UserService", models.SymbolClass, "class UserService extends BaseService",
+			[]string{"BaseService", "TIMEOUT", "fetchUsers"}},
+		{"Role", models.SymbolEnum, "{ Admin = ROLES.admin, Guest }", []string{"ROLES"}},
+		{"Flag", models.SymbolEnum, "{ On, Off }", nil},
+		{"User", models.SymbolInterface, "extends Entity {\n  role: Role;\n  name?: string;\n}", []string{"Entity", "Role", "string"}},
+		{"UserMap", models.SymbolType, "Record<K, User>", []string{"K", "string", "Record", "User"}},
+		{"Handler", models.SymbolType, "(event: AppEvent) => void", []string{"AppEvent"}},
+		// Параметр затеняет константу верхнего уровня
+		{"render", models.SymbolFunction, "(API_URL) => API_URL", nil},
+		{"Component", models.SymbolClass, "class {}", nil},
+	}
+
+	declarations := extractDeclarations(src, tokens)
+	if len(declarations) != len(expected) {
+		names := make([]string, 0, len(declarations))
+		for _, decl := range declarations {
+			names = append(names, decl.Name)
+		}
+		t.Fatalf("Ожидается %d символов, получено: %v", len(expected), names)
+	}
+
+	for i, exp := range expected {
+		decl := declarations[i]
+		if decl.Name != exp.name || decl.Kind != exp.kind || decl.Value != exp.value {
+			t.Errorf("Символ %d: ожидается %s %s = %q, получено: %s %s = %q",
+				i, exp.kind, exp.name, exp.value, decl.Kind, decl.Name, decl.Value)
+		}
+		if !reflect.DeepEqual(decl.Refs, exp.refs) {
+			t.Errorf("Символ %s: ожидаются ссылки %v, получено: %v", exp.name, exp.refs, decl.Refs)
+		}
+	}
+}
+
+func TestLocalNames(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected []string
+	}{
+		{"(a, { b: c, d = X }, [e], ...f) => a", []string{"a", "c", "d", "e", "f"}},
+		{"x => x * FACTOR", []string{"x"}},
+		{"function (opts: Options = DEFAULTS) { let y; try {} catch (err) {} }", []string{"opts", "y", "err"}},
+		{"({ get(id: string): User { return id; }, load: (key) => CACHE[key] })", []string{"id", "key"}},
+		// Условия и вызовы в тернарном операторе не объявляют параметры
+		{"if (DEBUG) { log(LEVEL) }", nil},
+		{"READY ? format(CONFIG) : fallback(DEFAULTS)", nil},
+	}
+
+	for _, test := range tests {
+		tokens, err := Tokenize(test.src, false)
+		if err != nil {
+			t.Fatalf("Неожиданная ошибка лексера для %q: %v", test.src, err)
+		}
+		locals := localNames(tokens)
+		if len(locals) != len(test.expected) {
+			t.Errorf("%q: ожидаются локальные имена %v, получено: %v", test.src, test.expected, locals)
+			continue
+		}
+		for _, name := range test.expected {
+			if !locals[name] {
+				t.Errorf("%q: имя %s не найдено среди локальных: %v", test.src, name, locals)
+			}
+		}
+	}
+}
+
+func TestSymbolDependencies(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "symbols-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"config.ts": "export const API_URL = '/api';\nexport const THEME = 'dark';\nexport type Theme = typeof THEME;",
+		"App.tsx": `import { API_URL, THEME, type Theme } from './config';
+export function Header({ title }: { title: string }) {
+  return <h1 className={THEME}>{title}</h1>;
+}
+export const App = () => <Header title={API_URL} />;
+export class Api {
+  base: Theme = THEME;
+}`,
+	})
+
+	ds := NewDependencyService(NewFileService(tempDir, nil))
+	ds.BuildDependencyGraph()
+
+	kinds := make(map[string]string)
+	for _, node := range ds.Graph.Nodes {
+		kinds[node.ID] = node.Kind
+	}
+	expectedKinds := map[string]string{
+		"config.ts#API_URL": models.SymbolConst,
+		"config.ts#THEME":   models.SymbolConst,
+		"config.ts#Theme":   models.SymbolType,
+		"App.tsx#Header":    models.SymbolFunction,
+		"App.tsx#App":       models.SymbolFunction,
+		"App.tsx#Api":       models.SymbolClass,
+	}
+	if !reflect.DeepEqual(kinds, expectedKinds) {
+		t.Errorf("Неверные символы:\nожидается %v\nполучено   %v", expectedKinds, kinds)
+	}
+
+	var edges []string
+	for _, edge := range ds.Graph.Edges {
+		edges = append(edges, edge.Source+" -> "+edge.Target)
+	}
+	sort.Strings(edges)
+	expectedEdges := []string{
+		"App.tsx#Api -> config.ts#THEME",
+		"App.tsx#Api -> config.ts#Theme",
+		"App.tsx#App -> App.tsx#Header",
+		"App.tsx#App -> config.ts#API_URL",
+		"App.tsx#Header -> config.ts#THEME",
+		"config.ts#Theme -> config.ts#THEME",
+	}
+	if !reflect.DeepEqual(edges, expectedEdges) {
+		t.Errorf("Неверные зависимости:\nожидается %v\nполучено   %v", expectedEdges, edges)
+	}
+}
+
+func TestFunctionParameterOperators(t *testing.T) {
+	// Операторы сравнения и сдвига в значениях по умолчанию не являются скобками типа
+	src := `function check(a = 1 < 2, b = LIMIT) {
+  return a && b;
+}
+function shift(x = 8 >> 1) { return LIMIT; }
+function generic<T extends Array<number>>(items: T = DEFAULTS as T): Map<string, T> { return CACHE; }
+`
+
+	tokens, err := Tokenize(src, false)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка лексера: %v", err)
+	}
+
+	expected := map[string][]string{
+		"check":   {"LIMIT"},
+		"shift":   {"LIMIT"},
+		"generic": {"T", "Array", "number", "DEFAULTS", "Map", "string", "CACHE"},
+	}
+
+	declarations := extractDeclarations(src, tokens)
+	if len(declarations) != len(expected) {
+		t.Fatalf("Ожидается %d функции, получено: %+v", len(expected), declarations)
+	}
+	for _, decl := range declarations {
+		if decl.Kind != models.SymbolFunction || !reflect.DeepEqual(decl.Refs, expected[decl.Name]) {
+			t.Errorf("Функция %s: ожидаются ссылки %v, получено: %s %v", decl.Name, expected[decl.Name], decl.Kind, decl.Refs)
+		}
+	}
+}
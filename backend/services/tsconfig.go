@@ -0,0 +1,284 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// tsConfigNames содержит имена файлов конфигурации в порядке приоритета
+var tsConfigNames = []string{"tsconfig.json", "jsconfig.json"}
+
+// TSConfig содержит параметры разрешения модулей из tsconfig.json или jsconfig.json
+// с учетом цепочки extends
+type TSConfig struct {
+	Path     string              // Путь к файлу конфигурации
+	BaseURL  string              // Абсолютный путь compilerOptions.baseUrl (пусто, если не задан)
+	Paths    map[string][]string // Сопоставления compilerOptions.paths
+	PathsDir string              // Директория конфигурации, в которой объявлены paths
+}
+
+// rawTSConfig описывает интересующую нас часть файла конфигурации
+type rawTSConfig struct {
+	Extends         json.RawMessage `json:"extends"`
+	CompilerOptions struct {
+		BaseURL *string             `json:"baseUrl"`
+		Paths   map[string][]string `json:"paths"`
+	} `json:"compilerOptions"`
+}
+
+// LoadTSConfig загружает файл конфигурации TypeScript, следуя по цепочке extends
+func LoadTSConfig(path string) (*TSConfig, error) {
+	return loadTSConfig(path, make(map[string]bool))
+}
+
+func loadTSConfig(path string, visited map[string]bool) (*TSConfig, error) {
+	path = filepath.Clean(path)
+	if visited[path] {
+		return nil, fmt.Errorf("circular extends in %s", path)
+	}
+	visited[path] = true
+	defer delete(visited, path)
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw rawTSConfig
+	if err := json.Unmarshal(stripJSONComments(content), &raw); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	config := &TSConfig{Path: path}
+	dir := filepath.Dir(path)
+
+	// Сначала применяем родительские конфигурации, затем переопределяем их своими параметрами
+	parents, err := parseExtends(raw.Extends)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for _, parent := range parents {
+		parentPath, ok := resolveExtendsPath(dir, parent)
+		if !ok {
+			return nil, fmt.Errorf("%s: cannot find base config %q", path, parent)
+		}
+		base, err := loadTSConfig(parentPath, visited)
+		if err != nil {
+			return nil, err
+		}
+		if base.BaseURL != "" {
+			config.BaseURL = base.BaseURL
+		}
+		if base.Paths != nil {
+			config.Paths = base.Paths
+			config.PathsDir = base.PathsDir
+		}
+	}
+
+	// Пути в конфигурации разрешаются относительно файла, в котором они объявлены
+	if raw.CompilerOptions.BaseURL != nil {
+		config.BaseURL = filepath.Join(dir, filepath.FromSlash(*raw.CompilerOptions.BaseURL))
+	}
+	if raw.CompilerOptions.Paths != nil {
+		config.Paths = raw.CompilerOptions.Paths
+		config.PathsDir = dir
+	}
+
+	return config, nil
+}
+
+// parseExtends разбирает значение extends: строку или массив строк (TypeScript 5.0)
+func parseExtends(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return []string{single}, nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, fmt.Errorf("extends must be a string or an array of strings")
+	}
+	return list, nil
+}
+
+// resolveExtendsPath находит файл базовой конфигурации: по относительному пути
+// или в пакете из node_modules
+func resolveExtendsPath(dir, extends string) (string, bool) {
+	var candidates []string
+	if isRelativeSpecifier(extends) {
+		base := extends
+		if !filepath.IsAbs(base) {
+			base = filepath.Join(dir, filepath.FromSlash(extends))
+		}
+		candidates = []string{base, base + ".json"}
+	} else {
+		for current := dir; ; current = filepath.Dir(current) {
+			pkg := filepath.Join(current, "node_modules", filepath.FromSlash(extends))
+			candidates = append(candidates, pkg, pkg+".json", filepath.Join(pkg, "tsconfig.json"))
+			if filepath.Dir(current) == current {
+				break
+			}
+		}
+	}
+
+	for _, candidate := range candidates {
+		if isFile(candidate) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// ResolveNonRelative разрешает нерелятивный спецификатор через paths и baseUrl
+// в файл с одним из расширений extensions
+func (c *TSConfig) ResolveNonRelative(specifier string, extensions []string) (string, bool) {
+	return c.resolveNonRelative(specifier, func(base string) (string, bool) {
+		return probeModulePath(base, extensions)
+	})
+}
+
+// resolveNonRelative разрешает спецификатор через paths и baseUrl, подбирая
+// файл по каждому пути-кандидату функцией probe
+func (c *TSConfig) resolveNonRelative(specifier string, probe func(base string) (string, bool)) (string, bool) {
+	if c.Paths != nil {
+		pathsBase := c.BaseURL
+		if pathsBase == "" {
+			pathsBase = c.PathsDir
+		}
+
+		if pattern, matched, ok := matchPathsPattern(c.Paths, specifier); ok {
+			for _, substitution := range c.Paths[pattern] {
+				target := strings.Replace(substitution, "*", matched, 1)
+				if resolved, ok := probe(filepath.Join(pathsBase, filepath.FromSlash(target))); ok {
+					return resolved, true
+				}
+			}
+		}
+	}
+
+	if c.BaseURL != "" {
+		return probe(filepath.Join(c.BaseURL, filepath.FromSlash(specifier)))
+	}
+
+	return "", false
+}
+
+// matchPathsPattern выбирает шаблон paths так же, как tsc: точное совпадение,
+// иначе шаблон со звездочкой с самым длинным префиксом. Возвращает шаблон
+// и часть спецификатора, совпавшую со звездочкой.
+func matchPathsPattern(paths map[string][]string, specifier string) (string, string, bool) {
+	if _, ok := paths[specifier]; ok && !strings.Contains(specifier, "*") {
+		return specifier, "", true
+	}
+
+	best, matched := "", ""
+	bestPrefix := -1
+	for pattern := range paths {
+		star := strings.Index(pattern, "*")
+		if star < 0 {
+			continue
+		}
+		prefix, suffix := pattern[:star], pattern[star+1:]
+		if len(specifier) < len(prefix)+len(suffix) ||
+			!strings.HasPrefix(specifier, prefix) || !strings.HasSuffix(specifier, suffix) {
+			continue
+		}
+		if len(prefix) > bestPrefix {
+			best, bestPrefix = pattern, len(prefix)
+			matched = specifier[len(prefix) : len(specifier)-len(suffix)]
+		}
+	}
+
+	return best, matched, bestPrefix >= 0
+}
+
+// stripJSONComments удаляет из JSON комментарии и висячие запятые,
+// которые допускаются в tsconfig.json
+func stripJSONComments(data []byte) []byte {
+	return stripTrailingCommas(stripComments(data))
+}
+
+// stripComments удаляет комментарии // и /* */ вне строк
+func stripComments(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if inString {
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i+1 < len(data) && data[i+1] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++
+			out = append(out, ' ')
+		default:
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+// stripTrailingCommas удаляет запятые перед закрывающими скобками вне строк
+func stripTrailingCommas(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if inString {
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		if c == '"' {
+			inString = true
+		} else if c == ',' {
+			j := i + 1
+			for j < len(data) && (data[j] == ' ' || data[j] == '\t' || data[j] == '\r' || data[j] == '\n') {
+				j++
+			}
+			if j < len(data) && (data[j] == '}' || data[j] == ']') {
+				continue
+			}
+		}
+		out = append(out, c)
+	}
+
+	return out
+}
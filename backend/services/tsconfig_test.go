@@ -0,0 +1,225 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestFiles создает файлы с заданным содержимым внутри директории dir
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Не удалось создать директорию: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Не удалось создать файл %s: %v", name, err)
+		}
+	}
+}
+
+func TestStripJSONComments(t *testing.T) {
+	input := `{
+  // комментарий
+  "a": "http://example.com", /* блок */
+  "b": ["x", "y",],
+  "c": "// не комментарий",
+}`
+	expected := `{
+  
+  "a": "http://example.com",  
+  "b": ["x", "y"],
+  "c": "// не комментарий"
+}`
+
+	if actual := string(stripJSONComments([]byte(input))); actual != expected {
+		t.Errorf("Ожидается:\n%s\nполучено:\n%s", expected, actual)
+	}
+}
+
+func TestLoadTSConfigExtends(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "tsconfig-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"node_modules/@org/tsconfig/tsconfig.json": `{
+  "compilerOptions": { "baseUrl": "." }
+}`,
+		"configs/base.json": `{
+  // Базовая конфигурация
+  "extends": "@org/tsconfig",
+  "compilerOptions": {
+    "baseUrl": "../src",
+    "paths": { "@app/*": ["app/*"], },
+  },
+}`,
+		"tsconfig.json": `{
+  "extends": ["./configs/base"],
+  "compilerOptions": { "strict": true }
+}`,
+	})
+
+	config, err := LoadTSConfig(filepath.Join(tempDir, "tsconfig.json"))
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	if expected := filepath.Join(tempDir, "src"); config.BaseURL != expected {
+		t.Errorf("Ожидается baseUrl=%s, получено: %s", expected, config.BaseURL)
+	}
+	if expected := filepath.Join(tempDir, "configs"); config.PathsDir != expected {
+		t.Errorf("Ожидается директория paths=%s, получено: %s", expected, config.PathsDir)
+	}
+	if len(config.Paths["@app/*"]) != 1 {
+		t.Errorf("Ожидается унаследованный шаблон @app/*, получено: %v", config.Paths)
+	}
+}
+
+func TestLoadTSConfigCircularExtends(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "tsconfig-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"a.json": `{ "extends": "./b.json" }`,
+		"b.json": `{ "extends": "./a.json" }`,
+	})
+
+	if _, err := LoadTSConfig(filepath.Join(tempDir, "a.json")); err == nil {
+		t.Errorf("Ожидается ошибка для циклической цепочки extends")
+	}
+}
+
+func TestMatchPathsPattern(t *testing.T) {
+	paths := map[string][]string{
+		"*":             {"*"},
+		"@app/*":        {"app/*"},
+		"@app/ui/*":     {"ui/*"},
+		"config":        {"config/index"},
+		"~/*.generated": {"gen/*"},
+	}
+
+	tests := []struct {
+		specifier string
+		pattern   string
+		matched   string
+	}{
+		{"config", "config", ""},
+		{"@app/store", "@app/*", "store"},
+		{"@app/ui/button", "@app/ui/*", "button"},
+		{"~/schema.generated", "~/*.generated", "schema"},
+		{"lodash", "*", "lodash"},
+	}
+
+	for _, test := range tests {
+		pattern, matched, ok := matchPathsPattern(paths, test.specifier)
+		if !ok || pattern != test.pattern || matched != test.matched {
+			t.Errorf("Для %q ожидается шаблон %q (%q), получено: %q (%q, ok=%v)",
+				test.specifier, test.pattern, test.matched, pattern, matched, ok)
+		}
+	}
+}
+
+func TestModuleResolverTSConfig(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "resolver-tsconfig-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"tsconfig.json": `{
+  "compilerOptions": {
+    "baseUrl": "src",
+    "paths": {
+      "@app/*": ["app/*", "generated/*"],
+      "@shared": ["../shared/index.ts"]
+    }
+  }
+}`,
+		"src/app/store.ts":          "",
+		"src/app/widgets/index.tsx": "",
+		"src/generated/api.d.ts":    "",
+		"src/lib/math.js":           "",
+		"src/main.ts":               "",
+		"shared/index.ts":           "",
+		"packages/ui/package.json":  `{ "types": "lib/main" }`,
+		"packages/ui/lib/main.d.ts": "",
+		// Вложенная конфигурация без paths перекрывает корневую
+		"tools/jsconfig.json": `{ "compilerOptions": { "baseUrl": "." } }`,
+		"tools/helpers.js":    "",
+		"tools/build.js":      "",
+	})
+
+	resolver := NewModuleResolver(tempDir)
+	main := filepath.Join(tempDir, "src", "main.ts")
+	build := filepath.Join(tempDir, "tools", "build.js")
+
+	tests := []struct {
+		from      string
+		specifier string
+		expected  string
+	}{
+		{main, "@app/store", "src/app/store.ts"},
+		{main, "@app/widgets", "src/app/widgets/index.tsx"},
+		{main, "@app/api", "src/generated/api.d.ts"},
+		{main, "@shared", "shared/index.ts"},
+		{main, "lib/math", "src/lib/math.js"},
+		{main, "../packages/ui", "packages/ui/lib/main.d.ts"},
+		{main, "react", ""},
+		{main, "@app/missing", ""},
+		{build, "helpers", "tools/helpers.js"},
+		{build, "@app/store", ""},
+	}
+
+	for _, test := range tests {
+		resolved, ok := resolver.Resolve(test.from, test.specifier)
+		if test.expected == "" {
+			if ok {
+				t.Errorf("Для %q не ожидается разрешение, получено: %s", test.specifier, resolved)
+			}
+			continue
+		}
+
+		expected := filepath.Join(tempDir, filepath.FromSlash(test.expected))
+		if !ok || resolved != expected {
+			t.Errorf("Для %q ожидается %s, получено: %s (ok=%v)", test.specifier, expected, resolved, ok)
+		}
+	}
+}
+
+func TestModuleResolverAliases(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "resolver-aliases-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"tsconfig.json":         `{ "compilerOptions": { "paths": { "@/*": ["lib/*"] } } }`,
+		"src/components/ui.tsx": "",
+		"lib/components/ui.ts":  "",
+		"lib/format.ts":         "",
+		"src/main.ts":           "",
+	})
+
+	resolver := NewModuleResolver(tempDir)
+	resolver.Aliases = map[string][]string{"@/*": {"src/*"}}
+	from := filepath.Join(tempDir, "src", "main.ts")
+
+	// Псевдонимы из конфигурации проекта важнее tsconfig.json
+	if resolved, ok := resolver.Resolve(from, "@/components/ui"); !ok || resolved != filepath.Join(tempDir, "src", "components", "ui.tsx") {
+		t.Errorf("Ожидается src/components/ui.tsx, получено %q (%v)", resolved, ok)
+	}
+	// Если по псевдониму файл не найден, используется tsconfig.json
+	if resolved, ok := resolver.Resolve(from, "@/format"); !ok || resolved != filepath.Join(tempDir, "lib", "format.ts") {
+		t.Errorf("Ожидается lib/format.ts, получено %q (%v)", resolved, ok)
+	}
+}
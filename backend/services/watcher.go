@@ -0,0 +1,179 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultWatchInterval задает период опроса файловой системы по умолчанию
+const DefaultWatchInterval = time.Second
+
+// resolutionConfigNames содержит имена файлов, от которых зависит разрешение импортов
+var resolutionConfigNames = append(append([]string{}, tsConfigNames...), "package.json")
+
+// FileChanges описывает изменения JS/TS файлов между двумя проверками
+type FileChanges struct {
+	Added    []string
+	Modified []string
+	Removed  []string
+	// Configs - добавленные, измененные и удаленные tsconfig.json, jsconfig.json
+	// и package.json в директориях с JS/TS файлами и их родительских директориях
+	Configs []string
+}
+
+// Empty проверяет, что изменений нет
+func (c FileChanges) Empty() bool {
+	return len(c.Added) == 0 && len(c.Modified) == 0 && len(c.Removed) == 0 && len(c.Configs) == 0
+}
+
+// Files возвращает все затронутые файлы
+func (c FileChanges) Files() []string {
+	files := make([]string, 0, len(c.Added)+len(c.Modified)+len(c.Removed))
+	files = append(files, c.Added...)
+	files = append(files, c.Modified...)
+	return append(files, c.Removed...)
+}
+
+// fileState содержит признаки, по которым определяется изменение файла
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watcher отслеживает добавление, изменение и удаление JS/TS файлов проекта,
+// периодически опрашивая файловую систему. Список файлов берется из FileService,
+// поэтому правила .gitignore соблюдаются. Кроме того, отслеживаются файлы
+// конфигурации, влияющие на разрешение импортов. Конфигурации, подключенные
+// через extends из других директорий, не отслеживаются.
+type Watcher struct {
+	FileService *FileService
+	Interval    time.Duration
+
+	files   map[string]fileState
+	configs map[string]fileState
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewWatcher создает новый экземпляр Watcher и запоминает текущее состояние файлов
+func NewWatcher(fileService *FileService, interval time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	w := &Watcher{
+		FileService: fileService,
+		Interval:    interval,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	w.files, w.configs = w.snapshot()
+	return w
+}
+
+// Start запускает опрос в отдельной горутине. Функция onChange вызывается
+// последовательно для каждого непустого набора изменений.
+func (w *Watcher) Start(onChange func(FileChanges)) {
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				if changes := w.Poll(); !changes.Empty() {
+					onChange(changes)
+				}
+			}
+		}
+	}()
+}
+
+// Stop останавливает опрос и дожидается завершения текущей проверки
+func (w *Watcher) Stop() {
+	w.once.Do(func() {
+		close(w.stop)
+		<-w.done
+	})
+}
+
+// Poll сравнивает текущее состояние файлов с предыдущим и возвращает изменения
+func (w *Watcher) Poll() FileChanges {
+	files, configs := w.snapshot()
+
+	var changes FileChanges
+	changes.Added, changes.Modified, changes.Removed = diffStates(w.files, files)
+
+	added, modified, removed := diffStates(w.configs, configs)
+	changes.Configs = append(append(added, modified...), removed...)
+	sort.Strings(changes.Configs)
+
+	w.files, w.configs = files, configs
+	return changes
+}
+
+// diffStates сравнивает два состояния файлов и возвращает отсортированные
+// списки добавленных, измененных и удаленных файлов
+func diffStates(previous, current map[string]fileState) (added, modified, removed []string) {
+	for path, state := range current {
+		old, ok := previous[path]
+		switch {
+		case !ok:
+			added = append(added, path)
+		case !old.modTime.Equal(state.modTime) || old.size != state.size:
+			modified = append(modified, path)
+		}
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			removed = append(removed, path)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(modified)
+	sort.Strings(removed)
+	return added, modified, removed
+}
+
+// snapshot возвращает состояние всех JS/TS файлов проекта и файлов конфигурации
+// в их директориях и родительских директориях в пределах проекта
+func (w *Watcher) snapshot() (map[string]fileState, map[string]fileState) {
+	files := make(map[string]fileState)
+	dirs := make(map[string]bool)
+	for _, path := range w.FileService.GetJSTSFiles() {
+		if state, ok := statFile(path); ok {
+			files[path] = state
+		}
+		for dir := filepath.Dir(path); !dirs[dir] && isWithin(w.FileService.ProjectPath, dir); dir = filepath.Dir(dir) {
+			dirs[dir] = true
+		}
+	}
+
+	configs := make(map[string]fileState)
+	for dir := range dirs {
+		for _, name := range resolutionConfigNames {
+			path := filepath.Join(dir, name)
+			if state, ok := statFile(path); ok {
+				configs[path] = state
+			}
+		}
+	}
+	return files, configs
+}
+
+// statFile возвращает состояние файла path
+func statFile(path string) (fileState, bool) {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return fileState{}, false
+	}
+	return fileState{modTime: info.ModTime(), size: info.Size()}, true
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/avor0n/dependency-graph-visualizer/utils"
+)
+
+func TestWatcherPoll(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "watcher-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		".gitignore":     "generated/\n",
+		"a.ts":           "export const A = 1;",
+		"b.ts":           "export const B = 2;",
+		"styles.css":     "",
+		"generated/x.ts": "",
+	})
+
+	fileService := NewFileService(tempDir, utils.LoadGitIgnore(tempDir))
+	watcher := NewWatcher(fileService, time.Hour)
+
+	if changes := watcher.Poll(); !changes.Empty() {
+		t.Errorf("Ожидается отсутствие изменений, получено: %+v", changes)
+	}
+
+	// Изменяем, добавляем и удаляем файлы, в том числе игнорируемые
+	writeTestFiles(t, tempDir, map[string]string{
+		"a.ts":           "export const A = 100;",
+		"c.tsx":          "export const C = 3;",
+		"styles.css":     "body {}",
+		"generated/y.ts": "",
+	})
+	if err := os.Remove(filepath.Join(tempDir, "b.ts")); err != nil {
+		t.Fatalf("Не удалось удалить файл: %v", err)
+	}
+
+	changes := watcher.Poll()
+	expected := FileChanges{
+		Added:    []string{filepath.Join(tempDir, "c.tsx")},
+		Modified: []string{filepath.Join(tempDir, "a.ts")},
+		Removed:  []string{filepath.Join(tempDir, "b.ts")},
+	}
+	if !equalStrings(changes.Added, expected.Added) ||
+		!equalStrings(changes.Modified, expected.Modified) ||
+		!equalStrings(changes.Removed, expected.Removed) {
+		t.Errorf("Ожидается %+v, получено: %+v", expected, changes)
+	}
+
+	if changes := watcher.Poll(); !changes.Empty() {
+		t.Errorf("Повторная проверка не должна находить изменений, получено: %+v", changes)
+	}
+}
+
+func TestWatcherStart(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "watcher-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	watcher := NewWatcher(NewFileService(tempDir, nil), 10*time.Millisecond)
+	received := make(chan FileChanges, 1)
+	watcher.Start(func(changes FileChanges) {
+		select {
+		case received <- changes:
+		default:
+		}
+	})
+	defer watcher.Stop()
+
+	writeTestFiles(t, tempDir, map[string]string{"new.js": "const X = 1;"})
+
+	select {
+	case changes := <-received:
+		if len(changes.Added) != 1 {
+			t.Errorf("Ожидается один добавленный файл, получено: %+v", changes)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Изменения не обнаружены")
+	}
+}
@@ -2,139 +2,328 @@ package utils
 
 import (
 	"bufio"
+	"io"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
-// GitIgnore представляет правила игнорирования файлов
+// GitIgnore представляет правила игнорирования файлов. Как и git, учитывает
+// core.excludesFile, .git/info/exclude и файлы .gitignore во всех директориях;
+// вложенные .gitignore загружаются при первой проверке пути внутри их директории.
 type GitIgnore struct {
-	patterns []string
+	// root - корень репозитория (или проекта вне репозитория); пустой
+	// root отключает чтение файлов
+	root string
+	// prefix - путь проекта относительно root
+	prefix []string
+	// global - правила core.excludesFile и .git/info/exclude
+	global []gitIgnorePattern
+
+	mutex sync.Mutex
+	// dirs - правила .gitignore по директориям относительно root
+	dirs map[string][]gitIgnorePattern
+}
+
+// gitIgnorePattern - разобранное правило .gitignore
+type gitIgnorePattern struct {
+	// negate - правило начинается с ! и возвращает ранее исключенный путь
+	negate bool
+	// dirOnly - правило заканчивается на / и применяется только к директориям
+	dirOnly bool
+	// segments - части шаблона между символами /. Шаблон без / в начале
+	// или середине применяется на любом уровне и начинается с **.
+	segments []string
 }
 
-// LoadGitIgnore загружает правила .gitignore из директории проекта
+// LoadGitIgnore загружает правила игнорирования для проекта. Если проект
+// находится в git-репозитории, учитываются также .gitignore родительских
+// директорий до корня репозитория, .git/info/exclude и core.excludesFile.
+// Всегда возвращает непустой GitIgnore: вложенные .gitignore могут
+// существовать и без корневого.
 func LoadGitIgnore(projectPath string) *GitIgnore {
-	gitIgnorePath := filepath.Join(projectPath, ".gitignore")
+	gitIgnore := &GitIgnore{
+		root: projectPath,
+		dirs: make(map[string][]gitIgnorePattern),
+	}
+
+	if repo, ok := findGitRepo(projectPath); ok {
+		gitIgnore.root = repo.root
+		if rel, err := filepath.Rel(repo.root, projectPath); err == nil && rel != "." {
+			gitIgnore.prefix = strings.Split(filepath.ToSlash(rel), "/")
+		}
+
+		for _, path := range []string{excludesFile(repo.commonDir), filepath.Join(repo.commonDir, "info", "exclude")} {
+			if path == "" {
+				continue
+			}
+			if patterns, ok := readPatterns(path); ok {
+				log.Printf("Загружено %d правил из %s\n", len(patterns), path)
+				gitIgnore.global = append(gitIgnore.global, patterns...)
+			}
+		}
+	}
 
-	// Проверяем, существует ли файл .gitignore
-	if _, err := os.Stat(gitIgnorePath); os.IsNotExist(err) {
-		log.Println("Файл .gitignore не найден, игнорирование файлов не будет применяться")
-		return nil
+	// Загружаем .gitignore корня проекта и его родительских директорий в репозитории
+	for i := 0; i <= len(gitIgnore.prefix); i++ {
+		gitIgnore.dirPatterns(gitIgnore.prefix[:i])
+	}
+	if gitIgnore.dirs[strings.Join(gitIgnore.prefix, "/")] == nil {
+		log.Println("Файл .gitignore не найден в корне проекта")
 	}
 
-	// Открываем файл .gitignore
-	file, err := os.Open(gitIgnorePath)
+	return gitIgnore
+}
+
+// readPatterns читает правила из файла. Возвращает false, если файл отсутствует
+// или не может быть прочитан.
+func readPatterns(path string) ([]gitIgnorePattern, bool) {
+	file, err := os.Open(path)
 	if err != nil {
-		log.Printf("Ошибка при открытии файла .gitignore: %v\n", err)
-		return nil
+		if !os.IsNotExist(err) {
+			log.Printf("Ошибка при открытии файла %s: %v\n", path, err)
+		}
+		return nil, false
 	}
 	defer file.Close()
 
-	// Создаем объект GitIgnore
+	gitIgnore, err := parseGitIgnore(file)
+	if err != nil {
+		log.Printf("Ошибка при чтении файла %s: %v\n", path, err)
+		return nil, false
+	}
+	return gitIgnore.global, true
+}
+
+// dirPatterns возвращает правила .gitignore директории dir (относительно root),
+// загружая файл при первом обращении
+func (gi *GitIgnore) dirPatterns(dir []string) []gitIgnorePattern {
+	key := strings.Join(dir, "/")
+
+	gi.mutex.Lock()
+	defer gi.mutex.Unlock()
+
+	if patterns, ok := gi.dirs[key]; ok || gi.root == "" {
+		return patterns
+	}
+	if gi.dirs == nil {
+		gi.dirs = make(map[string][]gitIgnorePattern)
+	}
+
+	path := filepath.Join(gi.root, filepath.FromSlash(key), ".gitignore")
+	patterns, ok := readPatterns(path)
+	if ok {
+		log.Printf("Загружено %d правил из %s\n", len(patterns), path)
+	}
+	// Отсутствующий файл запоминаем как nil, чтобы не проверять его повторно
+	gi.dirs[key] = patterns
+	return patterns
+}
+
+// parseGitIgnore читает правила построчно в global
+func parseGitIgnore(r io.Reader) (*GitIgnore, error) {
 	gitIgnore := &GitIgnore{
-		patterns: []string{},
+		global: []gitIgnorePattern{},
 	}
 
-	// Читаем файл построчно
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-
-		// Пропускаем пустые строки и комментарии
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
+		if pattern, ok := parsePattern(scanner.Text()); ok {
+			gitIgnore.global = append(gitIgnore.global, pattern)
 		}
+	}
+
+	return gitIgnore, scanner.Err()
+}
+
+// newGitIgnore создает GitIgnore из строк файла .gitignore без чтения файлов
+func newGitIgnore(lines ...string) *GitIgnore {
+	gitIgnore, _ := parseGitIgnore(strings.NewReader(strings.Join(lines, "\n")))
+	return gitIgnore
+}
+
+// parsePattern разбирает строку .gitignore. Для пустых строк и комментариев
+// возвращает false.
+func parsePattern(line string) (gitIgnorePattern, bool) {
+	var pattern gitIgnorePattern
+
+	line = strings.TrimSuffix(line, "\r")
+	line = trimTrailingSpaces(line)
 
-		// Добавляем шаблон
-		gitIgnore.patterns = append(gitIgnore.patterns, line)
+	// Пропускаем пустые строки и комментарии; \# в начале - обычный символ #
+	if line == "" || strings.HasPrefix(line, "#") {
+		return pattern, false
 	}
 
-	if err := scanner.Err(); err != nil {
-		log.Printf("Ошибка при чтении файла .gitignore: %v\n", err)
-		return nil
+	if strings.HasPrefix(line, "!") {
+		pattern.negate = true
+		line = line[1:]
 	}
 
-	log.Printf("Загружено %d правил из .gitignore\n", len(gitIgnore.patterns))
-	return gitIgnore
+	if strings.HasSuffix(line, "/") {
+		pattern.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+
+	// Шаблон с / в начале или середине задается относительно корня проекта,
+	// остальные совпадают с именем на любом уровне
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return pattern, false
+	}
+
+	pattern.segments = strings.Split(line, "/")
+	if !anchored {
+		pattern.segments = append([]string{"**"}, pattern.segments...)
+	}
+	for i, segment := range pattern.segments {
+		pattern.segments[i] = convertCharClasses(segment)
+	}
+
+	return pattern, true
+}
+
+// trimTrailingSpaces удаляет пробелы в конце строки, кроме экранированных \
+func trimTrailingSpaces(line string) string {
+	end := len(line)
+	for end > 0 && line[end-1] == ' ' {
+		// Считаем обратные слэши перед пробелом: нечетное число экранирует его
+		backslashes := 0
+		for i := end - 2; i >= 0 && line[i] == '\\'; i-- {
+			backslashes++
+		}
+		if backslashes%2 == 1 {
+			break
+		}
+		end--
+	}
+	return line[:end]
+}
+
+// convertCharClasses заменяет отрицание [!...] из fnmatch на [^...],
+// которое понимает path.Match
+func convertCharClasses(segment string) string {
+	if !strings.Contains(segment, "[!") {
+		return segment
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(segment); i++ {
+		switch {
+		case segment[i] == '\\' && i+1 < len(segment):
+			builder.WriteString(segment[i : i+2])
+			i++
+		case segment[i] == '[' && i+1 < len(segment) && segment[i+1] == '!':
+			builder.WriteString("[^")
+			i++
+		default:
+			builder.WriteByte(segment[i])
+		}
+	}
+	return builder.String()
 }
 
-// IsIgnored проверяет, соответствует ли путь правилам .gitignore
-func (gi *GitIgnore) IsIgnored(path string) bool {
+// IsIgnored проверяет, соответствует ли путь (относительно проекта) правилам
+// игнорирования. Как и в git, побеждает последнее совпавшее правило, правила
+// из более глубоких .gitignore важнее, а файл из исключенной директории
+// нельзя вернуть правилом с !.
+func (gi *GitIgnore) IsIgnored(filePath string, isDir bool) bool {
 	// Если gitignore не загружен, ничего не игнорируем
 	if gi == nil {
 		return false
 	}
 
 	// Нормализуем путь для сравнения
-	path = filepath.ToSlash(path)
+	filePath = strings.Trim(filepath.ToSlash(filePath), "/")
+	if filePath == "" || filePath == "." {
+		return false
+	}
 
-	// По умолчанию не игнорируем путь
-	ignored := false
+	segments := append(append([]string{}, gi.prefix...), strings.Split(filePath, "/")...)
 
-	for _, pattern := range gi.patterns {
-		// Обрабатываем разные типы шаблонов
+	// Сначала проверяем родительские директории внутри проекта
+	for i := len(gi.prefix) + 1; i < len(segments); i++ {
+		if gi.matches(segments[:i], true) {
+			return true
+		}
+	}
 
-		// Шаблон исключения (начинается с !)
-		if strings.HasPrefix(pattern, "!") {
-			negPattern := pattern[1:]
-			if matchPattern(path, negPattern) {
-				// Если путь соответствует шаблону исключения, он не игнорируется
-				return false
-			}
-			continue
+	return gi.matches(segments, isDir)
+}
+
+// matches применяет правила к пути относительно root без учета
+// родительских директорий
+func (gi *GitIgnore) matches(segments []string, isDir bool) bool {
+	ignored := false
+	for _, pattern := range gi.global {
+		if pattern.match(segments, isDir) {
+			ignored = !pattern.negate
 		}
+	}
 
-		// Проверяем, соответствует ли путь текущему шаблону
-		if matchPattern(path, pattern) {
-			ignored = true
+	// Правила .gitignore применяются к путям относительно его директории
+	for i := 0; i < len(segments); i++ {
+		for _, pattern := range gi.dirPatterns(segments[:i]) {
+			if pattern.match(segments[i:], isDir) {
+				ignored = !pattern.negate
+			}
 		}
 	}
 
 	return ignored
 }
 
-// matchPattern проверяет соответствие пути шаблону .gitignore
-func matchPattern(path, pattern string) bool {
-	// Нормализуем путь и шаблон
-	path = strings.Trim(path, "/")
-
-	// Если шаблон заканчивается на /, то это директория
-	isDirPattern := strings.HasSuffix(pattern, "/")
-	if isDirPattern {
-		pattern = strings.TrimSuffix(pattern, "/")
+// match проверяет соответствие пути правилу без учета отрицания
+func (p gitIgnorePattern) match(segments []string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
 	}
+	return matchSegments(p.segments, segments)
+}
 
-	// Проверка для шаблона директории
-	if isDirPattern {
-		// Путь соответствует, если он содержит эту директорию в любом месте
-		return strings.HasSuffix(path, pattern) ||
-			   strings.Contains(path, pattern+"/") ||
-			   path == pattern
+// matchPattern проверяет соответствие пути шаблону .gitignore.
+// Путь, заканчивающийся на /, считается директорией.
+func matchPattern(filePath, line string) bool {
+	pattern, ok := parsePattern(line)
+	if !ok {
+		return false
 	}
 
-	// Проверка для шаблонов, начинающихся с /
-	if strings.HasPrefix(pattern, "/") {
-		// Шаблон с / в начале соответствует только файлам в корне проекта
-		pattern = strings.TrimPrefix(pattern, "/")
-		return path == pattern || strings.HasPrefix(path, pattern+"/")
-	}
+	isDir := strings.HasSuffix(filePath, "/")
+	filePath = strings.Trim(filePath, "/")
+	return pattern.match(strings.Split(filePath, "/"), isDir)
+}
 
-	// Проверка для обычных шаблонов с расширением (*.ext)
-	if strings.HasPrefix(pattern, "*") {
-		ext := strings.TrimPrefix(pattern, "*")
-		return strings.HasSuffix(path, ext) || strings.Contains(path, "/"+pattern)
-	}
+// matchSegments сопоставляет части шаблона с частями пути.
+// ** совпадает с любым числом директорий, а завершающий ** - со всем
+// содержимым директории, но не с ней самой.
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			if len(rest) == 0 {
+				return len(segments) > 0
+			}
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(rest, segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
 
-	// Проверка для шаблонов с именем файла (file.*)
-	if strings.HasSuffix(pattern, "*") {
-		basename := strings.TrimSuffix(pattern, "*")
-		return strings.HasPrefix(filepath.Base(path), basename) ||
-			   strings.Contains(path, pattern)
+		if len(segments) == 0 {
+			return false
+		}
+		if matched, err := path.Match(pattern[0], segments[0]); err != nil || !matched {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
 	}
 
-	// Точное совпадение или совпадение в пути
-	return path == pattern ||
-		   strings.HasSuffix(path, "/"+pattern) ||
-		   strings.Contains(path, "/"+pattern+"/")
+	return len(segments) == 0
 }
@@ -16,8 +16,11 @@ func TestLoadGitIgnore(t *testing.T) {
 
 	// Случай 1: Директория без .gitignore
 	gitIgnore := LoadGitIgnore(tempDir)
-	if gitIgnore != nil {
-		t.Errorf("Ожидается nil при отсутствии .gitignore, получено: %v", gitIgnore)
+	if gitIgnore == nil {
+		t.Fatalf("Ожидается ненулевой GitIgnore при отсутствии .gitignore")
+	}
+	if gitIgnore.dirs[""] != nil || gitIgnore.IsIgnored("file.js", false) {
+		t.Errorf("Без .gitignore ничего не должно игнорироваться")
 	}
 
 	// Случай 2: Директория с пустым .gitignore
@@ -29,8 +32,8 @@ func TestLoadGitIgnore(t *testing.T) {
 	gitIgnore = LoadGitIgnore(tempDir)
 	if gitIgnore == nil {
 		t.Errorf("Ожидается ненулевой GitIgnore для пустого .gitignore")
-	} else if len(gitIgnore.patterns) != 0 {
-		t.Errorf("Ожидается 0 шаблонов, получено: %d", len(gitIgnore.patterns))
+	} else if len(gitIgnore.dirs[""]) != 0 {
+		t.Errorf("Ожидается 0 шаблонов, получено: %d", len(gitIgnore.dirs[""]))
 	}
 
 	// Случай 3: Директория с .gitignore и шаблонами
@@ -52,42 +55,44 @@ func TestLoadGitIgnore(t *testing.T) {
 	} else {
 		// Должно быть 3 шаблона (пустые строки и комментарии игнорируются)
 		expectedPatterns := 3
-		if len(gitIgnore.patterns) != expectedPatterns {
-			t.Errorf("Ожидается %d шаблонов, получено: %d", expectedPatterns, len(gitIgnore.patterns))
+		if len(gitIgnore.dirs[""]) != expectedPatterns {
+			t.Errorf("Ожидается %d шаблонов, получено: %d", expectedPatterns, len(gitIgnore.dirs[""]))
 		}
 	}
 }
 
 func TestIsIgnored(t *testing.T) {
 	// Создаем GitIgnore с известными шаблонами
-	gitIgnore := &GitIgnore{
-		patterns: []string{
-			"node_modules/",
-			"*.log",
-			"!important.log",
-			"/dist",
-		},
-	}
+	gitIgnore := newGitIgnore(
+		"node_modules/",
+		"*.log",
+		"!important.log",
+		"/dist",
+	)
 
 	// Тестовые случаи: path -> должен ли быть проигнорирован
 	tests := []struct {
 		path     string
+		isDir    bool
 		expected bool
 	}{
-		{"node_modules/file.js", true},             // Соответствует node_modules/
-		{"path/to/node_modules/file.js", true},     // Соответствует node_modules/ в поддиректории
-		{"file.log", true},                         // Соответствует *.log
-		{"path/to/file.log", true},                 // Соответствует *.log в поддиректории
-		{"important.log", false},                   // Соответствует !important.log (исключение)
-		{"path/to/important.log", false},           // Соответствует !important.log в поддиректории
-		{"dist/file.js", true},                     // Соответствует /dist (только в корне)
-		{"path/to/dist/file.js", false},            // Не соответствует /dist в поддиректории
-		{"node_modulesx/file.js", false},           // Не соответствует node_modules/
-		{"file.txt", false},                        // Не соответствует ни одному шаблону
+		{"node_modules/file.js", false, true},         // Соответствует node_modules/
+		{"path/to/node_modules/file.js", false, true}, // Соответствует node_modules/ в поддиректории
+		{"node_modules", true, true},                  // Сама директория
+		{"node_modules", false, false},                // Файл с именем директории
+		{"file.log", false, true},                     // Соответствует *.log
+		{"path/to/file.log", false, true},             // Соответствует *.log в поддиректории
+		{"important.log", false, false},               // Соответствует !important.log (исключение)
+		{"path/to/important.log", false, false},       // Соответствует !important.log в поддиректории
+		{"dist/file.js", false, true},                 // Соответствует /dist (только в корне)
+		{"path/to/dist/file.js", false, false},        // Не соответствует /dist в поддиректории
+		{"node_modulesx/file.js", false, false},       // Не соответствует node_modules/
+		{"file.txt", false, false},                    // Не соответствует ни одному шаблону
+		{".", true, false},                            // Корень проекта
 	}
 
 	for _, test := range tests {
-		result := gitIgnore.IsIgnored(test.path)
+		result := gitIgnore.IsIgnored(test.path, test.isDir)
 		if result != test.expected {
 			t.Errorf("Для пути %q: ожидалось IsIgnored=%v, получено: %v", test.path, test.expected, result)
 		}
@@ -95,28 +100,64 @@ func TestIsIgnored(t *testing.T) {
 
 	// Тест с nil GitIgnore
 	var nilGitIgnore *GitIgnore
-	if nilGitIgnore.IsIgnored("any/path") {
+	if nilGitIgnore.IsIgnored("any/path", false) {
 		t.Errorf("Ожидается false для nil GitIgnore")
 	}
 }
 
+func TestIsIgnoredLastMatchWins(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		// Исключение, за которым снова следует совпадающее правило
+		{"повторное исключение", []string{"*.log", "!important.log", "important.*"}, "important.log", false, true},
+		{"возврат после исключения", []string{"*.log", "!important.log"}, "logs/important.log", false, false},
+		// Файл нельзя вернуть, если исключена его родительская директория
+		{"исключенная директория", []string{"build/", "!build/keep.js"}, "build/keep.js", false, true},
+		// Содержимое директории можно вернуть, если исключено содержимое, а не сама директория
+		{"исключенное содержимое", []string{"build/*", "!build/keep.js"}, "build/keep.js", false, false},
+		{"исключенное содержимое, другой файл", []string{"build/*", "!build/keep.js"}, "build/other.js", false, true},
+		// Пример из документации git: отслеживается только foo/bar
+		{"только foo/bar", []string{"/*", "!/foo", "/foo/*", "!/foo/bar"}, "foo/bar/file.js", false, false},
+		{"только foo/bar, соседний файл", []string{"/*", "!/foo", "/foo/*", "!/foo/bar"}, "foo/baz.js", false, true},
+		{"только foo/bar, корень", []string{"/*", "!/foo", "/foo/*", "!/foo/bar"}, "other.js", false, true},
+	}
+
+	for _, test := range tests {
+		gitIgnore := newGitIgnore(test.patterns...)
+		if result := gitIgnore.IsIgnored(test.path, test.isDir); result != test.expected {
+			t.Errorf("%s: для пути %q и правил %q ожидалось IsIgnored=%v, получено: %v",
+				test.name, test.path, test.patterns, test.expected, result)
+		}
+	}
+}
+
 func TestMatchPattern(t *testing.T) {
-	// Тестовые случаи: path, pattern -> должен ли соответствовать
+	// Тестовые случаи: path, pattern -> должен ли соответствовать.
+	// Путь, заканчивающийся на /, - директория.
 	tests := []struct {
 		path     string
 		pattern  string
 		expected bool
 	}{
-		{"file.txt", "*.txt", true},               // Простое соответствие по расширению
-		{"path/to/file.txt", "*.txt", true},       // Соответствие в поддиректории
-		{"file.txt", "file.*", true},              // Соответствие по имени файла
-		{"file.log", "*.log", true},               // Соответствие по расширению
-		{"a/b/c/file.log", "*.log", true},         // Соответствие в глубокой поддиректории
-		{"dir/", "dir/", true},                    // Соответствие директории
-		{"path/to/dir/", "dir/", true},            // Соответствие директории в поддиректории
-		{"path/to/dir/file.txt", "dir/", true},    // Соответствие файла в директории
-		{"file.jpg", "*.txt", false},              // Несоответствие расширения
-		{"file.txt", "file.jpg", false},           // Несоответствие имени файла
+		{"file.txt", "*.txt", true},             // Простое соответствие по расширению
+		{"path/to/file.txt", "*.txt", true},     // Соответствие в поддиректории
+		{"file.txt", "file.*", true},            // Соответствие по имени файла
+		{"file.log", "*.log", true},             // Соответствие по расширению
+		{"a/b/c/file.log", "*.log", true},       // Соответствие в глубокой поддиректории
+		{"dir/", "dir/", true},                  // Соответствие директории
+		{"path/to/dir/", "dir/", true},          // Соответствие директории в поддиректории
+		{"dir", "dir/", false},                  // Файл не соответствует шаблону директории
+		{"file.jpg", "*.txt", false},            // Несоответствие расширения
+		{"file.txt", "file.jpg", false},         // Несоответствие имени файла
+		{"file.txt", "# file.txt", false},       // Комментарий
+		{"file.txt", "   ", false},              // Пустая строка
+		{"file.txt", "file.txt\r", true},        // Окончание строки CRLF
+		{"path/to/dir/file.txt", "dir/", false}, // Содержимое директории проверяет IsIgnored
 	}
 
 	for _, test := range tests {
@@ -128,6 +169,103 @@ func TestMatchPattern(t *testing.T) {
 	}
 }
 
+// TestMatchPatternGitExamples проверяет примеры из документации gitignore(5)
+func TestMatchPatternGitExamples(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		// "hello.*" совпадает с любым файлом или директорией, имя которых начинается с hello.
+		{"hello.*", "hello.txt", true},
+		{"hello.*", "a/hello.c", true},
+		{"hello.*", "hello.d/", true},
+		{"hello.*", "hello", false},
+
+		// "foo/" совпадает с директорией foo, но не с файлом foo
+		{"foo/", "foo/", true},
+		{"foo/", "a/foo/", true},
+		{"foo/", "foo", false},
+
+		// "doc/frotz/" и "/doc/frotz" привязаны к корню
+		{"doc/frotz/", "doc/frotz/", true},
+		{"doc/frotz/", "a/doc/frotz/", false},
+		{"doc/frotz", "doc/frotz", true},
+		{"doc/frotz", "a/doc/frotz", false},
+		{"/doc/frotz", "doc/frotz", true},
+		{"frotz/", "a/frotz/", true},
+
+		// "/bar" совпадает только в корне
+		{"/bar", "bar", true},
+		{"/bar", "a/bar", false},
+
+		// "foo/*" совпадает с foo/test.json и foo/bar, но не с foo/bar/hello.c
+		{"foo/*", "foo/test.json", true},
+		{"foo/*", "foo/bar/", true},
+		{"foo/*", "foo/bar/hello.c", false},
+		{"foo/*", "foo/", false},
+
+		// Ведущий "**/" совпадает на любом уровне
+		{"**/foo", "foo", true},
+		{"**/foo", "a/b/foo", true},
+		{"**/foo/bar", "foo/bar", true},
+		{"**/foo/bar", "x/foo/bar", true},
+		{"**/foo/bar", "x/foo/y/bar", false},
+
+		// Завершающий "/**" совпадает со всем содержимым, но не с директорией
+		{"abc/**", "abc/file", true},
+		{"abc/**", "abc/x/y/file", true},
+		{"abc/**", "abc/", false},
+		{"abc/**", "x/abc/file", false},
+
+		// "/**/" совпадает с нулем или более директорий
+		{"a/**/b", "a/b", true},
+		{"a/**/b", "a/x/b", true},
+		{"a/**/b", "a/x/y/b", true},
+		{"a/**/b", "a/x/y/c", false},
+		{"src/**/generated", "src/api/v1/generated/", true},
+
+		// Прочие "**" - обычные "*"
+		{"foo**bar", "fooxbar", true},
+		{"foo**bar", "foo/bar", false},
+
+		// "*" и "?" не совпадают с /
+		{"a?c", "abc", true},
+		{"a?c", "a/c", false},
+		{"a?c", "ac", false},
+		{"a/*.js", "a/b/c.js", false},
+
+		// Классы символов
+		{"*.test.[jt]s", "app.test.js", true},
+		{"*.test.[jt]s", "app.test.ts", true},
+		{"*.test.[jt]s", "app.test.cs", false},
+		{"file[0-9].txt", "file7.txt", true},
+		{"file[!0-9].txt", "file7.txt", false},
+		{"file[!0-9].txt", "filex.txt", true},
+		{"file[^0-9].txt", "filex.txt", true},
+
+		// Экранирование
+		{"\\#file", "#file", true},
+		{"\\#file", "file", false},
+		{"\\!important!.txt", "!important!.txt", true},
+		{"\\*", "*", true},
+		{"\\*", "x", false},
+
+		// Пробелы в конце удаляются, если не экранированы
+		{"trailing   ", "trailing", true},
+		{"space\\ ", "space ", true},
+		{"space\\ ", "space", false},
+	}
+
+	for _, test := range tests {
+		result := matchPattern(test.path, test.pattern)
+		if result != test.expected {
+			t.Errorf("Для пути %q и шаблона %q: ожидалось %v, получено: %v",
+				test.path, test.pattern, test.expected, result)
+		}
+	}
+}
+
 // Вспомогательная функция для объединения строк с переносами строк
 func joinLines(lines ...string) string {
 	result := ""
@@ -136,3 +274,60 @@ func joinLines(lines ...string) string {
 	}
 	return result
 }
+
+func TestLoadGitIgnoreNested(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "gitignore-nested-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeFiles(t, tempDir, map[string]string{
+		".gitignore":                   joinLines("*.log", "/build/"),
+		"packages/app/.gitignore":      joinLines("/build/", "generated/", "!keep.log"),
+		"packages/app/deep/.gitignore": joinLines("*.tmp.js"),
+		"packages/lib/.gitignore":      joinLines("*.js", "!index.js"),
+	})
+
+	gitIgnore := LoadGitIgnore(tempDir)
+
+	tests := []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"build", true, true},
+		{"packages/build", true, false},         // /build/ корневого .gitignore привязан к корню
+		{"packages/app/build", true, true},      // /build/ из packages/app/.gitignore
+		{"packages/app/src/build", true, false}, // привязан к packages/app
+		{"packages/app/src/generated/api.js", false, true},
+		{"packages/lib/src/generated/api.js", false, true}, // *.js из packages/lib/.gitignore
+		{"packages/lib/index.js", false, false},
+		{"packages/app/error.log", false, true},
+		{"packages/app/keep.log", false, false}, // более глубокий .gitignore важнее
+		{"keep.log", false, true},               // но только в своей директории
+		{"packages/app/deep/file.tmp.js", false, true},
+		{"packages/app/file.tmp.js", false, false},
+	}
+
+	for _, test := range tests {
+		if result := gitIgnore.IsIgnored(test.path, test.isDir); result != test.expected {
+			t.Errorf("Для пути %q: ожидалось IsIgnored=%v, получено: %v", test.path, test.expected, result)
+		}
+	}
+}
+
+// writeFiles создает файлы с содержимым, включая промежуточные директории
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Не удалось создать директорию для %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Не удалось создать файл %s: %v", name, err)
+		}
+	}
+}
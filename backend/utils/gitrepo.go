@@ -0,0 +1,186 @@
+package utils
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gitRepo описывает расположение git-репозитория
+type gitRepo struct {
+	// root - рабочая директория репозитория
+	root string
+	// commonDir - директория .git (для рабочих деревьев git worktree -
+	// общая директория основного репозитория)
+	commonDir string
+}
+
+// findGitRepo ищет репозиторий, содержащий path, поднимаясь по директориям
+func findGitRepo(path string) (gitRepo, bool) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return gitRepo{}, false
+	}
+
+	for {
+		if gitDir, ok := resolveGitDir(filepath.Join(dir, ".git")); ok {
+			return gitRepo{root: dir, commonDir: commonGitDir(gitDir)}, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return gitRepo{}, false
+		}
+		dir = parent
+	}
+}
+
+// resolveGitDir возвращает директорию репозитория для .git. В подмодулях
+// и рабочих деревьях .git - файл со строкой "gitdir: <путь>".
+func resolveGitDir(dotGit string) (string, bool) {
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return "", false
+	}
+	if info.IsDir() {
+		return dotGit, true
+	}
+
+	content, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", false
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir:")
+	if !ok {
+		return "", false
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(dotGit), gitDir)
+	}
+	return gitDir, true
+}
+
+// commonGitDir возвращает общую директорию репозитория, на которую
+// рабочее дерево ссылается файлом commondir
+func commonGitDir(gitDir string) string {
+	content, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir
+	}
+	commonDir := strings.TrimSpace(string(content))
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(gitDir, commonDir)
+	}
+	return commonDir
+}
+
+// excludesFile возвращает путь к глобальному файлу исключений (core.excludesFile).
+// Значение из конфигурации репозитория важнее пользовательского; если
+// параметр не задан, используется $XDG_CONFIG_HOME/git/ignore.
+func excludesFile(gitDir string) string {
+	configDir := userGitConfigDir()
+	home, _ := os.UserHomeDir()
+
+	configs := []string{}
+	if configDir != "" {
+		configs = append(configs, filepath.Join(configDir, "config"))
+	}
+	if home != "" {
+		configs = append(configs, filepath.Join(home, ".gitconfig"))
+	}
+	configs = append(configs, filepath.Join(gitDir, "config"))
+
+	path := ""
+	for _, config := range configs {
+		if value, ok := readGitConfigValue(config, "core", "excludesfile"); ok {
+			path = value
+		}
+	}
+
+	if path == "" {
+		if configDir == "" {
+			return ""
+		}
+		return filepath.Join(configDir, "ignore")
+	}
+
+	if rest, ok := strings.CutPrefix(path, "~/"); ok && home != "" {
+		path = filepath.Join(home, rest)
+	}
+	return path
+}
+
+// userGitConfigDir возвращает $XDG_CONFIG_HOME/git или ~/.config/git
+func userGitConfigDir() string {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "git")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".config", "git")
+	}
+	return ""
+}
+
+// readGitConfigValue читает параметр из файла конфигурации git. Имена секции
+// и параметра не зависят от регистра; поддерживаются значения в кавычках
+// и комментарии после # и ;.
+func readGitConfigValue(path, section, key string) (string, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+
+	value, found := "", false
+	inSection := false
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if line[0] == '[' {
+			name := strings.TrimSpace(strings.Trim(line[:strings.IndexByte(line, ']')+1], "[]"))
+			inSection = strings.EqualFold(name, section)
+			continue
+		}
+		if !inSection {
+			continue
+		}
+
+		name, raw, ok := strings.Cut(line, "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(name), key) {
+			continue
+		}
+		value, found = parseGitConfigValue(raw), true
+	}
+
+	return value, found
+}
+
+// parseGitConfigValue убирает кавычки и комментарий из значения параметра
+func parseGitConfigValue(raw string) string {
+	var builder strings.Builder
+	quoted := false
+
+	raw = strings.TrimSpace(raw)
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case c == '\\' && i+1 < len(raw):
+			i++
+			builder.WriteByte(raw[i])
+		case c == '"':
+			quoted = !quoted
+		case (c == '#' || c == ';') && !quoted:
+			return strings.TrimSpace(builder.String())
+		default:
+			builder.WriteByte(c)
+		}
+	}
+	return strings.TrimSpace(builder.String())
+}
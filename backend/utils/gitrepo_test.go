@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// isolateGitConfig подменяет домашнюю директорию, чтобы тесты не читали
+// конфигурацию git пользователя
+func isolateGitConfig(t *testing.T) string {
+	t.Helper()
+
+	home, err := os.MkdirTemp("", "gitconfig-home")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(home) })
+
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	return home
+}
+
+func TestLoadGitIgnoreGitExcludes(t *testing.T) {
+	home := isolateGitConfig(t)
+
+	tempDir, err := os.MkdirTemp("", "gitignore-repo-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeFiles(t, tempDir, map[string]string{
+		".git/info/exclude": joinLines("# локальные исключения", "local/"),
+		".gitignore":        joinLines("!global-keep.js"),
+	})
+	writeFiles(t, home, map[string]string{
+		".config/git/ignore": joinLines("*.swp", "global-keep.js"),
+	})
+
+	gitIgnore := LoadGitIgnore(tempDir)
+
+	tests := []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"local", true, true}, // .git/info/exclude
+		{"src/local/file.js", false, true},
+		{"src/file.js.swp", false, true}, // $XDG_CONFIG_HOME/git/ignore
+		{"global-keep.js", false, false}, // .gitignore важнее глобальных исключений
+		{"src/file.js", false, false},
+	}
+
+	for _, test := range tests {
+		if result := gitIgnore.IsIgnored(test.path, test.isDir); result != test.expected {
+			t.Errorf("Для пути %q: ожидалось IsIgnored=%v, получено: %v", test.path, test.expected, result)
+		}
+	}
+}
+
+func TestLoadGitIgnoreCoreExcludesFile(t *testing.T) {
+	home := isolateGitConfig(t)
+
+	tempDir, err := os.MkdirTemp("", "gitignore-repo-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeFiles(t, home, map[string]string{
+		".gitconfig":         joinLines("[user]", "\tname = Test", "[Core]", "\texcludesFile = ~/global-ignore ; комментарий"),
+		"global-ignore":      joinLines("*.bak"),
+		".config/git/ignore": joinLines("*.swp"),
+	})
+	writeFiles(t, tempDir, map[string]string{
+		".git/HEAD": "ref: refs/heads/main\n",
+	})
+
+	gitIgnore := LoadGitIgnore(tempDir)
+	if !gitIgnore.IsIgnored("src/file.bak", false) {
+		t.Errorf("Ожидается, что core.excludesFile из ~/.gitconfig применяется")
+	}
+	if gitIgnore.IsIgnored("src/file.swp", false) {
+		t.Errorf("Заданный core.excludesFile заменяет файл по умолчанию")
+	}
+
+	// Параметр в конфигурации репозитория важнее пользовательского
+	writeFiles(t, tempDir, map[string]string{
+		".git/config": joinLines("[core]", "\texcludesfile = \""+filepath.Join(tempDir, "repo-ignore")+"\""),
+		"repo-ignore": joinLines("*.orig"),
+	})
+
+	gitIgnore = LoadGitIgnore(tempDir)
+	if !gitIgnore.IsIgnored("src/file.orig", false) || gitIgnore.IsIgnored("src/file.bak", false) {
+		t.Errorf("Ожидается core.excludesFile из .git/config")
+	}
+}
+
+func TestLoadGitIgnoreSubdirectory(t *testing.T) {
+	isolateGitConfig(t)
+
+	tempDir, err := os.MkdirTemp("", "gitignore-repo-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeFiles(t, tempDir, map[string]string{
+		".git/info/exclude":       joinLines("/packages/app/secret/"),
+		".gitignore":              joinLines("dist/", "/packages/app/*.gen.js"),
+		"packages/.gitignore":     joinLines("app/tmp/"),
+		"packages/app/.gitignore": joinLines("*.log"),
+	})
+
+	// Проект - поддиректория репозитория: применяются .gitignore родительских директорий
+	gitIgnore := LoadGitIgnore(filepath.Join(tempDir, "packages", "app"))
+
+	tests := []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"dist", true, true},
+		{"src/dist/file.js", false, true},
+		{"file.gen.js", false, true},
+		{"src/file.gen.js", false, false},
+		{"tmp/file.js", false, true},
+		{"secret", true, true},
+		{"error.log", false, true},
+		{"src/index.js", false, false},
+	}
+
+	for _, test := range tests {
+		if result := gitIgnore.IsIgnored(test.path, test.isDir); result != test.expected {
+			t.Errorf("Для пути %q: ожидалось IsIgnored=%v, получено: %v", test.path, test.expected, result)
+		}
+	}
+}
+
+func TestFindGitRepoWorktree(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "gitignore-worktree-test")
+	if err != nil {
+		t.Fatalf("Не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Рабочее дерево ссылается на .git/worktrees/feature, а та - на общую директорию
+	writeFiles(t, tempDir, map[string]string{
+		"main/.git/info/exclude":                joinLines("*.local.js"),
+		"main/.git/worktrees/feature/commondir": "../..\n",
+		"feature/.git":                          "gitdir: ../main/.git/worktrees/feature\n",
+	})
+
+	repo, ok := findGitRepo(filepath.Join(tempDir, "feature"))
+	if !ok {
+		t.Fatalf("Ожидается, что репозиторий найден")
+	}
+	if repo.root != filepath.Join(tempDir, "feature") || repo.commonDir != filepath.Join(tempDir, "main", ".git") {
+		t.Errorf("Неверное расположение репозитория: %+v", repo)
+	}
+}
+
+func TestParseGitConfigValue(t *testing.T) {
+	tests := map[string]string{
+		"~/.gitignore":                   "~/.gitignore",
+		"  /path/to/ignore  ":            "/path/to/ignore",
+		`"/path with spaces/ignore"`:     "/path with spaces/ignore",
+		"/path/ignore # комментарий":     "/path/ignore",
+		`"/path;with;semicolons" ; note`: "/path;with;semicolons",
+		`C:\\Users\\ignore`:              `C:\Users\ignore`,
+	}
+
+	for raw, expected := range tests {
+		if value := parseGitConfigValue(raw); value != expected {
+			t.Errorf("parseGitConfigValue(%q): ожидается %q, получено %q", raw, expected, value)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// PatternSet - набор шаблонов в синтаксисе .gitignore, заданный списком
+// строк (например, в конфигурации), а не файлами. Путь совпадает с набором,
+// если совпадает он сам или одна из его родительских директорий.
+type PatternSet struct {
+	patterns []string
+	matcher  *GitIgnore
+}
+
+// NewPatternSet проверяет и компилирует шаблоны
+func NewPatternSet(patterns []string) (*PatternSet, error) {
+	for _, pattern := range patterns {
+		if err := ValidatePattern(pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	return &PatternSet{
+		patterns: append([]string{}, patterns...),
+		matcher:  newGitIgnore(patterns...),
+	}, nil
+}
+
+// ValidatePattern проверяет синтаксис шаблона
+func ValidatePattern(pattern string) error {
+	parsed, ok := parsePattern(pattern)
+	if !ok {
+		return fmt.Errorf("пустой шаблон %q", pattern)
+	}
+
+	for _, segment := range parsed.segments {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("некорректный шаблон %q", pattern)
+		}
+	}
+	return nil
+}
+
+// Match проверяет, совпадает ли путь (относительно проекта) с набором
+func (ps *PatternSet) Match(filePath string, isDir bool) bool {
+	if ps == nil {
+		return false
+	}
+	return ps.matcher.IsIgnored(filePath, isDir)
+}
+
+// Empty сообщает, что набор не содержит шаблонов
+func (ps *PatternSet) Empty() bool {
+	return ps == nil || len(ps.patterns) == 0
+}
+
+// String возвращает шаблоны через запятую
+func (ps *PatternSet) String() string {
+	if ps == nil {
+		return ""
+	}
+	return strings.Join(ps.patterns, ", ")
+}
@@ -0,0 +1,50 @@
+package utils
+
+import "testing"
+
+func TestPatternSet(t *testing.T) {
+	set, err := NewPatternSet([]string{"src/ui/**", "shared/", "*.test.[jt]s", "!keep.test.ts"})
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	tests := []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"src/ui/button.tsx", false, true},
+		{"src/ui/forms/input.tsx", false, true},
+		{"src/ui", true, false},
+		{"src/db/client.ts", false, false},
+		{"shared/utils.ts", false, true},
+		{"packages/shared/utils.ts", false, true},
+		{"src/app.test.ts", false, true},
+		{"src/keep.test.ts", false, false},
+	}
+
+	for _, test := range tests {
+		if result := set.Match(test.path, test.isDir); result != test.expected {
+			t.Errorf("Для пути %q: ожидалось Match=%v, получено: %v", test.path, test.expected, result)
+		}
+	}
+
+	var empty *PatternSet
+	if !empty.Empty() || empty.Match("any/path", false) {
+		t.Errorf("Пустой набор не должен совпадать ни с одним путем")
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	for _, pattern := range []string{"src/**", "*.ts", "file[0-9].js", "!keep.js", "/dist/"} {
+		if err := ValidatePattern(pattern); err != nil {
+			t.Errorf("Шаблон %q должен быть корректным: %v", pattern, err)
+		}
+	}
+
+	for _, pattern := range []string{"", "   ", "# комментарий", "file[.js", "/"} {
+		if err := ValidatePattern(pattern); err == nil {
+			t.Errorf("Ожидается ошибка для шаблона %q", pattern)
+		}
+	}
+}
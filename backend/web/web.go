@@ -0,0 +1,72 @@
+// Package web раздает фронтенд, встроенный в исполняемый файл или взятый с диска.
+package web
+
+import (
+	"bytes"
+	"embed"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// static содержит собранный фронтенд (static/dist) и README.md,
+// благодаря которому шаблон совпадает и без сборки
+//
+//go:embed all:static
+var static embed.FS
+
+// indexFile - точка входа одностраничного приложения
+const indexFile = "index.html"
+
+// Embedded возвращает встроенный фронтенд. Второе значение равно false,
+// если бинарник собран без фронтенда (static/dist отсутствует).
+func Embedded() (fs.FS, bool) {
+	dist, err := fs.Sub(static, "static/dist")
+	if err != nil {
+		return nil, false
+	}
+	if _, err := fs.Stat(dist, indexFile); err != nil {
+		return nil, false
+	}
+	return dist, true
+}
+
+// NewHandler раздает файлы из frontend. Для неизвестных путей вне /api/
+// возвращается index.html, чтобы маршрутизацией занималось само приложение.
+func NewHandler(frontend fs.FS) http.Handler {
+	files := http.FileServer(http.FS(frontend))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+		if name == "" {
+			name = "."
+		}
+
+		if info, err := fs.Stat(frontend, name); err == nil && !info.IsDir() {
+			files.ServeHTTP(w, r)
+			return
+		}
+
+		if name == "api" || strings.HasPrefix(name, "api/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		serveIndex(w, r, frontend)
+	})
+}
+
+// serveIndex отправляет index.html
+func serveIndex(w http.ResponseWriter, r *http.Request, frontend fs.FS) {
+	content, err := fs.ReadFile(frontend, indexFile)
+	if err != nil {
+		http.Error(w, "Фронтенд не содержит index.html", http.StatusNotFound)
+		return
+	}
+
+	// Не кэшируем index.html: он ссылается на ресурсы текущей сборки
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeContent(w, r, indexFile, time.Time{}, bytes.NewReader(content))
+}
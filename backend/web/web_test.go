@@ -0,0 +1,68 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestNewHandler(t *testing.T) {
+	handler := NewHandler(fstest.MapFS{
+		"index.html":     {Data: []byte("<html>index</html>")},
+		"assets/app.js":  {Data: []byte("console.log('app');")},
+		"assets/app.css": {Data: []byte("body {}")},
+	})
+
+	tests := []struct {
+		path     string
+		status   int
+		contains string
+	}{
+		{"/", http.StatusOK, "index"},
+		{"/assets/app.js", http.StatusOK, "console.log"},
+		{"/assets/app.css", http.StatusOK, "body"},
+		// Маршруты приложения отдаются как index.html
+		{"/files/src/config.ts", http.StatusOK, "index"},
+		{"/assets", http.StatusOK, "index"},
+		{"/../index.html/..", http.StatusOK, "index"},
+		// Неизвестные пути API не подменяются приложением
+		{"/api/unknown", http.StatusNotFound, ""},
+		{"/api", http.StatusNotFound, ""},
+	}
+
+	for _, test := range tests {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+		if rr.Code != test.status {
+			t.Errorf("%s: ожидается статус %d, получен %d", test.path, test.status, rr.Code)
+			continue
+		}
+		if !strings.Contains(rr.Body.String(), test.contains) {
+			t.Errorf("%s: ожидается %q в ответе, получено: %s", test.path, test.contains, rr.Body.String())
+		}
+	}
+}
+
+func TestNewHandlerWithoutIndex(t *testing.T) {
+	handler := NewHandler(fstest.MapFS{"app.js": {Data: []byte("app")}})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/page", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Без index.html ожидается статус %d, получен %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestEmbedded(t *testing.T) {
+	// В репозитории static/dist отсутствует, пока фронтенд не собран командой build:embed
+	frontend, ok := Embedded()
+	if ok && frontend == nil {
+		t.Error("Встроенный фронтенд не должен быть nil")
+	}
+	if !ok && frontend != nil {
+		t.Error("Без сборки фронтенд должен быть nil")
+	}
+}
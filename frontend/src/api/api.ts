@@ -1,18 +1,39 @@
-// Константа с базовым URL для API
-const API_BASE_URL = 'http://localhost:8080/api';
+// Базовый URL для API: по умолчанию тот же origin, с которого загружен фронтенд.
+// В режиме разработки запросы /api проксирует Vite (см. vite.config.ts),
+// VITE_API_BASE_URL позволяет указать другой сервер при сборке
+const API_BASE_URL = `${import.meta.env.VITE_API_BASE_URL ?? window.location.origin}/api`;
 
 // Типы данных
+export interface Position {
+  line: number;
+  column: number;
+  offset: number;
+}
+
+// Вид объявления верхнего уровня
+export type SymbolKind = 'const' | 'let' | 'var' | 'function' | 'class' | 'enum' | 'interface' | 'type';
+
+// Узел графа: константа, переменная, функция, класс, перечисление, интерфейс или тип
 export interface Constant {
+  id: string;
   name: string;
+  kind: SymbolKind;
+  // Инициализатор, определение типа или заголовок функции и класса
   value: string;
   type: string;
   filePath: string;
   lineNum: number;
+  nameStart: Position;
+  nameEnd: Position;
+  valueStart: Position;
+  valueEnd: Position;
 }
 
+// Ребра ссылаются на идентификаторы констант (Constant.id)
 export interface Dependency {
   source: string;
   target: string;
+  kind: 'local' | 'import';
 }
 
 export interface DependencyGraph {
@@ -20,6 +41,95 @@ export interface DependencyGraph {
   edges: Dependency[];
 }
 
+// Граф импортов между файлами: узел - файл, ребро - одна инструкция импорта
+export type ImportKind = 'static' | 'dynamic' | 'type-only' | 'side-effect';
+
+export interface ModuleNode {
+  id: string;
+  name: string;
+  // Файл указан в entryPoints конфигурации проекта
+  entry?: boolean;
+  // Файл не достижим из точек входа
+  unreachable?: boolean;
+  // Импортируемый файл, который не анализируется (стили, JSON)
+  unanalyzed?: boolean;
+}
+
+export interface ModuleEdge {
+  source: string;
+  target: string;
+  kind: ImportKind;
+  reExport: boolean;
+  // Динамический импорт внутри lazy(() => import(...))
+  lazy: boolean;
+  symbols: string[];
+  line: number;
+}
+
+// Вызов import(), модуль которого вычисляется во время выполнения
+export interface ModuleDiagnostic {
+  kind: 'unresolvable-dynamic';
+  expression: string;
+  file: string;
+  line: number;
+  column: number;
+  endLine: number;
+  endColumn: number;
+}
+
+export interface ModuleGraph {
+  nodes: ModuleNode[];
+  edges: ModuleEdge[];
+  diagnostics: ModuleDiagnostic[];
+}
+
+// Граф, сгруппированный по директориям или пакетам
+export type AggregateBy = 'directory' | 'package';
+
+export interface AggregateNode {
+  id: string;
+  label: string;
+  kind: AggregateBy | 'constant';
+  // Группа, в которую входит константа раскрытой группы
+  parent?: string;
+  constants: number;
+  files: number;
+  internalEdges: number;
+}
+
+// Вес ребра - число зависимостей между константами, объединенных в ребро
+export interface AggregateEdge {
+  source: string;
+  target: string;
+  weight: number;
+}
+
+export interface AggregateGraph {
+  by: AggregateBy;
+  depth?: number;
+  nodes: AggregateNode[];
+  edges: AggregateEdge[];
+}
+
+// Изменения графа, которые сервер присылает в режиме -watch
+export interface GraphDelta {
+  addedNodes: Constant[] | null;
+  changedNodes: Constant[] | null;
+  removedNodes: string[] | null;
+  addedEdges: Dependency[] | null;
+  removedEdges: Dependency[] | null;
+  addedFiles: string[] | null;
+  removedFiles: string[] | null;
+}
+
+export interface GraphUpdateListener {
+  // Вызывается для каждого изменения графа
+  onDelta: (delta: GraphDelta) => void;
+  // Вызывается при каждом (пере)подключении: изменения, пропущенные
+  // во время разрыва соединения, не доставляются, поэтому граф нужно загрузить заново
+  onReady?: () => void;
+}
+
 export interface ProjectInfo {
   projectPath: string;
   projectName: string;
@@ -32,6 +142,52 @@ export interface FileNode {
   children?: FileNode[];
 }
 
+// Общее подключение к потоку изменений для всех подписчиков
+const graphUpdateListeners = new Set<GraphUpdateListener>();
+let graphEvents: EventSource | null = null;
+
+const openGraphEvents = () => {
+  const source = new EventSource(`${API_BASE_URL}/events`);
+
+  source.addEventListener('ready', () => {
+    graphUpdateListeners.forEach(listener => listener.onReady?.());
+  });
+
+  source.addEventListener('delta', event => {
+    const delta: GraphDelta = JSON.parse((event as MessageEvent).data);
+    graphUpdateListeners.forEach(listener => listener.onDelta(delta));
+  });
+
+  source.onerror = () => {
+    // Сервер запущен без -watch: переподключаться бессмысленно
+    if (source.readyState === EventSource.CLOSED) {
+      graphEvents = null;
+    }
+  };
+
+  return source;
+};
+
+// Применяет изменения к графу и возвращает новый граф
+export const applyGraphDelta = (graph: DependencyGraph, delta: GraphDelta): DependencyGraph => {
+  const edgeKey = (edge: Dependency) => `${edge.source}\u0000${edge.target}`;
+
+  const removedNodes = new Set(delta.removedNodes ?? []);
+  const changedNodes = new Map((delta.changedNodes ?? []).map(node => [node.id, node]));
+  const removedEdges = new Set((delta.removedEdges ?? []).map(edgeKey));
+
+  const nodes = graph.nodes
+    .filter(node => !removedNodes.has(node.id))
+    .map(node => changedNodes.get(node.id) ?? node)
+    .concat(delta.addedNodes ?? []);
+
+  const edges = graph.edges
+    .filter(edge => !removedEdges.has(edgeKey(edge)))
+    .concat(delta.addedEdges ?? []);
+
+  return { nodes, edges };
+};
+
 // Функции для работы с API
 export const api = {
   // Получение информации о проекте
@@ -61,6 +217,30 @@ export const api = {
     return response.json();
   },
 
+  // Получение графа импортов между файлами; kinds оставляет только импорты указанных видов
+  async getModuleGraph(kinds: ImportKind[] = []): Promise<ModuleGraph> {
+    const params = new URLSearchParams();
+    kinds.forEach(kind => params.append('kind', kind));
+
+    const response = await fetch(`${API_BASE_URL}/module-graph?${params}`);
+    if (!response.ok) {
+      throw new Error('Не удалось загрузить граф модулей');
+    }
+    return response.json();
+  },
+
+  // Получение сгруппированного графа; группы из expand раскрываются в константы
+  async getAggregateGraph(by: AggregateBy, depth = 1, expand: string[] = []): Promise<AggregateGraph> {
+    const params = new URLSearchParams({ by, depth: String(depth) });
+    expand.forEach(group => params.append('expand', group));
+
+    const response = await fetch(`${API_BASE_URL}/aggregate?${params}`);
+    if (!response.ok) {
+      throw new Error('Не удалось загрузить сгруппированный граф');
+    }
+    return response.json();
+  },
+
   // Получение зависимостей для конкретного файла
   async getFileDependencies(filePath: string): Promise<DependencyGraph> {
     const response = await fetch(`${API_BASE_URL}/file-dependencies`, {
@@ -75,6 +255,22 @@ export const api = {
       throw new Error('Не удалось загрузить данные зависимостей для файла');
     }
     return response.json();
+  },
+
+  // Подписка на изменения графа. Возвращает функцию для отписки
+  subscribeToGraphUpdates(listener: GraphUpdateListener): () => void {
+    graphUpdateListeners.add(listener);
+    if (!graphEvents) {
+      graphEvents = openGraphEvents();
+    }
+
+    return () => {
+      graphUpdateListeners.delete(listener);
+      if (graphUpdateListeners.size === 0 && graphEvents) {
+        graphEvents.close();
+        graphEvents = null;
+      }
+    };
   }
 };
 
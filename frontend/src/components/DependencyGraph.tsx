@@ -2,7 +2,7 @@ import { useState, useEffect } from 'react';
 import styled from 'styled-components';
 import GraphCanvas from './graph/GraphCanvas';
 import { GraphData, D3Node } from './graph/types';
-import { api, DependencyGraph as DependencyGraphType } from '../api/api';
+import { api, applyGraphDelta, DependencyGraph as DependencyGraphType } from '../api/api';
 
 const GraphContainer = styled.div`
   flex: 1;
@@ -63,17 +63,25 @@ const LoadingSpinner = styled.div`
   }
 `;
 
-// Функция для преобразования данных из API в формат для D3
-const transformData = (data: DependencyGraphType): GraphData => {
-  const nodes: D3Node[] = data.nodes.map(node => ({
-    id: node.name,
-    name: node.name,
-    type: node.type,
-    value: node.value,
-    filePath: node.filePath,
-    lineNum: node.lineNum,
-    color: getNodeColor(node.type)
-  }));
+// Функция для преобразования данных из API в формат для D3.
+// Узлы, которые уже были на графе, сохраняют свои координаты
+const transformData = (data: DependencyGraphType, previous?: GraphData | null): GraphData => {
+  const positions = new Map((previous?.nodes ?? []).map(node => [node.id, node]));
+
+  const nodes: D3Node[] = data.nodes.map(node => {
+    const old = positions.get(node.id);
+    return {
+      id: node.id,
+      name: node.name,
+      type: node.type,
+      value: node.value,
+      filePath: node.filePath,
+      lineNum: node.lineNum,
+      color: getNodeColor(node.type),
+      x: old?.x,
+      y: old?.y
+    };
+  });
 
   const links = data.edges.map(edge => ({
     source: edge.source,
@@ -83,7 +91,7 @@ const transformData = (data: DependencyGraphType): GraphData => {
   return { nodes, links };
 };
 
-// Определяем цвет узла в зависимости от типа константы
+// Определяем цвет узла в зависимости от типа константы или вида символа
 const getNodeColor = (type: string): string => {
   switch (type) {
     case 'string':
@@ -96,6 +104,14 @@ const getNodeColor = (type: string): string => {
       return '#cc00cc'; // фиолетовый
     case 'array':
       return '#ff3333'; // красный
+    case 'function':
+      return '#00b3b3'; // бирюзовый
+    case 'class':
+      return '#996633'; // коричневый
+    case 'enum':
+    case 'interface':
+    case 'type':
+      return '#6677aa'; // серо-синий для типов TypeScript
     default:
       return '#aaaaaa'; // серый для неизвестных типов
   }
@@ -106,11 +122,19 @@ interface DependencyGraphProps {
 }
 
 const DependencyGraph: React.FC<DependencyGraphProps> = ({ selectedFile }) => {
+  const [graph, setGraph] = useState<DependencyGraphType | null>(null);
   const [graphData, setGraphData] = useState<GraphData | null>(null);
   const [loading, setLoading] = useState<boolean>(true);
   const [error, setError] = useState<string | null>(null);
   const [hoveredNode, setHoveredNode] = useState<D3Node | null>(null);
 
+  // Пересчитываем данные для D3 при каждом изменении графа
+  useEffect(() => {
+    if (graph) {
+      setGraphData(previous => transformData(graph, previous));
+    }
+  }, [graph]);
+
   // Загружаем данные о зависимостях при изменении выбранного файла
   useEffect(() => {
     if (!selectedFile) {
@@ -122,15 +146,44 @@ const DependencyGraph: React.FC<DependencyGraphProps> = ({ selectedFile }) => {
     }
   }, [selectedFile]);
 
+  // Обновляем открытый граф по изменениям с сервера без перезагрузки страницы
+  useEffect(() => {
+    let connected = false;
+
+    return api.subscribeToGraphUpdates({
+      onDelta: delta => {
+        if (!selectedFile) {
+          setGraph(previous => previous && applyGraphDelta(previous, delta));
+        } else {
+          // Соседние константы файла вычисляет сервер, поэтому подграф загружаем заново
+          fetchFileGraph(selectedFile, true);
+        }
+      },
+      onReady: () => {
+        // После переподключения загружаем граф заново: изменения могли быть пропущены
+        if (connected) {
+          if (selectedFile) {
+            fetchFileGraph(selectedFile, true);
+          } else {
+            fetchFullGraph(true);
+          }
+        }
+        connected = true;
+      }
+    });
+  }, [selectedFile]);
+
   // Загружает полный граф зависимостей
-  const fetchFullGraph = async () => {
-    setLoading(true);
+  const fetchFullGraph = async (silent = false) => {
+    if (!silent) {
+      setLoading(true);
+      setGraphData(null);
+    }
     setError(null);
 
     try {
       const data = await api.getDependencyGraph();
-      const transformedData = transformData(data);
-      setGraphData(transformedData);
+      setGraph(data);
     } catch (err) {
       console.error('Error fetching graph data:', err);
       setError('Ошибка при загрузке графа зависимостей');
@@ -140,14 +193,16 @@ const DependencyGraph: React.FC<DependencyGraphProps> = ({ selectedFile }) => {
   };
 
   // Загружает граф зависимостей для конкретного файла
-  const fetchFileGraph = async (filePath: string) => {
-    setLoading(true);
+  const fetchFileGraph = async (filePath: string, silent = false) => {
+    if (!silent) {
+      setLoading(true);
+      setGraphData(null);
+    }
     setError(null);
 
     try {
       const data = await api.getFileDependencies(filePath);
-      const transformedData = transformData(data);
-      setGraphData(transformedData);
+      setGraph(data);
     } catch (err) {
       console.error('Error fetching file dependencies:', err);
       setError('Ошибка при загрузке зависимостей для файла');
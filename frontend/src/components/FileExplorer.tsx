@@ -90,6 +90,33 @@ const FileExplorer: React.FC<FileExplorerProps> = ({ onFileSelect }) => {
     fetchData();
   }, []);
 
+  // Обновляем дерево файлов, когда на сервере появляются или удаляются файлы
+  useEffect(() => {
+    let connected = false;
+
+    const refreshTree = async () => {
+      try {
+        setFileTree(await api.getFileTree());
+      } catch (err) {
+        console.error('Error refreshing file tree:', err);
+      }
+    };
+
+    return api.subscribeToGraphUpdates({
+      onDelta: delta => {
+        if (delta.addedFiles?.length || delta.removedFiles?.length) {
+          refreshTree();
+        }
+      },
+      onReady: () => {
+        if (connected) {
+          refreshTree();
+        }
+        connected = true;
+      }
+    });
+  }, []);
+
   const handleSelectNode = (node: FileNode) => {
     setSelectedNode(node);
 
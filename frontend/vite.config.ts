@@ -1,7 +1,17 @@
-import { defineConfig } from 'vite'
+import { defineConfig, loadEnv } from 'vite'
 import react from '@vitejs/plugin-react'
 
 // https://vite.dev/config/
-export default defineConfig({
-  plugins: [react()],
+export default defineConfig(({ mode }) => {
+  // Адрес бэкенда для dev-сервера, например DEPGRAPH_API_URL=http://localhost:9090
+  const env = loadEnv(mode, '.', 'DEPGRAPH_')
+
+  return {
+    plugins: [react()],
+    server: {
+      proxy: {
+        '/api': env.DEPGRAPH_API_URL ?? 'http://localhost:8080',
+      },
+    },
+  }
 })